@@ -25,31 +25,48 @@ func main() {
 	app.UsageText = "kpm  <command> [arguments]..."
 	app.Commands = []*cli.Command{
 		cmd.NewInitCmd(kpmcli),
-		cmd.NewGraphCmd(kpmcli),
-		cmd.NewAddCmd(kpmcli),
-		cmd.NewPkgCmd(kpmcli),
-		cmd.NewMetadataCmd(kpmcli),
+		cmd.InWorkspace(kpmcli, cmd.NewGraphCmd(kpmcli)),
+		cmd.InWorkspace(kpmcli, cmd.NewWhyCmd(kpmcli)),
+		cmd.NewOutdatedCmd(kpmcli),
+		cmd.InWorkspace(kpmcli, cmd.NewAddCmd(kpmcli)),
+		cmd.InWorkspace(kpmcli, cmd.NewRemoveCmd(kpmcli)),
+		cmd.InWorkspace(kpmcli, cmd.NewTidyCmd(kpmcli)),
+		cmd.InWorkspace(kpmcli, cmd.NewPkgCmd(kpmcli)),
+		cmd.InWorkspace(kpmcli, cmd.NewMetadataCmd(kpmcli)),
 		cmd.NewImportCmd(kpmcli),
 
 		// todo: The following commands are bound to the oci registry.
 		// Refactor them to compatible with the other registry.
-		cmd.NewRunCmd(kpmcli),
+		cmd.InWorkspace(kpmcli, cmd.NewRunCmd(kpmcli)),
 		cmd.NewLoginCmd(kpmcli),
 		cmd.NewLogoutCmd(kpmcli),
-		cmd.NewPushCmd(kpmcli),
+		cmd.InWorkspace(kpmcli, cmd.NewPushCmd(kpmcli)),
 		cmd.NewPullCmd(kpmcli),
-		cmd.NewUpdateCmd(kpmcli),
+		cmd.NewSearchCmd(kpmcli),
+		cmd.NewInfoCmd(kpmcli),
+		cmd.NewDeprecateCmd(kpmcli),
+		cmd.NewYankCmd(kpmcli),
+		cmd.NewSignCmd(kpmcli),
+		cmd.InWorkspace(kpmcli, cmd.NewSbomCmd(kpmcli)),
+		cmd.NewVerifyProvenanceCmd(kpmcli),
+		cmd.InWorkspace(kpmcli, cmd.NewUpdateCmd(kpmcli)),
+		cmd.NewVerifyCmd(kpmcli),
 	}
 	app.Flags = []cli.Flag{
 		&cli.BoolFlag{
 			Name:  cmd.FLAG_QUIET,
 			Usage: "push in vendor mode",
 		},
+		&cli.BoolFlag{
+			Name:  cmd.FLAG_LOCKED,
+			Usage: "fail if kcl.mod.lock needs to be updated",
+		},
 	}
 	app.Before = func(c *cli.Context) error {
 		if c.Bool(cmd.FLAG_QUIET) {
 			kpmcli.SetLogWriter(nil)
 		}
+		kpmcli.SetLocked(c.Bool(cmd.FLAG_LOCKED))
 		return nil
 	}
 	err = app.Run(os.Args)
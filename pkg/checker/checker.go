@@ -10,7 +10,6 @@ import (
 	"oras.land/oras-go/v2"
 	remoteauth "oras.land/oras-go/v2/registry/remote/auth"
 
-	"kcl-lang.io/kpm/pkg/constants"
 	"kcl-lang.io/kpm/pkg/downloader"
 	"kcl-lang.io/kpm/pkg/oci"
 	"kcl-lang.io/kpm/pkg/opt"
@@ -138,13 +137,23 @@ func (sc *SumChecker) Check(kclPkg pkg.KclPkg) error {
 		if err != nil {
 			return fmt.Errorf("failed to get checksum from trusted source: %w", err)
 		}
-		if dep.Sum != trustedSum {
+		if !sumMatches(dep, trustedSum) {
 			return fmt.Errorf("checksum verification failed for '%s': expected '%s', got '%s'", dep.Name, trustedSum, dep.Sum)
 		}
 	}
 	return nil
 }
 
+// sumMatches checks whether the checksum of the dependency matches the trusted checksum.
+// The checksums computed by different algorithms are not comparable,
+// so the trusted checksum is verified against the content of the dependency if it exists locally.
+func sumMatches(dep pkg.Dependency, trustedSum string) bool {
+	if utils.SameSumAlgorithm(dep.Sum, trustedSum) || len(dep.LocalFullPath) == 0 {
+		return dep.Sum == trustedSum
+	}
+	return utils.CheckPackageSum(dep.Sum, dep.LocalFullPath) && utils.CheckPackageSum(trustedSum, dep.LocalFullPath)
+}
+
 // isValidDependencyName checks whether the given dependency name is valid.
 func isValidDependencyName(name string) bool {
 	validNamePattern := `^[a-zA-Z][a-zA-Z0-9_\-\.]*[a-zA-Z0-9_]$`
@@ -181,7 +190,7 @@ func (sc *SumChecker) populateOciFields(dep pkg.Dependency) {
 	}
 
 	if len(dep.Source.Oci.Repo) == 0 {
-		dep.Source.Oci.Repo = utils.JoinPath(sc.settings.DefaultOciRepo(), dep.Name)
+		dep.Source.Oci.Repo = utils.JoinPath(sc.settings.DefaultOciRepo(), dep.PkgName())
 	}
 }
 
@@ -249,7 +258,7 @@ func (sc *SumChecker) GetCredentials(hostName string) (*remoteauth.Credential, e
 
 // extractChecksumFromManifest extracts the checksum from the OCI manifest.
 func (sc *SumChecker) extractChecksumFromManifest(manifest ocispec.Manifest) (string, error) {
-	if value, ok := manifest.Annotations[constants.DEFAULT_KCL_OCI_MANIFEST_SUM]; ok {
+	if value, ok := pkg.SumFromManifest(manifest.Annotations); ok {
 		return value, nil
 	}
 	return "", fmt.Errorf("checksum annotation not found in manifest")
@@ -0,0 +1,196 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+	"kcl-lang.io/kpm/pkg/constants"
+	"kcl-lang.io/kpm/pkg/downloader"
+	"kcl-lang.io/kpm/pkg/mock"
+	"kcl-lang.io/kpm/pkg/opt"
+	pkg "kcl-lang.io/kpm/pkg/package"
+	"kcl-lang.io/kpm/pkg/utils"
+)
+
+func TestResolveAliasedDeps(t *testing.T) {
+	testDir := getTestDir("test_alias")
+	pkgPath := filepath.Join(testDir, "pkg")
+	modPath := filepath.Join(pkgPath, "kcl.mod")
+
+	expectedMod, err := os.ReadFile(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.WriteFile(modPath, expectedMod, 0644)
+		_ = os.RemoveAll(filepath.Join(pkgPath, "kcl.mod.lock"))
+	}()
+
+	kpmcli, err := NewKpmClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Two versions of the package 'dep' are depended on under 'dep' and 'dep_old'.
+	kpkg, err := kpmcli.LoadPkgFromPath(pkgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkgMap, err := kpmcli.ResolveDepsIntoMap(kpkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, len(pkgMap), 2)
+	assert.Equal(t, pkgMap["dep"], filepath.Join(testDir, "dep_v2"))
+	assert.Equal(t, pkgMap["dep_old"], filepath.Join(testDir, "dep_v1"))
+
+	depOld, ok := kpkg.Dependencies.Deps.Get("dep_old")
+	assert.Equal(t, ok, true)
+	assert.Equal(t, depOld.Version, "1.0.0")
+	assert.Equal(t, depOld.PkgName(), "dep")
+
+	gotMod, err := os.ReadFile(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(gotMod), string(expectedMod))
+}
+
+func TestAliasedRegistryDepSource(t *testing.T) {
+	kpmcli, err := NewKpmClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedRepo := utils.JoinPath(kpmcli.GetSettings().DefaultOciRepo(), "k8s")
+
+	// The package depended on under an alias is fetched from the repo of the package.
+	modFile, _, err := pkg.ParseModFile("[package]\nname = \"pkg\"\nversion = \"0.0.1\"\n\n[dependencies]\nk8s_old = { package = \"k8s\", version = \"1.27\" }\n", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	k8sOld, ok := modFile.Deps.Get("k8s_old")
+	assert.Equal(t, ok, true)
+	assert.Equal(t, k8sOld.Source.Oci.Repo, expectedRepo)
+
+	dep := pkg.Dependency{
+		Name:    "k8s_old",
+		Version: "1.27",
+		Source: downloader.Source{
+			ModSpec: &downloader.ModSpec{Name: "k8s", Version: "1.27"},
+			Oci:     &downloader.Oci{Tag: "1.27"},
+		},
+	}
+	err = kpmcli.FillDepInfo(&dep, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, dep.Source.Oci.Repo, expectedRepo)
+
+	// The aliased dependency only locked in kcl.mod.lock, e.g. required by another dependency,
+	// is fetched from the repo of the package recorded in the full name.
+	pkgPath := t.TempDir()
+	err = os.WriteFile(filepath.Join(pkgPath, "kcl.mod"), []byte("[package]\nname = \"pkg\"\nedition = \"v0.10.0\"\nversion = \"0.0.1\"\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(pkgPath, "kcl.mod.lock"), []byte(`[dependencies]
+  [dependencies.k8s_old]
+    name = "k8s_old"
+    full_name = "k8s_1.27"
+    version = "1.27"
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kpkg, err := kpmcli.LoadPkgFromPath(pkgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lockedDep, ok := kpkg.Dependencies.Deps.Get("k8s_old")
+	assert.Equal(t, ok, true)
+	assert.Equal(t, lockedDep.PkgName(), "k8s")
+	assert.Equal(t, lockedDep.Source.Oci.Repo, expectedRepo)
+	assert.Equal(t, lockedDep.Source.ModSpec.Name, "k8s")
+}
+
+func TestAddOciDepWithRename(t *testing.T) {
+	registry := mock.NewOciRegistry()
+	defer registry.Close()
+
+	tarPath := filepath.Join(t.TempDir(), "dep_1.0.0.tar")
+	err := utils.TarDir(filepath.Join(getTestDir("test_alias"), "dep_v1"), tarPath, []string{}, []string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tarContent, err := os.ReadFile(tarPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = registry.AddPackage("test/dep", "1.0.0", v1.MediaTypeImageConfig, []byte("{}"), tarContent, map[string]string{
+		constants.DEFAULT_KCL_OCI_MANIFEST_NAME:    "dep",
+		constants.DEFAULT_KCL_OCI_MANIFEST_VERSION: "1.0.0",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkgPath := filepath.Join(t.TempDir(), "pkg")
+	if err := os.MkdirAll(pkgPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(pkgPath, "kcl.mod"), []byte("[package]\nname = \"pkg\"\nedition = \"v0.10.0\"\nversion = \"0.0.1\"\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(pkgPath, "main.k"), []byte("import dep_old\n\nb = dep_old.a\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kpmcli, err := NewKpmClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	kpmcli.SetHomePath(t.TempDir())
+
+	kpkg, err := kpmcli.LoadPkgFromPath(pkgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 'kpm add oci://<registry>/test/dep?tag=1.0.0 --rename dep_old'
+	_, err = kpmcli.AddDepWithOpts(kpkg, &opt.AddOptions{
+		LocalPath:  pkgPath,
+		NewPkgName: "dep_old",
+		RegistryOpts: opt.RegistryOptions{
+			Oci: &opt.OciOptions{Reg: registry.Host(), Repo: "test/dep", Tag: "1.0.0", Ref: "dep"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The package from the oci url is depended on under the new name, and still pulled from its repo.
+	kpkg, err = kpmcli.LoadPkgFromPath(pkgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, ok := kpkg.ModFile.Deps.Get("dep")
+	assert.Equal(t, ok, false)
+	modDep, ok := kpkg.ModFile.Deps.Get("dep_old")
+	assert.Equal(t, ok, true)
+	assert.Equal(t, modDep.Source.Oci.Repo, "test/dep")
+	lockedDep, ok := kpkg.Dependencies.Deps.Get("dep_old")
+	assert.Equal(t, ok, true)
+	assert.Equal(t, lockedDep.Version, "1.0.0")
+	assert.Equal(t, lockedDep.PkgName(), "dep")
+
+	pkgMap, err := kpmcli.ResolveDepsIntoMap(kpkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, ok = pkgMap["dep_old"]
+	assert.Equal(t, ok, true)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/BurntSushi/toml"
 	"github.com/dominikbraun/graph"
 	"github.com/elliotchance/orderedmap/v2"
+	"github.com/opencontainers/go-digest"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"golang.org/x/mod/module"
 	"kcl-lang.io/kcl-go/pkg/kcl"
@@ -30,6 +31,7 @@ import (
 	pkg "kcl-lang.io/kpm/pkg/package"
 	"kcl-lang.io/kpm/pkg/reporter"
 	"kcl-lang.io/kpm/pkg/runner"
+	"kcl-lang.io/kpm/pkg/semver"
 	"kcl-lang.io/kpm/pkg/settings"
 	"kcl-lang.io/kpm/pkg/utils"
 	"kcl-lang.io/kpm/pkg/visitor"
@@ -51,6 +53,12 @@ type KpmClient struct {
 	noSumCheck bool
 	// The flag of whether to skip the verification of TLS.
 	insecureSkipTLSverify bool
+	// The flag of whether kcl.mod.lock is not allowed to be changed.
+	locked bool
+	// The workspace of the packages developed together.
+	workspace *pkg.Workspace
+	// The workspace members indexed by the package names.
+	workspaceMembers map[string]*pkg.KclPkg
 }
 
 // NewKpmClient will create a new kpm client with default settings.
@@ -84,6 +92,17 @@ func (c *KpmClient) SetNoSumCheck(noSumCheck bool) {
 	c.noSumCheck = noSumCheck
 }
 
+// SetLocked will set the 'locked' flag.
+// Under the locked mode, the operations fail if they would change kcl.mod.lock.
+func (c *KpmClient) SetLocked(locked bool) {
+	c.locked = locked
+}
+
+// GetLocked will return the 'locked' flag.
+func (c *KpmClient) GetLocked() bool {
+	return c.locked
+}
+
 // GetCredsClient will return the credential client.
 func (c *KpmClient) GetCredsClient() (*downloader.CredClient, error) {
 	if c.credsClient == nil {
@@ -145,10 +164,15 @@ func (c *KpmClient) GetSettings() *settings.Settings {
 }
 
 func (c *KpmClient) LoadPkgFromPath(path string) (*pkg.KclPkg, error) {
-	return pkg.LoadKclPkgWithOpts(
+	kpkg, err := pkg.LoadKclPkgWithOpts(
 		pkg.WithPath(path),
 		pkg.WithSettings(&c.settings),
 	)
+	if err != nil {
+		return nil, err
+	}
+	kpkg.Locked = c.locked
+	return kpkg, nil
 }
 
 func (c *KpmClient) LoadModFile(path string) (*pkg.ModFile, error) {
@@ -177,7 +201,7 @@ func (c *KpmClient) AcquireDepSum(dep pkg.Dependency) (string, error) {
 		}
 
 		if len(dep.Source.Oci.Repo) == 0 {
-			urlpath := utils.JoinPath(c.GetSettings().DefaultOciRepo(), dep.Name)
+			urlpath := utils.JoinPath(c.GetSettings().DefaultOciRepo(), dep.PkgName())
 			dep.Source.Oci.Repo = urlpath
 		}
 		// Fetch the metadata of the OCI manifest.
@@ -187,7 +211,7 @@ func (c *KpmClient) AcquireDepSum(dep pkg.Dependency) (string, error) {
 			OciOptions: opt.OciOptions{
 				Reg:  dep.Source.Oci.Reg,
 				Repo: dep.Source.Oci.Repo,
-				Tag:  dep.Source.Oci.Tag,
+				Tag:  dep.Source.Oci.Reference(),
 			},
 		})
 
@@ -201,7 +225,7 @@ func (c *KpmClient) AcquireDepSum(dep pkg.Dependency) (string, error) {
 		}
 
 		// Check the dependency checksum.
-		if value, ok := manifest.Annotations[constants.DEFAULT_KCL_OCI_MANIFEST_SUM]; ok {
+		if value, ok := pkg.SumFromManifest(manifest.Annotations); ok {
 			return value, nil
 		}
 	}
@@ -255,6 +279,18 @@ func (c *KpmClient) getDepStorePath(search_path string, d *pkg.Dependency, isVen
 // Since redownloads are not triggered if local dependencies exists,
 // indirect dependencies are also synchronized to the lock file by `lockDeps`.
 func (c *KpmClient) ResolvePkgDepsMetadata(kclPkg *pkg.KclPkg, update bool) error {
+	// The package may be loaded without the client, e.g. by the visitor of 'kpm run'.
+	if c.locked {
+		kclPkg.Locked = true
+	}
+	// Under the locked mode, fail before downloading anything if kcl.mod.lock is not consistent with kcl.mod.
+	if kclPkg.Locked {
+		err := c.checkLockedDeps(kclPkg)
+		if err != nil {
+			return err
+		}
+	}
+
 	if kclPkg.IsVendorMode() {
 		// In the vendor mode, the search path is the vendor subdirectory of the current package.
 		err := c.VendorDeps(kclPkg)
@@ -263,7 +299,7 @@ func (c *KpmClient) ResolvePkgDepsMetadata(kclPkg *pkg.KclPkg, update bool) erro
 		}
 	} else {
 		// In the non-vendor mode, the search path is the KCL_PKG_PATH.
-		err := c.resolvePkgDeps(kclPkg, &kclPkg.Dependencies, update)
+		err := c.resolvePkgDeps(kclPkg, &kclPkg.Dependencies, update, true)
 		if err != nil {
 			return err
 		}
@@ -272,10 +308,17 @@ func (c *KpmClient) ResolvePkgDepsMetadata(kclPkg *pkg.KclPkg, update bool) erro
 	return nil
 }
 
-func (c *KpmClient) resolvePkgDeps(kclPkg *pkg.KclPkg, lockDeps *pkg.Dependencies, update bool) error {
+// resolvePkgDeps resolves the dependencies of the package and records them into 'lockDeps'.
+// The dev-dependencies are resolved only if 'withDevDeps' is true, i.e. the package is not consumed as a dependency.
+func (c *KpmClient) resolvePkgDeps(kclPkg *pkg.KclPkg, lockDeps *pkg.Dependencies, update bool, withDevDeps bool) error {
 	var searchPath string
 	kclPkg.NoSumCheck = c.noSumCheck
 
+	modDeps := &kclPkg.ModFile.Dependencies
+	if withDevDeps {
+		modDeps = kclPkg.ModFile.DepsWithDevDeps()
+	}
+
 	// If under the mode of '--no_sum_check', the checksum of the package will not be checked.
 	// There is no kcl.mod.lock, and the dependencies in kcl.mod and kcl.mod.lock do not need to be aligned.
 	if !c.noSumCheck {
@@ -290,25 +333,34 @@ func (c *KpmClient) resolvePkgDeps(kclPkg *pkg.KclPkg, lockDeps *pkg.Dependencie
 			if !ok {
 				break
 			}
-			modDep, ok := kclPkg.ModFile.Dependencies.Deps.Get(name)
+			modDep, ok := modDeps.Deps.Get(name)
+			// If the dependency is required by a version range in kcl.mod,
+			// the locked version is kept as long as it is in the range.
+			if ok && modDep.Source.IsVersionRange() && lockedVersionInRange(kclPkg, &dep) {
+				modDep = modDep.WithExactVersion(dep.Version)
+			}
 			if !ok || !dep.Equals(modDep) {
 				kclPkg.Dependencies.Deps.Delete(name)
 			}
 		}
 		// add the dependencies in kcl.mod which not in kcl.mod.lock
-		for _, name := range kclPkg.ModFile.Dependencies.Deps.Keys() {
-			d, ok := kclPkg.ModFile.Dependencies.Deps.Get(name)
+		for _, name := range modDeps.Deps.Keys() {
+			d, ok := modDeps.Deps.Get(name)
 			if !ok {
 				break
 			}
 			if _, ok := kclPkg.Dependencies.Deps.Get(name); !ok {
-				kclPkg.Dependencies.Deps.Set(name, d)
+				exactDep, err := c.resolveDepVersionRange(&d, lockDeps)
+				if err != nil {
+					return err
+				}
+				kclPkg.Dependencies.Deps.Set(name, *exactDep)
 			}
 		}
 	} else {
 		// If under the mode of '--no_sum_check', the checksum of the package will not be checked.
 		// All the dependencies in kcl.mod are the dependencies of the current package.
-		kclPkg.Dependencies.Deps = kclPkg.ModFile.Dependencies.Deps
+		kclPkg.Dependencies.Deps = modDeps.Deps
 	}
 
 	for _, name := range kclPkg.Dependencies.Deps.Keys() {
@@ -316,10 +368,22 @@ func (c *KpmClient) resolvePkgDeps(kclPkg *pkg.KclPkg, lockDeps *pkg.Dependencie
 		if !ok {
 			break
 		}
+		// The dependency redirected by the [replace] section in kcl.mod is loaded from the replacement.
+		lockedDep := d
+		replacedDep, isReplaced := lockDeps.Replaces.ReplaceDep(&d)
+		if isReplaced {
+			d = *replacedDep
+		}
 		searchPath = c.getDepStorePath(kclPkg.HomePath, &d, kclPkg.IsVendorMode())
 		depPath := searchPath
-		// if the dependency is not exist
-		if !utils.DirExists(searchPath) {
+		// The dependency on the workspace member uses the local copy of the member.
+		memberPath, isMember := c.workspaceMember(&d)
+		if isMember {
+			searchPath = memberPath
+			depPath = memberPath
+		}
+		// if the dependency is not exist, or the git branch is not pinned to a commit yet.
+		if !utils.DirExists(searchPath) || (update && !isMember && d.IsUnpinnedGitBranch()) {
 			if d.IsFromLocal() {
 				// If the dependency is from the local path, and it does not exist locally, raise an error
 				return reporter.NewErrorEvent(reporter.DependencyNotFound, fmt.Errorf("dependency '%s' not found in '%s'", d.Name, searchPath))
@@ -333,11 +397,15 @@ func (c *KpmClient) resolvePkgDeps(kclPkg *pkg.KclPkg, lockDeps *pkg.Dependencie
 							return err
 						}
 					} else {
-						// re-download it.
-						err := c.AddDepToPkg(kclPkg, &d)
+						// re-download it, the replacement is applied during downloading.
+						err := c.AddDepToPkg(kclPkg, &lockedDep)
 						if err != nil {
 							return err
 						}
+						// The git branch is pinned to the commit resolved during downloading.
+						if relockedDep, ok := kclPkg.Dependencies.Deps.Get(name); ok && !isReplaced {
+							d.PinGitBranch(&relockedDep)
+						}
 
 						depPath = c.getDepStorePath(kclPkg.HomePath, &d, kclPkg.IsVendorMode())
 					}
@@ -347,7 +415,7 @@ func (c *KpmClient) resolvePkgDeps(kclPkg *pkg.KclPkg, lockDeps *pkg.Dependencie
 			}
 		}
 
-		if d.GetPackage() != "" {
+		if d.GetPackage() != "" && !isMember {
 			depPath, _ = utils.FindPackage(depPath, d.GetPackage())
 		}
 
@@ -360,29 +428,43 @@ func (c *KpmClient) resolvePkgDeps(kclPkg *pkg.KclPkg, lockDeps *pkg.Dependencie
 				// todo: add command to clean the package cache
 			)
 		}
+		// The member without the checksum locked for its version is kept out of kcl.mod.lock.
+		if isMember {
+			d.FromWorkspace = len(d.Sum) == 0 || d.Version != depPkg.GetPkgVersion()
+		}
 		d.FromKclPkg(depPkg)
-		err = c.resolvePkgDeps(depPkg, lockDeps, update)
+		// The checksum of the git dependency computed by the algorithm before 'h1:' is migrated,
+		// and the 'h1:' checksum is written when kcl.mod.lock is rewritten.
+		if d.Source.Git != nil && !isMember {
+			d.MigrateSum(searchPath)
+		}
+		// The kcl.mod.lock of the dependency in the package cache is not the one locked by the user.
+		depPkg.Locked = false
+		err = c.resolvePkgDeps(depPkg, lockDeps, update, false)
 		if err != nil {
 			return err
 		}
 		if d.Source.Git != nil && d.Source.Git.GetPackage() != "" {
 			if d.Source.Git != nil && d.Source.Git.GetPackage() != "" {
 				name := utils.ParseRepoNameFromGitUrl(d.Source.Git.Url)
-				if len(d.Source.Git.Tag) != 0 {
-					d.FullName = fmt.Sprintf(PKG_NAME_PATTERN, name, d.Source.Git.Tag)
-				} else if len(d.Source.Git.Commit) != 0 {
-					d.FullName = fmt.Sprintf(PKG_NAME_PATTERN, name, d.Source.Git.Commit)
-				} else {
-					d.FullName = fmt.Sprintf(PKG_NAME_PATTERN, name, d.Source.Git.Branch)
-				}
+				d.FullName = fmt.Sprintf(PKG_NAME_PATTERN, name, d.Source.Git.GetPinnedReference())
 			}
 		}
+		if isReplaced {
+			d = lockedDep.RecordReplace(&d)
+		}
+		// The dependency is required by the current package besides the dependents already locked.
+		if lockDep, ok := lockDeps.Deps.Get(name); ok {
+			d.Dependents = lockDep.Dependents
+			d.KeepProvenance(&lockDep)
+		}
+		d.AddDependents(kclPkg.GetPkgName())
 		kclPkg.Dependencies.Deps.Set(name, d)
 		lockDeps.Deps.Set(name, d)
 	}
 
 	// Generate file kcl.mod.lock.
-	if kclPkg.ModFile.Dependencies.Deps.Len() > 0 && !kclPkg.NoSumCheck || !update {
+	if modDeps.Deps.Len() > 0 && !kclPkg.NoSumCheck || !update {
 		err := kclPkg.LockDepsVersion()
 		if err != nil {
 			return err
@@ -411,6 +493,8 @@ func GetReleasesFromSource(sourceType, uri string) ([]string, error) {
 
 // UpdateDeps will update the dependencies.
 func (c *KpmClient) UpdateDeps(kclPkg *pkg.KclPkg) error {
+	// The git branches are resolved to the latest commits again.
+	kclPkg.Dependencies.UnpinGitBranches()
 	_, err := c.ResolveDepsMetadataInJsonStr(kclPkg, true)
 	if err != nil {
 		return err
@@ -476,6 +560,38 @@ func (c *KpmClient) Compile(kclPkg *pkg.KclPkg, kclvmCompiler *runner.Compiler)
 	return kclvmCompiler.Run()
 }
 
+// checkLockedDeps will check that every dependency in kcl.mod is locked in kcl.mod.lock
+// with the version required by kcl.mod, and return the differences as an error if not.
+func (c *KpmClient) checkLockedDeps(kclPkg *pkg.KclPkg) error {
+	var diffs []string
+	modDeps := kclPkg.ModFile.DepsWithDevDeps()
+	for _, name := range modDeps.Deps.Keys() {
+		modDep, ok := modDeps.Deps.Get(name)
+		if !ok {
+			return fmt.Errorf("failed to get dependency %s", name)
+		}
+		lockedDep, ok := kclPkg.Dependencies.Deps.Get(name)
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("+ %s %s (missing from kcl.mod.lock)", name, modDep.Version))
+			continue
+		}
+		if modDep.Source.IsVersionRange() {
+			if !lockedVersionInRange(kclPkg, &lockedDep) {
+				diffs = append(diffs, fmt.Sprintf("~ %s: locked version %s is out of the range %s", name, lockedDep.Version, modDep.Source.VersionRange()))
+			}
+			continue
+		}
+		if !lockedDep.Equals(modDep) {
+			diffs = append(diffs, fmt.Sprintf("~ %s: version %s -> %s", name, lockedDep.Version, modDep.Version))
+		}
+	}
+
+	if len(diffs) != 0 {
+		return pkg.NewLockFileChangedError(diffs)
+	}
+	return nil
+}
+
 // createIfNotExist will create a file if it does not exist.
 func (c *KpmClient) createIfNotExist(filepath string, storeFunc func() error) error {
 	reporter.ReportMsgTo(fmt.Sprintf("creating new :%s", filepath), c.GetLogWriter())
@@ -529,6 +645,15 @@ func (c *KpmClient) AddDepWithOpts(kclPkg *pkg.KclPkg, opt *opt.AddOptions) (*pk
 		return nil, err
 	}
 
+	// The package is depended on under the new name,
+	// e.g. 'k8s_old = { package = "k8s", version = "1.27" }' with '--rename k8s_old'.
+	// The package from the oci or git url is still pulled from the url under the new name,
+	// e.g. 'k8s_old = { oci = "oci://ghcr.io/kcl-lang/k8s", tag = "1.27" }'.
+	if opt.NewPkgName != "" {
+		aliasedDep := d.WithAlias(opt.NewPkgName)
+		d = &aliasedDep
+	}
+
 	// Backup the dependency used in kcl.mod
 	if opt.RegistryOpts.Registry != nil {
 		kclPkg.BackupDepUI(d.Name, &pkg.Dependency{
@@ -536,7 +661,7 @@ func (c *KpmClient) AddDepWithOpts(kclPkg *pkg.KclPkg, opt *opt.AddOptions) (*pk
 			Version: d.Version,
 			Source: downloader.Source{
 				ModSpec: &downloader.ModSpec{
-					Name:    d.Name,
+					Name:    d.PkgName(),
 					Version: d.Version,
 				},
 			},
@@ -555,29 +680,6 @@ func (c *KpmClient) AddDepWithOpts(kclPkg *pkg.KclPkg, opt *opt.AddOptions) (*pk
 	}
 
 	// 3. update the kcl.mod and kcl.mod.lock.
-	if opt.NewPkgName != "" {
-		// update the kcl.mod with NewPkgName
-		tempDeps, ok := kclPkg.ModFile.Dependencies.Deps.Get(d.Name)
-		if !ok {
-			return nil, fmt.Errorf("dependency '%s' not found in 'kcl.mod'", d.Name)
-		}
-		tempDeps.Name = opt.NewPkgName
-		kclPkg.ModFile.Dependencies.Deps.Set(d.Name, tempDeps)
-
-		// update the kcl.mod.lock with NewPkgName
-		tempDeps, ok = kclPkg.Dependencies.Deps.Get(d.Name)
-		if !ok {
-			return nil, fmt.Errorf("dependency '%s' not found in 'kcl.mod.lock'", d.Name)
-		}
-		tempDeps.Name = opt.NewPkgName
-		tempDeps.FullName = opt.NewPkgName + "_" + tempDeps.Version
-		kclPkg.Dependencies.Deps.Set(d.Name, tempDeps)
-
-		// update the key of kclPkg.Dependencies.Deps from d.Name to opt.NewPkgName
-		kclPkg.Dependencies.Deps.Set(opt.NewPkgName, kclPkg.Dependencies.Deps.GetOrDefault(d.Name, pkg.TestPkgDependency))
-		kclPkg.Dependencies.Deps.Delete(d.Name)
-	}
-
 	if ok, err := features.Enabled(features.SupportMVS); err != nil && ok {
 		// After adding the new dependency,
 		// Iterate through all the dependencies and select the version by mvs
@@ -619,7 +721,14 @@ func (c *KpmClient) AddDepToPkg(kclPkg *pkg.KclPkg, d *pkg.Dependency) error {
 
 	// Some field will be empty when the dependency is add from CLI.
 	// For avoiding re-download the dependency, just complete part of the fields not all of them.
-	if !kclPkg.ModFile.Dependencies.Deps.GetOrDefault(d.Name, pkg.TestPkgDependency).Equals(*d) {
+	modDep := kclPkg.ModFile.Dependencies.Deps.GetOrDefault(d.Name, pkg.TestPkgDependency)
+	if modDep.Source.IsVersionRange() {
+		// the version range in kcl.mod is kept if the dep is in the range.
+		if inRange, err := semver.VersionInRange(d.Version, modDep.Source.VersionRange()); err == nil && inRange {
+			modDep = modDep.WithExactVersion(d.Version)
+		}
+	}
+	if !modDep.Equals(*d) {
 		// the dep passed on the cli is different from the kcl.mod.
 		kclPkg.ModFile.Dependencies.Deps.Set(d.Name, *d)
 	}
@@ -657,22 +766,86 @@ func (c *KpmClient) PackagePkg(kclPkg *pkg.KclPkg, vendorMode bool) (string, err
 
 // Package will package the current kcl package into a "*.tar" file into 'tarPath'.
 func (c *KpmClient) Package(kclPkg *pkg.KclPkg, tarPath string, vendorMode bool) error {
+	// The package may be loaded without the client, e.g. from a tar to be pushed.
+	if c.locked {
+		kclPkg.Locked = true
+	}
+	if kclPkg.Locked {
+		err := c.checkLockedDeps(kclPkg)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The dev-dependencies are not packaged, because they are not required by the consumers.
+	releasePkg, err := c.releasePkg(kclPkg)
+	if err != nil {
+		return err
+	}
+
 	// Vendor all the dependencies into the current kcl package.
 	if vendorMode {
-		err := c.VendorDeps(kclPkg)
+		err = c.VendorDeps(releasePkg)
 		if err != nil {
 			return reporter.NewErrorEvent(reporter.FailedVendor, err, "failed to vendor dependencies")
 		}
 	}
 
+	// kcl.mod and kcl.mod.lock without the dev-dependencies are packaged instead of the files in the package.
+	var releaseFiles map[string][]byte
+	if releasePkg != kclPkg {
+		modContent, lockContent, err := releasePkg.ReleaseModFiles()
+		if err != nil {
+			return reporter.NewErrorEvent(reporter.FailedPackage, err, "failed to package the kcl module")
+		}
+		releaseFiles = map[string][]byte{constants.KCL_MOD: modContent}
+		if lockContent != nil {
+			releaseFiles[constants.KCL_MOD_LOCK] = lockContent
+		}
+	}
+
 	// Tar the current kcl package into a "*.tar" file.
-	err := utils.TarDir(kclPkg.HomePath, tarPath, kclPkg.GetPkgInclude(), kclPkg.GetPkgExclude())
+	err = utils.TarDirWithFiles(kclPkg.HomePath, tarPath, kclPkg.GetPkgInclude(), kclPkg.GetPkgExclude(), releaseFiles)
 	if err != nil {
 		return reporter.NewErrorEvent(reporter.FailedPackage, err, "failed to package the kcl module")
 	}
 	return nil
 }
 
+// releasePkg returns the package to be packaged without the dev-dependencies.
+// The dependencies in kcl.mod.lock only required by the dev-dependencies are left out too.
+func (c *KpmClient) releasePkg(kclPkg *pkg.KclPkg) (*pkg.KclPkg, error) {
+	if kclPkg.ModFile.DevDependencies.Deps == nil || kclPkg.ModFile.DevDependencies.Deps.Len() == 0 {
+		return kclPkg, nil
+	}
+
+	requiredDeps, err := c.resolveRequiredDeps(kclPkg, false)
+	if err != nil {
+		return nil, err
+	}
+
+	releasePkg := *kclPkg
+	releasePkg.ModFile.DevDependencies = pkg.Dependencies{
+		Deps: orderedmap.NewOrderedMap[string, pkg.Dependency](),
+	}
+	releasePkg.Dependencies = pkg.Dependencies{
+		Deps:     orderedmap.NewOrderedMap[string, pkg.Dependency](),
+		Replaces: kclPkg.Dependencies.Replaces,
+	}
+	for _, name := range kclPkg.Dependencies.Deps.Keys() {
+		dependents, ok := requiredDeps[name]
+		if !ok {
+			continue
+		}
+		dep, _ := kclPkg.Dependencies.Deps.Get(name)
+		// The dev-dependencies left out are not the dependents of the dependency in the package released.
+		dep.Dependents = nil
+		dep.AddDependents(dependents...)
+		releasePkg.Dependencies.Deps.Set(name, dep)
+	}
+	return &releasePkg, nil
+}
+
 // FillDepInfo will fill registry information for a dependency.
 func (c *KpmClient) FillDepInfo(dep *pkg.Dependency, homepath string) error {
 	// Homepath for a dependency is the homepath of the kcl package.
@@ -686,19 +859,13 @@ func (c *KpmClient) FillDepInfo(dep *pkg.Dependency, homepath string) error {
 		}
 
 		if len(dep.Source.Oci.Repo) == 0 {
-			urlpath := utils.JoinPath(c.GetSettings().DefaultOciRepo(), dep.Name)
+			urlpath := utils.JoinPath(c.GetSettings().DefaultOciRepo(), dep.PkgName())
 			dep.Source.Oci.Repo = urlpath
 		}
 	}
 	if dep.Source.Git != nil && dep.Source.Git.GetPackage() != "" {
 		name := utils.ParseRepoNameFromGitUrl(dep.Source.Git.Url)
-		if len(dep.Source.Git.Tag) != 0 {
-			dep.FullName = fmt.Sprintf(PKG_NAME_PATTERN, name, dep.Source.Git.Tag)
-		} else if len(dep.Source.Git.Commit) != 0 {
-			dep.FullName = fmt.Sprintf(PKG_NAME_PATTERN, name, dep.Source.Git.Commit)
-		} else {
-			dep.FullName = fmt.Sprintf(PKG_NAME_PATTERN, name, dep.Source.Git.Branch)
-		}
+		dep.FullName = fmt.Sprintf(PKG_NAME_PATTERN, name, dep.Source.Git.GetPinnedReference())
 	}
 	return nil
 }
@@ -719,6 +886,26 @@ func (c *KpmClient) FillDependenciesInfo(modFile *pkg.ModFile) error {
 	return nil
 }
 
+// newOciClient creates the oci client for the repo of the oci source.
+func (c *KpmClient) newOciClient(ociSource *downloader.Oci) (*oci.OciClient, error) {
+	cred, err := c.GetCredentials(ociSource.Reg)
+	if err != nil {
+		return nil, err
+	}
+
+	ociCli, err := oci.NewOciClientWithOpts(
+		oci.WithCredential(cred),
+		oci.WithRepoPath(utils.JoinPath(ociSource.Reg, ociSource.Repo)),
+		oci.WithSettings(c.GetSettings()),
+		oci.WithInsecureSkipTLSverify(c.insecureSkipTLSverify),
+	)
+	if err != nil {
+		return nil, err
+	}
+	ociCli.SetLogWriter(c.logWriter)
+	return ociCli, nil
+}
+
 // AcquireTheLatestOciVersion will acquire the latest version of the OCI reference.
 func (c *KpmClient) AcquireTheLatestOciVersion(ociSource downloader.Oci) (string, error) {
 	repoPath := utils.JoinPath(ociSource.Reg, ociSource.Repo)
@@ -810,6 +997,7 @@ func (c *KpmClient) downloadPkg(options ...downloader.Option) (*pkg.KclPkg, erro
 // Download will download the dependency to the local path.
 func (c *KpmClient) Download(dep *pkg.Dependency, homePath, localPath string) (*pkg.Dependency, error) {
 	if dep.Source.Git != nil {
+		pathSuffix := dep.GenPathSuffix()
 		err := c.DepDownloader.Download(*downloader.NewDownloadOptions(
 			downloader.WithLocalPath(localPath),
 			downloader.WithSource(dep.Source),
@@ -820,6 +1008,19 @@ func (c *KpmClient) Download(dep *pkg.Dependency, homePath, localPath string) (*
 			return nil, err
 		}
 
+		// The branch is resolved to a commit during downloading,
+		// move the package to the path of the commit, so that the package is reused until the branch is updated.
+		if pinnedPathSuffix := dep.GenPathSuffix(); filepath.Base(localPath) == pathSuffix && pinnedPathSuffix != pathSuffix {
+			pinnedPath := filepath.Join(filepath.Dir(localPath), pinnedPathSuffix)
+			if err := os.RemoveAll(pinnedPath); err != nil {
+				return nil, err
+			}
+			if err := os.Rename(localPath, pinnedPath); err != nil {
+				return nil, err
+			}
+			localPath = pinnedPath
+		}
+
 		dep.FullName = dep.GenDepFullName()
 
 		if dep.GetPackage() != "" {
@@ -846,7 +1047,8 @@ func (c *KpmClient) Download(dep *pkg.Dependency, homePath, localPath string) (*
 			ociSource = dep.Source.Oci
 		}
 		// Select the latest tag, if the tag, the user inputed, is empty.
-		if ociSource.Tag == "" || ociSource.Tag == constants.LATEST {
+		// The oci source pinned by the digest is downloaded by the digest.
+		if !ociSource.IsPinned() && (ociSource.Tag == "" || ociSource.Tag == constants.LATEST) {
 			latestTag, err := c.AcquireTheLatestOciVersion(*ociSource)
 			if err != nil {
 				return nil, err
@@ -906,7 +1108,7 @@ func (c *KpmClient) Download(dep *pkg.Dependency, homePath, localPath string) (*
 			return nil, err
 		}
 		if dep.Sum == "" {
-			dep.Sum, err = utils.HashDir(localPath)
+			dep.Sum, err = utils.HashDirH1(localPath)
 			if err != nil {
 				return nil, err
 			}
@@ -1194,6 +1396,7 @@ func (c *KpmClient) PushToOci(localPath string, ociOpts *opt.OciOptions) error {
 
 	return ociCli.PushWithOciManifest(localPath, ociOpts.Tag, &opt.OciManifestOptions{
 		Annotations: ociOpts.Annotations,
+		Config:      ociOpts.Config,
 	})
 }
 
@@ -1289,6 +1492,27 @@ func (c *KpmClient) ParseOciOptionFromString(oci string, tag string) (*opt.OciOp
 		return ociOpt, nil
 	}
 
+	// The oci url pinned by the digest, e.g. 'oci://ghcr.io/kcl-lang/helloworld@sha256:...',
+	// is pulled by the digest instead of the tag.
+	if repo, dgst, ok := strings.Cut(ociOpt.Repo, "@"); ok {
+		if _, err := digest.Parse(dgst); err != nil {
+			return nil, reporter.NewErrorEvent(reporter.InvalidPkgRef, err, fmt.Sprintf("invalid oci digest '%s'", dgst))
+		}
+		if len(tag) != 0 {
+			reporter.ReportEventTo(
+				reporter.NewEvent(
+					reporter.InvalidFlag,
+					"arg '--tag' is invalid for oci url pinned by the digest",
+				),
+				c.logWriter,
+			)
+		}
+		ociOpt.Repo = repo
+		ociOpt.Ref = filepath.Base(repo)
+		ociOpt.Tag = dgst
+		return ociOpt, nil
+	}
+
 	ociOpt.Tag = tag
 
 	return ociOpt, nil
@@ -1309,7 +1533,7 @@ func (c *KpmClient) InitGraphAndDownloadDeps(kclPkg *pkg.KclPkg) (*pkg.Dependenc
 		return nil, nil, err
 	}
 
-	changedDeps, err := c.DownloadDeps(&kclPkg.ModFile.Dependencies, &kclPkg.Dependencies, depGraph, kclPkg.HomePath, root)
+	changedDeps, err := c.downloadRootDeps(kclPkg, depGraph, root)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1317,6 +1541,106 @@ func (c *KpmClient) InitGraphAndDownloadDeps(kclPkg *pkg.KclPkg) (*pkg.Dependenc
 	return changedDeps, depGraph, nil
 }
 
+// downloadRootDeps will download the dependencies and the dev-dependencies of the package being built,
+// the dependencies are added into the dependency graph as the children of 'root'.
+func (c *KpmClient) downloadRootDeps(kclPkg *pkg.KclPkg, depGraph graph.Graph[module.Version, module.Version], root module.Version) (*pkg.Dependencies, error) {
+	changedDeps, err := c.DownloadDeps(&kclPkg.ModFile.Dependencies, &kclPkg.Dependencies, depGraph, kclPkg.HomePath, root)
+	if err != nil {
+		return nil, err
+	}
+	if kclPkg.ModFile.DevDependencies.Deps != nil && kclPkg.ModFile.DevDependencies.Deps.Len() != 0 {
+		changedDevDeps, err := c.DownloadDeps(&kclPkg.ModFile.DevDependencies, &kclPkg.Dependencies, depGraph, kclPkg.HomePath, root)
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range changedDevDeps.Deps.Keys() {
+			d, _ := changedDevDeps.Deps.Get(k)
+			if _, ok := changedDeps.Deps.Get(k); !ok {
+				changedDeps.Deps.Set(k, d)
+			}
+		}
+	}
+
+	err = fillLockDependents(&kclPkg.Dependencies, depGraph, root)
+	if err != nil {
+		return nil, err
+	}
+	return changedDeps, nil
+}
+
+// fillLockDependents will record the dependents of the dependencies in kcl.mod.lock
+// by the edges of the dependency graph reachable from 'root'.
+func fillLockDependents(lockDeps *pkg.Dependencies, depGraph graph.Graph[module.Version, module.Version], root module.Version) error {
+	adjacencyMap, err := depGraph.AdjacencyMap()
+	if err != nil {
+		return err
+	}
+
+	dependents := make(map[string][]string)
+	visited := map[module.Version]bool{root: true}
+	queue := []module.Version{root}
+	for len(queue) != 0 {
+		parent := queue[0]
+		queue = queue[1:]
+		for child := range adjacencyMap[parent] {
+			dependents[child.Path] = append(dependents[child.Path], parent.Path)
+			if !visited[child] {
+				visited[child] = true
+				queue = append(queue, child)
+			}
+		}
+	}
+
+	for _, name := range lockDeps.Deps.Keys() {
+		lockedDep, _ := lockDeps.Deps.Get(name)
+		if names, ok := dependents[name]; ok {
+			lockedDep.Dependents = nil
+			lockedDep.AddDependents(names...)
+			lockDeps.Deps.Set(name, lockedDep)
+		}
+	}
+	return nil
+}
+
+// resolveDepVersionRange will select the exact version for the dependency required by a version range.
+// The locked version is preferred if it is still in the range,
+// otherwise the highest version in the range is selected from the source.
+func (c *KpmClient) resolveDepVersionRange(dep *pkg.Dependency, lockDeps *pkg.Dependencies) (*pkg.Dependency, error) {
+	if !dep.Source.IsVersionRange() {
+		return dep, nil
+	}
+
+	if lockDeps != nil {
+		if lockedDep, ok := lockDeps.Deps.Get(dep.Name); ok && !lockedDep.Source.IsVersionRange() {
+			inRange, err := semver.VersionInRange(lockedDep.Version, dep.Source.VersionRange())
+			if err == nil && inRange {
+				exactDep := dep.WithExactVersion(lockedDep.Version)
+				exactDep.Sum = lockedDep.Sum
+				return &exactDep, nil
+			}
+		}
+	}
+
+	credCli, err := c.GetCredsClient()
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := downloader.ResolveVersionRange(
+		dep.Source,
+		downloader.WithCredsClient(credCli),
+		downloader.WithSettings(c.settings),
+		downloader.WithInsecureSkipTLSverify(c.insecureSkipTLSverify),
+		downloader.WithLogWriter(c.logWriter),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	exactDep := dep.WithExactVersion(source.ExactVersion())
+	return &exactDep, nil
+}
+
 // dependencyExists will check whether the dependency exists in the local filesystem.
 func (c *KpmClient) dependencyExistsLocal(searchPath string, dep *pkg.Dependency, isVendor bool) (*pkg.Dependency, error) {
 	// If the flag '--no_sum_check' is set, skip the checksum check.
@@ -1342,6 +1666,16 @@ func (c *KpmClient) dependencyExistsLocal(searchPath string, dep *pkg.Dependency
 	return nil, nil
 }
 
+// sumMatches checks whether the checksum locked in kcl.mod.lock matches the dependency downloaded.
+// The checksums computed by different algorithms are not comparable,
+// so the checksum locked is verified against the content of the dependency.
+func sumMatches(expectedSum string, dep *pkg.Dependency) bool {
+	if utils.SameSumAlgorithm(expectedSum, dep.Sum) {
+		return expectedSum == dep.Sum
+	}
+	return utils.CheckPackageSum(expectedSum, dep.LocalFullPath)
+}
+
 // downloadDeps will download all the dependencies of the current kcl package.
 func (c *KpmClient) DownloadDeps(deps *pkg.Dependencies, lockDeps *pkg.Dependencies, depGraph graph.Graph[module.Version, module.Version], pkghome string, parent module.Version) (*pkg.Dependencies, error) {
 
@@ -1356,13 +1690,66 @@ func (c *KpmClient) DownloadDeps(deps *pkg.Dependencies, lockDeps *pkg.Dependenc
 			return nil, errors.InvalidDependency
 		}
 
-		existDep, err := c.dependencyExistsLocal(pkghome, &d, false)
+		// The dependency on the workspace member uses the local copy of the member.
+		if memberPath, ok := c.workspaceMember(&d); ok {
+			memberDep, err := c.workspaceMemberDep(&d, memberPath, lockDeps)
+			if err != nil {
+				return nil, err
+			}
+			newDeps.Deps.Set(d.Name, *memberDep)
+			continue
+		}
+
+		// If the dependency is required by a version range, select the exact version.
+		isVersionRange := d.Source.IsVersionRange()
+		if isVersionRange {
+			exactDep, err := c.resolveDepVersionRange(&d, lockDeps)
+			if err != nil {
+				return nil, err
+			}
+			d = *exactDep
+		}
+
+		// The dependency redirected by the [replace] section in kcl.mod is fetched from the replacement,
+		// and the original dependency is recorded with the replacement.
+		originalDep := d
+		replacedDep, isReplaced := lockDeps.Replaces.ReplaceDep(&d)
+		if isReplaced {
+			d = *replacedDep
+		}
+
+		// The dependency on a git branch is pinned to the commit locked in kcl.mod.lock,
+		// the branch not pinned yet is always fetched to resolve the commit.
+		if lockedDep, ok := lockDeps.Deps.Get(d.Name); ok && !isReplaced {
+			d.PinGitBranch(&lockedDep)
+		}
+
+		// The version not locked yet is checked whether it is deprecated or yanked by the publisher.
+		if err := c.checkOciVersionStatus(&d, lockDeps); err != nil {
+			return nil, err
+		}
+
+		var existDep *pkg.Dependency
+		var err error
+		if !d.IsUnpinnedGitBranch() {
+			existDep, err = c.dependencyExistsLocal(pkghome, &d, false)
+		}
 		if existDep != nil && err == nil {
+			// The package in the cache is resolved without the provenance, e.g. the manifest digest.
+			lockedExistDep, _ := lockDeps.Deps.Get(d.Name)
+			existDep.KeepProvenance(&lockedExistDep)
+			if isReplaced {
+				*existDep = originalDep.RecordReplace(existDep)
+			}
 			newDeps.Deps.Set(d.Name, *existDep)
 			continue
 		}
 
 		expectedSum := lockDeps.Deps.GetOrDefault(d.Name, pkg.TestPkgDependency).Sum
+		// The checksum in kcl.mod.lock is not expected if the dependency is fetched from another source.
+		if lockDeps.Deps.GetOrDefault(d.Name, pkg.TestPkgDependency).Replace != d.Replace {
+			expectedSum = ""
+		}
 		// Clean the cache
 		if len(c.homePath) == 0 || len(d.FullName) == 0 {
 			return nil, errors.InternalBug
@@ -1383,21 +1770,32 @@ func (c *KpmClient) DownloadDeps(deps *pkg.Dependencies, lockDeps *pkg.Dependenc
 		if lockedDep.Oci != nil && lockedDep.Equals(lockDeps.Deps.GetOrDefault(d.Name, pkg.TestPkgDependency)) {
 			if !c.noSumCheck && expectedSum != "" &&
 				lockedDep.Sum != "" &&
-				lockedDep.Sum != expectedSum {
+				!sumMatches(expectedSum, lockedDep) {
 				return nil, reporter.NewErrorEvent(
 					reporter.CheckSumMismatch,
 					errors.CheckSumMismatchError,
 					fmt.Sprintf("checksum for '%s' changed in lock file '%s' and '%s'", lockedDep.Name, expectedSum, lockedDep.Sum),
 				)
-			} else {
+			} else if expectedSum == "" || lockedDep.Sum == "" || utils.SameSumAlgorithm(expectedSum, lockedDep.Sum) {
+				// The checksum locked is kept, unless the checksum published is computed by another algorithm,
+				// which replaces the checksum locked once the checksum locked is verified above.
 				lockedDep.Sum = lockDeps.Deps.GetOrDefault(d.Name, pkg.Dependency{}).Sum
 			}
 		}
 
+		prevLockedDep, _ := lockDeps.Deps.Get(d.Name)
+		lockedDep.KeepProvenance(&prevLockedDep)
+		if isReplaced {
+			*lockedDep = originalDep.RecordReplace(lockedDep)
+		}
 		newDeps.Deps.Set(d.Name, *lockedDep)
 		// After downloading the dependency in kcl.mod, update the dep into to the kcl.mod
 		// Only the direct dependencies are updated to kcl.mod.
-		deps.Deps.Set(d.Name, *lockedDep)
+		// The version range in kcl.mod is kept, the exact version is only recorded in kcl.mod.lock.
+		// The replacement is not updated to kcl.mod, it is only recorded in kcl.mod.lock.
+		if !isVersionRange && !isReplaced {
+			deps.Deps.Set(d.Name, *lockedDep)
+		}
 	}
 
 	// necessary to make a copy as when we are updating kcl.mod in below for loop
@@ -1530,8 +1928,12 @@ func (c *KpmClient) pullTarFromOci(localPath string, ociOpts *opt.OciOptions) er
 	}
 
 	full_repo := utils.JoinPath(ociOpts.Reg, ociOpts.Repo)
+	pulling := fmt.Sprintf("%s:%s", ociOpts.Repo, tagSelected)
+	if _, err := digest.Parse(tagSelected); err == nil {
+		pulling = fmt.Sprintf("%s@%s", ociOpts.Repo, tagSelected)
+	}
 	reporter.ReportMsgTo(
-		fmt.Sprintf("pulling '%s:%s' from '%s'", ociOpts.Repo, tagSelected, full_repo),
+		fmt.Sprintf("pulling '%s' from '%s'", pulling, full_repo),
 		c.logWriter,
 	)
 
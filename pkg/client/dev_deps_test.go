@@ -0,0 +1,112 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"kcl-lang.io/kpm/pkg/utils"
+)
+
+func TestResolveDevDeps(t *testing.T) {
+	testDir := getTestDir("test_dev_deps")
+	pkgPath := filepath.Join(testDir, "pkg")
+	consumerPath := filepath.Join(testDir, "consumer")
+	modPath := filepath.Join(pkgPath, "kcl.mod")
+
+	expectedMod, err := os.ReadFile(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.WriteFile(modPath, expectedMod, 0644)
+		_ = os.RemoveAll(filepath.Join(pkgPath, "kcl.mod.lock"))
+		_ = os.RemoveAll(filepath.Join(consumerPath, "kcl.mod.lock"))
+	}()
+
+	kpmcli, err := NewKpmClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The dev-dependencies are resolved for the package itself.
+	kpkg, err := kpmcli.LoadPkgFromPath(pkgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkgMap, err := kpmcli.ResolveDepsIntoMap(kpkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, len(pkgMap), 2)
+	assert.Equal(t, pkgMap["dep_0"], filepath.Join(testDir, "dep_0"))
+	assert.Equal(t, pkgMap["dev_0"], filepath.Join(testDir, "dev_0"))
+
+	gotMod, err := os.ReadFile(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(gotMod), string(expectedMod))
+
+	// The dev-dependencies are left out when the package is consumed as a dependency.
+	consumer, err := kpmcli.LoadPkgFromPath(consumerPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkgMap, err = kpmcli.ResolveDepsIntoMap(consumer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, len(pkgMap), 2)
+	assert.Equal(t, pkgMap["pkg"], pkgPath)
+	assert.Equal(t, pkgMap["dep_0"], filepath.Join(testDir, "dep_0"))
+	_, ok := pkgMap["dev_0"]
+	assert.Equal(t, ok, false)
+}
+
+func TestPackageWithoutDevDeps(t *testing.T) {
+	testDir := getTestDir("test_dev_deps")
+	pkgPath := filepath.Join(testDir, "pkg")
+	tarPath := filepath.Join(testDir, "pkg.tar")
+	modPath := filepath.Join(pkgPath, "kcl.mod")
+
+	expectedMod, err := os.ReadFile(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.WriteFile(modPath, expectedMod, 0644)
+		_ = os.RemoveAll(tarPath)
+		_ = os.RemoveAll(filepath.Join(pkgPath, "kcl.mod.lock"))
+	}()
+
+	kpmcli, err := NewKpmClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	kpkg, err := kpmcli.LoadPkgFromPath(pkgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = kpmcli.Package(kpkg, tarPath, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gotMod, err := utils.ReadFileFromTar(tarPath, "kcl.mod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Assert(t, strings.Contains(string(gotMod), "dep_0"))
+	assert.Assert(t, !strings.Contains(string(gotMod), "dev-dependencies"))
+	assert.Assert(t, !strings.Contains(string(gotMod), "dev_0"))
+
+	// The kcl.mod in the package itself is kept.
+	localMod, err := os.ReadFile(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Assert(t, strings.Contains(string(localMod), "dev_0"))
+}
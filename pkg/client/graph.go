@@ -0,0 +1,311 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/dominikbraun/graph"
+	"golang.org/x/mod/module"
+)
+
+const (
+	GRAPH_FORMAT_TEXT    = "text"
+	GRAPH_FORMAT_DOT     = "dot"
+	GRAPH_FORMAT_MERMAID = "mermaid"
+	GRAPH_FORMAT_JSON    = "json"
+	GRAPH_FORMAT_TREE    = "tree"
+)
+
+// GraphFormats are the output formats of the dependency graph.
+var GraphFormats = []string{
+	GRAPH_FORMAT_TEXT,
+	GRAPH_FORMAT_DOT,
+	GRAPH_FORMAT_MERMAID,
+	GRAPH_FORMAT_JSON,
+	GRAPH_FORMAT_TREE,
+}
+
+// DepGraphNode is a module in the dependency graph.
+type DepGraphNode struct {
+	// ID is the module in the format of '<name>@<version>'.
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	// Source is the type of the source the module is downloaded from, e.g. 'oci', 'git' or 'local'.
+	Source string `json:"source,omitempty"`
+	// Location is where the module is downloaded from, e.g. the oci repo or the git url.
+	Location string `json:"location,omitempty"`
+}
+
+// DepGraphEdge is a requirement from a module to another in the dependency graph.
+type DepGraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// DepGraph is the dependency graph rendered in the formats of 'kpm graph'.
+type DepGraph struct {
+	Nodes []DepGraphNode `json:"nodes"`
+	Edges []DepGraphEdge `json:"edges"`
+
+	roots    []module.Version
+	children map[module.Version][]module.Version
+	depth    int
+}
+
+// DepGraphOptions is the option for rendering the dependency graph.
+type DepGraphOptions struct {
+	// Depth is the max depth of the dependencies from the root packages, 0 means no limit.
+	Depth int
+	// Filter only keeps the dependencies whose '<name>@<version>' contains it and the paths from the root packages to them.
+	Filter string
+}
+
+type DepGraphOption func(*DepGraphOptions) error
+
+// WithDepGraphDepth sets the max depth of the dependencies from the root packages.
+func WithDepGraphDepth(depth int) DepGraphOption {
+	return func(opts *DepGraphOptions) error {
+		if depth < 0 {
+			return fmt.Errorf("invalid depth %d, the depth should not be negative", depth)
+		}
+		opts.Depth = depth
+		return nil
+	}
+}
+
+// WithDepGraphFilter sets the filter of the names of the dependencies.
+func WithDepGraphFilter(filter string) DepGraphOption {
+	return func(opts *DepGraphOptions) error {
+		opts.Filter = filter
+		return nil
+	}
+}
+
+// NewDepGraph creates the dependency graph rendered from the root packages in 'depGraph'.
+func NewDepGraph(depGraph graph.Graph[module.Version, module.Version], roots []module.Version, options ...DepGraphOption) (*DepGraph, error) {
+	opts := &DepGraphOptions{}
+	for _, option := range options {
+		if err := option(opts); err != nil {
+			return nil, err
+		}
+	}
+
+	adjMap, err := depGraph.AdjacencyMap()
+	if err != nil {
+		return nil, err
+	}
+
+	// Calculate the distance from the root packages to each module by BFS,
+	// the modules deeper than the depth are not included.
+	distances := make(map[module.Version]int)
+	var queue []module.Version
+	for _, root := range roots {
+		if _, ok := distances[root]; !ok {
+			distances[root] = 0
+			queue = append(queue, root)
+		}
+	}
+	children := make(map[module.Version][]module.Version)
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if opts.Depth != 0 && distances[current] >= opts.Depth {
+			continue
+		}
+		for _, next := range sortedModules(adjMap[current]) {
+			children[current] = append(children[current], next)
+			if _, ok := distances[next]; !ok {
+				distances[next] = distances[current] + 1
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	// Only keep the modules matched by the filter and the modules requiring them.
+	if len(opts.Filter) != 0 {
+		parents := make(map[module.Version][]module.Version)
+		var matched []module.Version
+		for m := range distances {
+			for _, child := range children[m] {
+				parents[child] = append(parents[child], m)
+			}
+			if distances[m] != 0 && strings.Contains(formatModule(m), opts.Filter) {
+				matched = append(matched, m)
+			}
+		}
+		kept := make(map[module.Version]bool)
+		for len(matched) > 0 {
+			current := matched[0]
+			matched = matched[1:]
+			if kept[current] {
+				continue
+			}
+			kept[current] = true
+			matched = append(matched, parents[current]...)
+		}
+		for _, root := range roots {
+			kept[root] = true
+		}
+		for m := range distances {
+			if !kept[m] {
+				delete(distances, m)
+				delete(children, m)
+				continue
+			}
+			var keptChildren []module.Version
+			for _, child := range children[m] {
+				if kept[child] {
+					keptChildren = append(keptChildren, child)
+				}
+			}
+			children[m] = keptChildren
+		}
+	}
+
+	modules := make([]module.Version, 0, len(distances))
+	for m := range distances {
+		modules = append(modules, m)
+	}
+	sortModules(modules)
+
+	depGraphView := &DepGraph{
+		Nodes:    []DepGraphNode{},
+		Edges:    []DepGraphEdge{},
+		roots:    roots,
+		children: children,
+		depth:    opts.Depth,
+	}
+	for _, m := range modules {
+		node := DepGraphNode{
+			ID:      formatModule(m),
+			Name:    m.Path,
+			Version: m.Version,
+		}
+		_, properties, err := depGraph.VertexWithProperties(m)
+		if err != nil {
+			return nil, err
+		}
+		// The source of the module is recorded as the attribute of the vertex.
+		for sourceType, location := range properties.Attributes {
+			node.Source = sourceType
+			node.Location = location
+		}
+		depGraphView.Nodes = append(depGraphView.Nodes, node)
+		for _, child := range children[m] {
+			depGraphView.Edges = append(depGraphView.Edges, DepGraphEdge{
+				From: formatModule(m),
+				To:   formatModule(child),
+			})
+		}
+	}
+	return depGraphView, nil
+}
+
+// Format renders the dependency graph in the format.
+func (g *DepGraph) Format(format string) (string, error) {
+	switch format {
+	case GRAPH_FORMAT_TEXT:
+		return g.FormatText(), nil
+	case GRAPH_FORMAT_DOT:
+		return g.FormatDot(), nil
+	case GRAPH_FORMAT_MERMAID:
+		return g.FormatMermaid(), nil
+	case GRAPH_FORMAT_JSON:
+		return g.FormatJson()
+	case GRAPH_FORMAT_TREE:
+		return g.FormatTree(), nil
+	default:
+		return "", fmt.Errorf("invalid graph format '%s', only %s are supported", format, strings.Join(GraphFormats, ", "))
+	}
+}
+
+// FormatText renders the dependency graph as the requirements in the format of '<module> <required module>' line by line.
+func (g *DepGraph) FormatText() string {
+	var sb strings.Builder
+	for _, edge := range g.Edges {
+		sb.WriteString(fmt.Sprintf("%s %s\n", edge.From, edge.To))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// FormatDot renders the dependency graph in the DOT language of graphviz.
+func (g *DepGraph) FormatDot() string {
+	var sb strings.Builder
+	sb.WriteString("digraph dependencies {\n")
+	for _, node := range g.Nodes {
+		sb.WriteString(fmt.Sprintf("  %q;\n", node.ID))
+	}
+	for _, edge := range g.Edges {
+		sb.WriteString(fmt.Sprintf("  %q -> %q;\n", edge.From, edge.To))
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
+
+// FormatMermaid renders the dependency graph as a mermaid flowchart.
+func (g *DepGraph) FormatMermaid() string {
+	ids := make(map[string]string)
+	var sb strings.Builder
+	sb.WriteString("graph TD\n")
+	for i, node := range g.Nodes {
+		ids[node.ID] = fmt.Sprintf("n%d", i)
+		sb.WriteString(fmt.Sprintf("  %s[\"%s\"]\n", ids[node.ID], strings.ReplaceAll(node.ID, "\"", "#quot;")))
+	}
+	for _, edge := range g.Edges {
+		sb.WriteString(fmt.Sprintf("  %s --> %s\n", ids[edge.From], ids[edge.To]))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// FormatJson renders the nodes, the edges and the sources of the dependency graph in json.
+func (g *DepGraph) FormatJson() (string, error) {
+	jsonData, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(jsonData), nil
+}
+
+// FormatTree renders the dependency graph as an indented tree from each root package.
+// The module whose dependencies have been shown is marked with '(*)' and not expanded again.
+func (g *DepGraph) FormatTree() string {
+	expanded := make(map[module.Version]bool)
+	var lines []string
+	var walk func(m module.Version, prefix string, level int)
+	walk = func(m module.Version, prefix string, level int) {
+		children := g.children[m]
+		if g.depth != 0 && level >= g.depth {
+			children = nil
+		}
+		for i, child := range children {
+			branch, indent := "├── ", "│   "
+			if i == len(children)-1 {
+				branch, indent = "└── ", "    "
+			}
+			line := prefix + branch + formatModule(child)
+			if expanded[child] && len(g.children[child]) != 0 {
+				lines = append(lines, line+" (*)")
+				continue
+			}
+			lines = append(lines, line)
+			expanded[child] = true
+			walk(child, prefix+indent, level+1)
+		}
+	}
+	for _, root := range g.roots {
+		lines = append(lines, formatModule(root))
+		expanded[root] = true
+		walk(root, "", 0)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatModule formats the module in the format of '<name>@<version>'.
+func formatModule(m module.Version) string {
+	if m.Version == "" {
+		return m.Path
+	}
+	return m.Path + "@" + m.Version
+}
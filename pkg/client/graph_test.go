@@ -0,0 +1,149 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/dominikbraun/graph"
+	"golang.org/x/mod/module"
+	"gotest.tools/v3/assert"
+)
+
+func newTestDepGraph(t *testing.T) (graph.Graph[module.Version, module.Version], module.Version) {
+	depGraph := graph.New(func(m module.Version) module.Version { return m }, graph.Directed(), graph.PreventCycles())
+	root := module.Version{Path: "pkg", Version: "0.0.1"}
+	dep0 := module.Version{Path: "dep_0", Version: "0.0.1"}
+	dep1 := module.Version{Path: "dep_1", Version: "0.0.1"}
+	helloworld := module.Version{Path: "helloworld", Version: "0.1.2"}
+
+	assert.NilError(t, depGraph.AddVertex(root))
+	assert.NilError(t, depGraph.AddVertex(dep0, graph.VertexAttribute("local", "/path/to/dep_0")))
+	assert.NilError(t, depGraph.AddVertex(dep1, graph.VertexAttribute("git", "https://github.com/kcl-lang/dep_1")))
+	assert.NilError(t, depGraph.AddVertex(helloworld, graph.VertexAttribute("oci", "oci://ghcr.io/kcl-lang/helloworld")))
+	assert.NilError(t, depGraph.AddEdge(root, dep0))
+	assert.NilError(t, depGraph.AddEdge(root, dep1))
+	assert.NilError(t, depGraph.AddEdge(dep0, helloworld))
+	assert.NilError(t, depGraph.AddEdge(dep1, helloworld))
+	return depGraph, root
+}
+
+func TestDepGraphFormats(t *testing.T) {
+	depGraph, root := newTestDepGraph(t)
+	depGraphView, err := NewDepGraph(depGraph, []module.Version{root})
+	assert.NilError(t, err)
+
+	out, err := depGraphView.Format(GRAPH_FORMAT_TEXT)
+	assert.NilError(t, err)
+	assert.Equal(t, out, "dep_0@0.0.1 helloworld@0.1.2\n"+
+		"dep_1@0.0.1 helloworld@0.1.2\n"+
+		"pkg@0.0.1 dep_0@0.0.1\n"+
+		"pkg@0.0.1 dep_1@0.0.1")
+
+	out, err = depGraphView.Format(GRAPH_FORMAT_DOT)
+	assert.NilError(t, err)
+	assert.Equal(t, out, `digraph dependencies {
+  "dep_0@0.0.1";
+  "dep_1@0.0.1";
+  "helloworld@0.1.2";
+  "pkg@0.0.1";
+  "dep_0@0.0.1" -> "helloworld@0.1.2";
+  "dep_1@0.0.1" -> "helloworld@0.1.2";
+  "pkg@0.0.1" -> "dep_0@0.0.1";
+  "pkg@0.0.1" -> "dep_1@0.0.1";
+}`)
+
+	out, err = depGraphView.Format(GRAPH_FORMAT_MERMAID)
+	assert.NilError(t, err)
+	assert.Equal(t, out, `graph TD
+  n0["dep_0@0.0.1"]
+  n1["dep_1@0.0.1"]
+  n2["helloworld@0.1.2"]
+  n3["pkg@0.0.1"]
+  n0 --> n2
+  n1 --> n2
+  n3 --> n0
+  n3 --> n1`)
+
+	out, err = depGraphView.Format(GRAPH_FORMAT_TREE)
+	assert.NilError(t, err)
+	assert.Equal(t, out, `pkg@0.0.1
+├── dep_0@0.0.1
+│   └── helloworld@0.1.2
+└── dep_1@0.0.1
+    └── helloworld@0.1.2`)
+
+	out, err = depGraphView.Format(GRAPH_FORMAT_JSON)
+	assert.NilError(t, err)
+	assert.Equal(t, out, `{
+  "nodes": [
+    {
+      "id": "dep_0@0.0.1",
+      "name": "dep_0",
+      "version": "0.0.1",
+      "source": "local",
+      "location": "/path/to/dep_0"
+    },
+    {
+      "id": "dep_1@0.0.1",
+      "name": "dep_1",
+      "version": "0.0.1",
+      "source": "git",
+      "location": "https://github.com/kcl-lang/dep_1"
+    },
+    {
+      "id": "helloworld@0.1.2",
+      "name": "helloworld",
+      "version": "0.1.2",
+      "source": "oci",
+      "location": "oci://ghcr.io/kcl-lang/helloworld"
+    },
+    {
+      "id": "pkg@0.0.1",
+      "name": "pkg",
+      "version": "0.0.1"
+    }
+  ],
+  "edges": [
+    {
+      "from": "dep_0@0.0.1",
+      "to": "helloworld@0.1.2"
+    },
+    {
+      "from": "dep_1@0.0.1",
+      "to": "helloworld@0.1.2"
+    },
+    {
+      "from": "pkg@0.0.1",
+      "to": "dep_0@0.0.1"
+    },
+    {
+      "from": "pkg@0.0.1",
+      "to": "dep_1@0.0.1"
+    }
+  ]
+}`)
+
+	_, err = depGraphView.Format("svg")
+	assert.ErrorContains(t, err, "invalid graph format 'svg'")
+}
+
+func TestDepGraphWithDepthAndFilter(t *testing.T) {
+	depGraph, root := newTestDepGraph(t)
+
+	depGraphView, err := NewDepGraph(depGraph, []module.Version{root}, WithDepGraphDepth(1))
+	assert.NilError(t, err)
+	assert.Equal(t, depGraphView.FormatTree(), `pkg@0.0.1
+├── dep_0@0.0.1
+└── dep_1@0.0.1`)
+	assert.Equal(t, len(depGraphView.Nodes), 3)
+
+	depGraphView, err = NewDepGraph(depGraph, []module.Version{root}, WithDepGraphFilter("dep_1"))
+	assert.NilError(t, err)
+	assert.Equal(t, depGraphView.FormatText(), "pkg@0.0.1 dep_1@0.0.1")
+
+	depGraphView, err = NewDepGraph(depGraph, []module.Version{root}, WithDepGraphFilter("helloworld"), WithDepGraphDepth(1))
+	assert.NilError(t, err)
+	assert.Equal(t, depGraphView.FormatText(), "")
+
+	_, err = NewDepGraph(depGraph, []module.Version{root}, WithDepGraphDepth(-1))
+	assert.ErrorContains(t, err, "invalid depth -1")
+}
@@ -0,0 +1,372 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"kcl-lang.io/kpm/pkg/constants"
+	"kcl-lang.io/kpm/pkg/downloader"
+	"kcl-lang.io/kpm/pkg/git"
+	"kcl-lang.io/kpm/pkg/opt"
+	pkg "kcl-lang.io/kpm/pkg/package"
+	"kcl-lang.io/kpm/pkg/reporter"
+	"kcl-lang.io/kpm/pkg/utils"
+)
+
+// PkgInfo is the information of a remote kcl package.
+type PkgInfo struct {
+	// Name is the name of the package.
+	Name string `json:"name"`
+	// Version is the version of the package.
+	Version string `json:"version"`
+	// Description is the description of the package.
+	Description string `json:"description,omitempty"`
+	// Source is the url of the package, e.g. 'oci://ghcr.io/kcl-lang/k8s?tag=1.28'.
+	Source string `json:"source"`
+	// Sum is the checksum of the content of the package.
+	Sum string `json:"sum,omitempty"`
+	// Created is the time the package is pushed or committed in RFC 3339.
+	Created string `json:"created,omitempty"`
+	// Size is the size of the package in bytes.
+	Size int64 `json:"size"`
+	// Digest is the digest of the oci manifest or the git commit of the package.
+	Digest string `json:"digest,omitempty"`
+	// Dependencies are the direct dependencies of the package in the format of '<name>@<version>'.
+	Dependencies []string `json:"dependencies"`
+}
+
+// InfoOptions is the option for inspecting a remote kcl package.
+type InfoOptions struct {
+	// Source is the remote package to inspect.
+	Source *downloader.Source
+}
+
+type InfoOption func(*InfoOptions) error
+
+// WithInfoSource sets the remote package to inspect.
+func WithInfoSource(source *downloader.Source) InfoOption {
+	return func(opts *InfoOptions) error {
+		if source == nil || (source.Oci == nil && source.Git == nil) {
+			return fmt.Errorf("only the oci and git packages can be inspected")
+		}
+		opts.Source = source
+		return nil
+	}
+}
+
+// Info will return the information of the remote kcl package.
+// The information of the oci package is read from the manifest without downloading the package,
+// the git package is cloned into a temporary directory to read the kcl.mod and removed after that.
+func (c *KpmClient) Info(options ...InfoOption) (*PkgInfo, error) {
+	opts := &InfoOptions{}
+	for _, option := range options {
+		if err := option(opts); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Source == nil {
+		return nil, fmt.Errorf("the package to inspect is not specified")
+	}
+
+	if opts.Source.Oci != nil {
+		return c.ociPkgInfo(*opts.Source.Oci)
+	}
+	return c.gitPkgInfo(*opts.Source.Git)
+}
+
+// ParsePkgRef parses the reference of the remote package into the source.
+// The reference can be an oci url 'oci://<reg>/<repo>?tag=<tag>', a git url with the tag, the commit or the branch in the query,
+// or '<name>:<tag>' in the default oci registry.
+func (c *KpmClient) ParsePkgRef(ref string) (*downloader.Source, error) {
+	// The reference without the scheme is '<name>:<tag>' in the default oci registry.
+	if !strings.Contains(ref, "://") {
+		ociOpts, err := c.ParseOciRef(ref)
+		if err != nil {
+			return nil, reporter.NewErrorEvent(reporter.IsNotRef, fmt.Errorf("invalid package reference '%s'", ref))
+		}
+		return &downloader.Source{
+			Oci: &downloader.Oci{
+				Reg:  ociOpts.Reg,
+				Repo: ociOpts.Repo,
+				Tag:  ociOpts.Tag,
+			},
+		}, nil
+	}
+
+	refUrl, err := url.Parse(ref)
+	if err != nil {
+		return nil, reporter.NewErrorEvent(reporter.IsNotRef, err, fmt.Sprintf("invalid package reference '%s'", ref))
+	}
+
+	switch refUrl.Scheme {
+	case constants.OciScheme:
+		ociSource := &downloader.Oci{}
+		if err := ociSource.FromString(ref); err != nil {
+			return nil, err
+		}
+		return &downloader.Source{Oci: ociSource}, nil
+	case constants.GitScheme, constants.SshScheme:
+		gitSource := &downloader.Git{}
+		if err := gitSource.FromString(ref); err != nil {
+			return nil, err
+		}
+		return &downloader.Source{Git: gitSource}, nil
+	case constants.HttpsScheme, "http":
+		query := refUrl.Query()
+		refUrl.RawQuery = ""
+		return &downloader.Source{
+			Git: &downloader.Git{
+				Url:    refUrl.String(),
+				Tag:    query.Get(constants.Tag),
+				Commit: query.Get(constants.GitCommit),
+				Branch: query.Get(constants.GitBranch),
+			},
+		}, nil
+	default:
+		return nil, reporter.NewErrorEvent(
+			reporter.IsNotRef,
+			fmt.Errorf("invalid package reference '%s', only the oci and git packages can be inspected", ref),
+		)
+	}
+}
+
+// ociPkgInfo returns the information of the oci package from the annotations of the manifest,
+// and the description and the dependencies are read from the config blob if the version is published with it.
+func (c *KpmClient) ociPkgInfo(ociSource downloader.Oci) (*PkgInfo, error) {
+	if len(ociSource.Tag) == 0 {
+		tag, err := c.AcquireTheLatestOciVersion(ociSource)
+		if err != nil {
+			return nil, err
+		}
+		ociSource.Tag = tag
+	}
+
+	manifestJson, err := c.FetchOciManifestIntoJsonStr(opt.OciFetchOptions{
+		OciOptions: opt.OciOptions{
+			Reg:  ociSource.Reg,
+			Repo: ociSource.Repo,
+			Tag:  ociSource.Tag,
+		},
+	})
+	if err != nil {
+		return nil, reporter.NewErrorEvent(
+			reporter.FailedGetPkg,
+			err,
+			fmt.Sprintf("failed to fetch the manifest of '%s:%s'", utils.JoinPath(ociSource.Reg, ociSource.Repo), ociSource.Tag),
+		)
+	}
+
+	info, err := pkgInfoFromManifest(manifestJson, &ociSource)
+	if err != nil {
+		return nil, err
+	}
+
+	// kcl.mod in the config blob is preferred to the annotations,
+	// which are only used for the versions published in the layout without the config blob.
+	modFile, _, err := c.fetchOciConfigModFile(&ociSource)
+	if err != nil {
+		return nil, err
+	}
+	if modFile != nil {
+		kclPkg := pkg.KclPkg{ModFile: *modFile}
+		info.Description = kclPkg.GetPkgDescription()
+		info.Dependencies = kclPkg.GetPkgDeps()
+	}
+	return info, nil
+}
+
+// FetchOciModFile will return kcl.mod and the dependencies in kcl.mod.lock of the version published in the oci registry.
+// Only the config blob of the version is fetched, and the package is downloaded
+// if the version is published in the layout without the config blob.
+func (c *KpmClient) FetchOciModFile(ociSource *downloader.Oci) (*pkg.ModFile, *pkg.Dependencies, error) {
+	modFile, deps, err := c.fetchOciConfigModFile(ociSource)
+	if err != nil || modFile != nil {
+		return modFile, deps, err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "")
+	if err != nil {
+		return nil, nil, reporter.NewErrorEvent(reporter.Bug, err, "internal bugs, please contact us to fix it.")
+	}
+	// clean the temp dir.
+	defer os.RemoveAll(tmpDir)
+
+	kclPkg, err := c.DownloadPkgFromOci(&downloader.Oci{Reg: ociSource.Reg, Repo: ociSource.Repo, Tag: ociSource.Tag}, tmpDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &kclPkg.ModFile, &kclPkg.Dependencies, nil
+}
+
+// fetchOciConfigModFile will return kcl.mod and the dependencies in kcl.mod.lock in the config blob of the version,
+// and nil if the version is published in the layout without the config blob
+// or kcl.mod has the local dependencies relative to the package.
+func (c *KpmClient) fetchOciConfigModFile(ociSource *downloader.Oci) (*pkg.ModFile, *pkg.Dependencies, error) {
+	if ociSource == nil || len(ociSource.Tag) == 0 {
+		return nil, nil, reporter.NewErrorEvent(reporter.InvalidPkgRef, fmt.Errorf("the version of the package is not specified"))
+	}
+
+	ociCli, err := c.newOciClient(ociSource)
+	if err != nil {
+		return nil, nil, err
+	}
+	config, err := ociCli.FetchPackageConfig(ociSource.Tag)
+	if err != nil || config == nil {
+		return nil, nil, err
+	}
+	modFile, deps, err := pkg.ParseModFile(config.ModFile, config.ModLockFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	// kcl.mod in the config blob has no home path to find the local dependencies relative to the package,
+	// so the package with them is read from the package downloaded.
+	if modFile.DepsWithDevDeps().HasRelativeLocalDeps() {
+		return nil, nil, nil
+	}
+	return modFile, deps, nil
+}
+
+// fetchDepModFile returns kcl.mod of the oci dependency from the config blob for the resolver
+// to traverse the dependencies of it without downloading it,
+// and nil if the dependency is not from the oci registry or published in the layout without the config blob.
+func (c *KpmClient) fetchDepModFile(source *downloader.Source) (*pkg.ModFile, error) {
+	if source.Oci == nil || len(source.Oci.Tag) == 0 {
+		return nil, nil
+	}
+	modFile, _, err := c.fetchOciConfigModFile(source.Oci)
+	return modFile, err
+}
+
+// pkgInfoFromManifest returns the information of the oci package from the manifest fetched.
+func pkgInfoFromManifest(manifestJson string, ociSource *downloader.Oci) (*PkgInfo, error) {
+	var manifest v1.Manifest
+	if err := json.Unmarshal([]byte(manifestJson), &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse the manifest of '%s:%s': %w", ociSource.Repo, ociSource.Tag, err)
+	}
+
+	name, ok := manifest.Annotations[constants.DEFAULT_KCL_OCI_MANIFEST_NAME]
+	if !ok {
+		return nil, fmt.Errorf("'%s:%s' is not a kcl package", ociSource.Repo, ociSource.Tag)
+	}
+
+	source, err := ociSource.ToString()
+	if err != nil {
+		return nil, err
+	}
+
+	info := &PkgInfo{
+		Name:         name,
+		Version:      manifest.Annotations[constants.DEFAULT_KCL_OCI_MANIFEST_VERSION],
+		Description:  manifest.Annotations[constants.DEFAULT_KCL_OCI_MANIFEST_DESCRIPTION],
+		Source:       source,
+		Created:      manifest.Annotations[constants.DEFAULT_CREATE_OCI_MANIFEST_TIME],
+		Digest:       digest.FromString(manifestJson).String(),
+		Dependencies: []string{},
+	}
+	if len(info.Version) == 0 {
+		info.Version = ociSource.Tag
+	}
+	info.Sum, _ = pkg.SumFromManifest(manifest.Annotations)
+	for _, layer := range manifest.Layers {
+		info.Size += layer.Size
+	}
+	if deps := manifest.Annotations[constants.DEFAULT_KCL_OCI_MANIFEST_DEPS]; len(deps) != 0 {
+		info.Dependencies = strings.Split(deps, ",")
+	}
+	return info, nil
+}
+
+// gitPkgInfo returns the information of the git package from the kcl.mod in the git repo.
+func (c *KpmClient) gitPkgInfo(gitSource downloader.Git) (*PkgInfo, error) {
+	tmpDir, err := os.MkdirTemp("", "kpm-info")
+	if err != nil {
+		return nil, reporter.NewErrorEvent(reporter.Bug, err, "internal bugs, please contact us to fix it.")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	repoPath := filepath.Join(tmpDir, "repo")
+	repo, err := git.CloneWithOpts(
+		git.WithRepoURL(gitSource.Url),
+		git.WithCommit(gitSource.Commit),
+		git.WithTag(gitSource.Tag),
+		git.WithBranch(gitSource.Branch),
+		git.WithLocalPath(repoPath),
+		git.WithWriter(c.logWriter),
+	)
+	if err != nil {
+		return nil, reporter.NewErrorEvent(
+			reporter.FailedCloneFromGit,
+			err,
+			fmt.Sprintf("failed to clone from '%s'", gitSource.Url),
+		)
+	}
+
+	pkgPath := repoPath
+	if len(gitSource.Package) != 0 {
+		pkgPath = filepath.Join(repoPath, gitSource.Package)
+	}
+	kclPkg, err := c.LoadPkgFromPath(pkgPath)
+	if err != nil {
+		return nil, err
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return nil, err
+	}
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, err
+	}
+
+	sum, err := utils.HashDirH1(pkgPath)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := gitSource.ToString()
+	if err != nil {
+		return nil, err
+	}
+
+	info := &PkgInfo{
+		Name:         kclPkg.GetPkgName(),
+		Version:      kclPkg.GetPkgVersion(),
+		Description:  kclPkg.GetPkgDescription(),
+		Source:       source,
+		Sum:          sum,
+		Created:      commit.Committer.When.UTC().Format(time.RFC3339),
+		Digest:       commit.Hash.String(),
+		Dependencies: kclPkg.GetPkgDeps(),
+	}
+
+	// The size of the git package is the size of the files without the '.git' directory.
+	err = filepath.WalkDir(pkgPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if d.Type().IsRegular() {
+			fileInfo, err := d.Info()
+			if err != nil {
+				return err
+			}
+			info.Size += fileInfo.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
@@ -0,0 +1,148 @@
+package client
+
+import (
+	"encoding/json"
+	"testing"
+
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+	"kcl-lang.io/kpm/pkg/constants"
+	"kcl-lang.io/kpm/pkg/downloader"
+	"kcl-lang.io/kpm/pkg/mock"
+	"kcl-lang.io/kpm/pkg/oci"
+)
+
+func TestParsePkgRef(t *testing.T) {
+	kpmcli, err := NewKpmClient()
+	assert.NilError(t, err)
+
+	source, err := kpmcli.ParsePkgRef("oci://localhost:5001/test/k8s?tag=1.28.1")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, source.Oci, &downloader.Oci{Reg: "localhost:5001", Repo: "test/k8s", Tag: "1.28.1"})
+
+	source, err = kpmcli.ParsePkgRef("k8s:1.28.1")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, source.Oci, &downloader.Oci{
+		Reg:  kpmcli.GetSettings().DefaultOciRegistry(),
+		Repo: kpmcli.GetSettings().DefaultOciRepo() + "/k8s",
+		Tag:  "1.28.1",
+	})
+
+	source, err = kpmcli.ParsePkgRef("https://github.com/kcl-lang/flask-demo-kcl-manifests.git?commit=ade147b")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, source.Git, &downloader.Git{Url: "https://github.com/kcl-lang/flask-demo-kcl-manifests.git", Commit: "ade147b"})
+
+	_, err = kpmcli.ParsePkgRef("file:///path/to/pkg")
+	assert.ErrorContains(t, err, "only the oci and git packages can be inspected")
+}
+
+func TestPkgInfoFromManifest(t *testing.T) {
+	ociSource := &downloader.Oci{
+		Reg:  "localhost:5001",
+		Repo: "test/k8s",
+		Tag:  "1.28.1",
+	}
+	manifestJson := `{
+  "schemaVersion": 2,
+  "layers": [
+    {"mediaType": "application/vnd.oci.image.layer.v1.tar", "digest": "sha256:0000000000000000000000000000000000000000000000000000000000000000", "size": 1024}
+  ],
+  "annotations": {
+    "org.kcllang.package.name": "k8s",
+    "org.kcllang.package.version": "1.28.1",
+    "org.kcllang.package.description": "Kubernetes schemas",
+    "org.kcllang.package.sum": "h1:CDFTB+ydIlso9AGUKXRzz7PxOGYND+Rem11uxGVQ7YY=",
+    "org.kcllang.package.dependencies": "helloworld@0.1.2,local_dep",
+    "org.opencontainers.image.created": "2024-01-01T00:00:00Z"
+  }
+}`
+
+	info, err := pkgInfoFromManifest(manifestJson, ociSource)
+	assert.NilError(t, err)
+	assert.Equal(t, info.Name, "k8s")
+	assert.Equal(t, info.Version, "1.28.1")
+	assert.Equal(t, info.Description, "Kubernetes schemas")
+	assert.Equal(t, info.Source, "oci://localhost:5001/test/k8s?tag=1.28.1")
+	assert.Equal(t, info.Sum, "h1:CDFTB+ydIlso9AGUKXRzz7PxOGYND+Rem11uxGVQ7YY=")
+	assert.Equal(t, info.Created, "2024-01-01T00:00:00Z")
+	assert.Equal(t, info.Size, int64(1024))
+	assert.Equal(t, info.Digest[:7], "sha256:")
+	assert.DeepEqual(t, info.Dependencies, []string{"helloworld@0.1.2", "local_dep"})
+
+	_, err = pkgInfoFromManifest(`{"schemaVersion": 2}`, ociSource)
+	assert.ErrorContains(t, err, "'test/k8s:1.28.1' is not a kcl package")
+}
+
+func TestOciPkgInfoWithConfig(t *testing.T) {
+	registry := mock.NewOciRegistry()
+	defer registry.Close()
+
+	annotations := map[string]string{
+		constants.DEFAULT_KCL_OCI_MANIFEST_NAME:        "helloworld",
+		constants.DEFAULT_KCL_OCI_MANIFEST_VERSION:     "0.1.0",
+		constants.DEFAULT_KCL_OCI_MANIFEST_DESCRIPTION: "the description in the annotations",
+		constants.DEFAULT_KCL_OCI_MANIFEST_DEPS:        "k8s@1.28",
+	}
+	// The version published in the layout without the config blob.
+	_, err := registry.AddPackage("test/helloworld", "0.1.0", v1.MediaTypeImageConfig, []byte("{}"), []byte("tar"), annotations)
+	assert.NilError(t, err)
+
+	config, err := json.Marshal(oci.PackageConfig{
+		ModFile: `[package]
+name = "helloworld"
+version = "0.2.0"
+description = "the description in kcl.mod"
+
+[dependencies]
+k8s = "1.29"
+`,
+		ModLockFile: `[dependencies]
+  [dependencies.k8s]
+    name = "k8s"
+    full_name = "k8s_1.29"
+    version = "1.29"
+    sum = "h1:CDFTB+ydIlso9AGUKXRzz7PxOGYND+Rem11uxGVQ7YY="
+    reg = "ghcr.io"
+    repo = "kcl-lang/k8s"
+    oci_tag = "1.29"
+`,
+	})
+	assert.NilError(t, err)
+	annotations[constants.DEFAULT_KCL_OCI_MANIFEST_VERSION] = "0.2.0"
+	_, err = registry.AddPackage("test/helloworld", "0.2.0", constants.DEFAULT_KCL_OCI_CONFIG_MEDIA_TYPE, config, []byte("tar"), annotations)
+	assert.NilError(t, err)
+
+	kpmcli, err := NewKpmClient()
+	assert.NilError(t, err)
+
+	// The description and the dependencies are read from the annotations without the config blob.
+	source := &downloader.Source{Oci: &downloader.Oci{Reg: registry.Host(), Repo: "test/helloworld", Tag: "0.1.0"}}
+	info, err := kpmcli.Info(WithInfoSource(source))
+	assert.NilError(t, err)
+	assert.Equal(t, info.Version, "0.1.0")
+	assert.Equal(t, info.Description, "the description in the annotations")
+	assert.DeepEqual(t, info.Dependencies, []string{"k8s@1.28"})
+
+	// kcl.mod in the config blob is preferred.
+	source = &downloader.Source{Oci: &downloader.Oci{Reg: registry.Host(), Repo: "test/helloworld", Tag: "0.2.0"}}
+	info, err = kpmcli.Info(WithInfoSource(source))
+	assert.NilError(t, err)
+	assert.Equal(t, info.Version, "0.2.0")
+	assert.Equal(t, info.Description, "the description in kcl.mod")
+	assert.DeepEqual(t, info.Dependencies, []string{"k8s@1.29"})
+
+	modFile, deps, err := kpmcli.FetchOciModFile(source.Oci)
+	assert.NilError(t, err)
+	assert.Equal(t, modFile.Pkg.Name, "helloworld")
+	dep, ok := deps.Deps.Get("k8s")
+	assert.Equal(t, ok, true)
+	assert.Equal(t, dep.Sum, "h1:CDFTB+ydIlso9AGUKXRzz7PxOGYND+Rem11uxGVQ7YY=")
+
+	// The resolver reads kcl.mod of the dependency from the config blob.
+	modFile, err = kpmcli.fetchDepModFile(source)
+	assert.NilError(t, err)
+	assert.Equal(t, modFile.Pkg.Version, "0.2.0")
+	modFile, err = kpmcli.fetchDepModFile(&downloader.Source{Oci: &downloader.Oci{Reg: registry.Host(), Repo: "test/helloworld", Tag: "0.1.0"}})
+	assert.NilError(t, err)
+	assert.Assert(t, modFile == nil)
+}
@@ -0,0 +1,52 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/otiai10/copy"
+	"gotest.tools/v3/assert"
+	"kcl-lang.io/kpm/pkg/utils"
+)
+
+func TestMigrateLockFileV1(t *testing.T) {
+	testDir := getTestDir("test_lock_v2")
+	pkgPath := filepath.Join(testDir, "pkg")
+	lockPath := filepath.Join(pkgPath, "kcl.mod.lock")
+
+	err := copy.Copy(filepath.Join(pkgPath, "kcl.mod.lock.v1"), lockPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.RemoveAll(lockPath)
+		_ = os.RemoveAll(filepath.Join(testDir, "dep_0", "kcl.mod.lock"))
+		_ = os.RemoveAll(filepath.Join(testDir, "dep_1", "kcl.mod.lock"))
+	}()
+
+	kpmcli, err := NewKpmClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kpkg, err := kpmcli.LoadPkgFromPath(pkgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = kpmcli.ResolveDepsIntoMap(kpkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The lock file version 1 is rewritten with the version and the dependents.
+	expectedLock, err := os.ReadFile(filepath.Join(pkgPath, "kcl.mod.lock.expect"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotLock, err := os.ReadFile(lockPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, utils.RmNewline(string(expectedLock)), utils.RmNewline(string(gotLock)))
+}
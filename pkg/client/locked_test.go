@@ -0,0 +1,92 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/elliotchance/orderedmap/v2"
+	"gotest.tools/v3/assert"
+	pkg "kcl-lang.io/kpm/pkg/package"
+)
+
+func TestLockedMode(t *testing.T) {
+	pkgPath := filepath.Join(getTestDir("test_locked"), "pkg")
+	lockPath := filepath.Join(pkgPath, "kcl.mod.lock")
+
+	expectedLock, err := os.ReadFile(lockPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.WriteFile(lockPath, expectedLock, 0644)
+	}()
+
+	kpmcli, err := NewKpmClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	kpmcli.SetLocked(true)
+
+	kpkg, err := kpmcli.LoadPkgFromPath(pkgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, kpkg.Locked, true)
+
+	err = kpmcli.ResolvePkgDepsMetadata(kpkg, true)
+	assert.ErrorContains(t, err, "+ dep_1 ")
+
+	gotLock, err := os.ReadFile(lockPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(gotLock), string(expectedLock))
+}
+
+func TestLockedModeWithNoSumCheck(t *testing.T) {
+	pkgPath := filepath.Join(getTestDir("test_locked"), "pkg")
+	lockPath := filepath.Join(pkgPath, "kcl.mod.lock")
+
+	expectedLock, err := os.ReadFile(lockPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.WriteFile(lockPath, expectedLock, 0644)
+	}()
+
+	kpmcli, err := NewKpmClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	kpmcli.SetLocked(true)
+	kpmcli.SetNoSumCheck(true)
+
+	kpkg, err := kpmcli.LoadPkgFromPath(pkgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The version drift is still reported under '--no_sum_check'.
+	err = kpmcli.ResolvePkgDepsMetadata(kpkg, true)
+	assert.ErrorContains(t, err, "+ dep_1 ")
+}
+
+func TestDiffLockDeps(t *testing.T) {
+	lockedDeps := pkg.Dependencies{Deps: orderedmap.NewOrderedMap[string, pkg.Dependency]()}
+	lockedDeps.Deps.Set("k8s", pkg.Dependency{Name: "k8s", FullName: "k8s_1.28", Version: "1.28", Sum: "sum_1.28"})
+	lockedDeps.Deps.Set("helloworld", pkg.Dependency{Name: "helloworld", FullName: "helloworld_0.1.0", Version: "0.1.0"})
+
+	newDeps := pkg.Dependencies{Deps: orderedmap.NewOrderedMap[string, pkg.Dependency]()}
+	newDeps.Deps.Set("k8s", pkg.Dependency{Name: "k8s", FullName: "k8s_1.29", Version: "1.29", Sum: "sum_1.29"})
+	newDeps.Deps.Set("podinfo", pkg.Dependency{Name: "podinfo", FullName: "podinfo_0.1.1", Version: "0.1.1"})
+
+	assert.DeepEqual(t, pkg.DiffLockDeps(&lockedDeps, &newDeps), []string{
+		"~ k8s: version 1.28 -> 1.29",
+		"~ k8s: sum sum_1.28 -> sum_1.29",
+		"+ podinfo 0.1.1 (missing from kcl.mod.lock)",
+		"- helloworld 0.1.0 (no longer required)",
+	})
+	assert.Equal(t, len(pkg.DiffLockDeps(&lockedDeps, &lockedDeps)), 0)
+}
@@ -0,0 +1,162 @@
+package client
+
+import (
+	"fmt"
+
+	"kcl-lang.io/kpm/pkg/downloader"
+	"kcl-lang.io/kpm/pkg/git"
+	"kcl-lang.io/kpm/pkg/oci"
+	pkg "kcl-lang.io/kpm/pkg/package"
+	"kcl-lang.io/kpm/pkg/reporter"
+	"kcl-lang.io/kpm/pkg/semver"
+	"kcl-lang.io/kpm/pkg/utils"
+)
+
+// OutdatedDep is the version information of a dependency in kcl.mod.
+type OutdatedDep struct {
+	// Name is the name of the dependency.
+	Name string `json:"name"`
+	// Source is the source type of the dependency, 'oci' or 'git'.
+	Source string `json:"source"`
+	// Current is the version of the dependency required in kcl.mod.
+	Current string `json:"current"`
+	// LatestCompatible is the latest version compatible with the current version.
+	LatestCompatible string `json:"latest_compatible"`
+	// Latest is the latest version of the dependency.
+	Latest string `json:"latest"`
+	// Pinned is true if the git dependency is pinned to a branch or a commit,
+	// and 'Current' is the branch or the commit instead of a version.
+	Pinned bool `json:"pinned,omitempty"`
+}
+
+// IsOutdated returns true if there is a newer version than the current version.
+// The dependency pinned to a branch or a commit is never outdated, since it can not be compared with the tags.
+func (d *OutdatedDep) IsOutdated() bool {
+	if d.Pinned {
+		return false
+	}
+	return (d.LatestCompatible != "" && d.LatestCompatible != d.Current) ||
+		(d.Latest != "" && d.Latest != d.Current)
+}
+
+// OutdatedOptions is the option for checking the outdated dependencies of a package.
+type OutdatedOptions struct {
+	// KclPkg is the package to be checked.
+	KclPkg *pkg.KclPkg
+}
+
+type OutdatedOption func(*OutdatedOptions) error
+
+// WithOutdatedKclPkg sets the kcl package to be checked.
+func WithOutdatedKclPkg(kpkg *pkg.KclPkg) OutdatedOption {
+	return func(opts *OutdatedOptions) error {
+		opts.KclPkg = kpkg
+		return nil
+	}
+}
+
+// Outdated will return the version information of the dependencies in kcl.mod.
+// The versions of the oci dependencies are from the tags in the oci registry,
+// and the versions of the git dependencies are from the tags in the remote git repository.
+// The dependencies from local path are skipped,
+// and the git dependencies pinned to a branch or a commit are reported as pinned.
+func (c *KpmClient) Outdated(options ...OutdatedOption) ([]OutdatedDep, error) {
+	opts := &OutdatedOptions{}
+	for _, option := range options {
+		if err := option(opts); err != nil {
+			return nil, err
+		}
+	}
+
+	kpkg := opts.KclPkg
+	if kpkg == nil {
+		return nil, fmt.Errorf("kcl package is nil")
+	}
+
+	var outdatedDeps []OutdatedDep
+	for _, depName := range kpkg.ModFile.Dependencies.Deps.Keys() {
+		dep, ok := kpkg.ModFile.Dependencies.Deps.Get(depName)
+		if !ok {
+			return nil, fmt.Errorf("failed to get dependency %s", depName)
+		}
+
+		var versions []string
+		var current string
+		var pinned bool
+		var err error
+		if dep.Source.Oci != nil {
+			current = dep.Source.Oci.Tag
+			versions, err = c.getOciTags(dep.Source.Oci)
+		} else if dep.Source.Git != nil {
+			current = dep.Source.Git.Tag
+			if current == "" {
+				// The dependency is pinned to a commit or a branch.
+				current, _ = dep.Source.Git.GetValidGitReference()
+				pinned = true
+			}
+			versions, err = git.GetAllRemoteTags(dep.Source.Git.Url)
+			if err != nil {
+				err = reporter.NewErrorEvent(
+					reporter.FailedGetPackageVersions,
+					err,
+					fmt.Sprintf("failed to get the tags of '%s'", dep.Source.Git.Url),
+				)
+			}
+		} else {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if current == "" {
+			current = dep.Version
+		}
+
+		outdatedDep := OutdatedDep{
+			Name:    depName,
+			Source:  dep.GetSourceType(),
+			Current: current,
+			Pinned:  pinned,
+		}
+
+		versions = semver.FilterValidVersions(versions)
+		if len(versions) != 0 {
+			outdatedDep.Latest, err = semver.LatestVersion(versions)
+			if err != nil {
+				return nil, err
+			}
+			// The latest compatible version is only available when the current version is a semantic version,
+			// and the branch or the commit pinned is not compared with the tags.
+			if !pinned {
+				if latestCompatible, err := semver.LatestCompatibleVersion(versions, current); err == nil {
+					outdatedDep.LatestCompatible = latestCompatible
+				}
+			}
+		}
+
+		outdatedDeps = append(outdatedDeps, outdatedDep)
+	}
+
+	return outdatedDeps, nil
+}
+
+// getOciTags will return all the tags of the oci source.
+func (c *KpmClient) getOciTags(ociSource *downloader.Oci) ([]string, error) {
+	repoPath := utils.JoinPath(ociSource.Reg, ociSource.Repo)
+	cred, err := c.GetCredentials(ociSource.Reg)
+	if err != nil {
+		return nil, err
+	}
+
+	ociClient, err := oci.NewOciClientWithOpts(
+		oci.WithCredential(cred),
+		oci.WithRepoPath(repoPath),
+		oci.WithSettings(c.GetSettings()),
+		oci.WithInsecureSkipTLSverify(c.insecureSkipTLSverify),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ociClient.Tags()
+}
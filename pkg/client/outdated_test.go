@@ -0,0 +1,56 @@
+package client
+
+import (
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestOutdated(t *testing.T) {
+	pkgPath := filepath.Join(getTestDir("test_outdated"), "pkg")
+
+	kpmcli, err := NewKpmClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kpkg, err := kpmcli.LoadPkgFromPath(pkgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outdatedDeps, err := kpmcli.Outdated(WithOutdatedKclPkg(kpkg))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, len(outdatedDeps), 1)
+	assert.Equal(t, outdatedDeps[0].Name, "helloworld")
+	assert.Equal(t, outdatedDeps[0].Source, "oci")
+	assert.Equal(t, outdatedDeps[0].Current, "0.1.0")
+	assert.Assert(t, outdatedDeps[0].LatestCompatible != "")
+	assert.Assert(t, outdatedDeps[0].Latest != "")
+	assert.Equal(t, outdatedDeps[0].IsOutdated(), true)
+}
+
+func TestOutdatedWithGitBranch(t *testing.T) {
+	pkgPath := filepath.Join(getTestDir("test_outdated"), "pkg_git_branch")
+
+	kpmcli, err := NewKpmClient()
+	assert.NilError(t, err)
+	kpkg, err := kpmcli.LoadPkgFromPath(pkgPath)
+	assert.NilError(t, err)
+
+	outdatedDeps, err := kpmcli.Outdated(WithOutdatedKclPkg(kpkg))
+	assert.NilError(t, err)
+
+	// The branch is not compared with the tags of the git repo.
+	assert.Equal(t, len(outdatedDeps), 1)
+	assert.Equal(t, outdatedDeps[0].Name, "flask_manifests")
+	assert.Equal(t, outdatedDeps[0].Source, "git")
+	assert.Equal(t, outdatedDeps[0].Current, "main")
+	assert.Equal(t, outdatedDeps[0].Pinned, true)
+	assert.Equal(t, outdatedDeps[0].LatestCompatible, "")
+	assert.Equal(t, outdatedDeps[0].IsOutdated(), false)
+}
@@ -0,0 +1,309 @@
+package client
+
+import (
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"kcl-lang.io/kpm/pkg/downloader"
+	"kcl-lang.io/kpm/pkg/git"
+	"kcl-lang.io/kpm/pkg/oci"
+	"kcl-lang.io/kpm/pkg/opt"
+	pkg "kcl-lang.io/kpm/pkg/package"
+	"kcl-lang.io/kpm/pkg/provenance"
+	"kcl-lang.io/kpm/pkg/reporter"
+	"kcl-lang.io/kpm/pkg/signature"
+	"kcl-lang.io/kpm/pkg/utils"
+	"kcl-lang.io/kpm/pkg/version"
+)
+
+// ProvenanceOptions is the option for generating the provenance of the package pushed.
+type ProvenanceOptions struct {
+	KclPkg *pkg.KclPkg
+	// TarPath is the path of the package tar pushed.
+	TarPath string
+	// StartedOn is the time the package starts to be built.
+	StartedOn time.Time
+	// Signer is the private key to sign the provenance as a DSSE envelope.
+	// The provenance is attached unsigned if it is nil.
+	Signer crypto.Signer
+}
+
+type ProvenanceOption func(*ProvenanceOptions) error
+
+// WithProvenanceKclPkg sets the kcl package pushed.
+func WithProvenanceKclPkg(kclPkg *pkg.KclPkg) ProvenanceOption {
+	return func(opts *ProvenanceOptions) error {
+		if kclPkg == nil {
+			return fmt.Errorf("kclPkg cannot be nil")
+		}
+		opts.KclPkg = kclPkg
+		return nil
+	}
+}
+
+// WithProvenanceTarPath sets the path of the package tar pushed.
+func WithProvenanceTarPath(tarPath string) ProvenanceOption {
+	return func(opts *ProvenanceOptions) error {
+		opts.TarPath = tarPath
+		return nil
+	}
+}
+
+// WithProvenanceStartedOn sets the time the package starts to be built.
+func WithProvenanceStartedOn(startedOn time.Time) ProvenanceOption {
+	return func(opts *ProvenanceOptions) error {
+		opts.StartedOn = startedOn
+		return nil
+	}
+}
+
+// WithProvenanceSigner sets the private key to sign the provenance.
+func WithProvenanceSigner(signer crypto.Signer) ProvenanceOption {
+	return func(opts *ProvenanceOptions) error {
+		opts.Signer = signer
+		return nil
+	}
+}
+
+// AttachProvenance will generate the provenance of the package pushed to 'ociOpts',
+// and attach it to the package as an oci referrer.
+// If the signer is set, the provenance is attached as a DSSE envelope signed by it.
+func (c *KpmClient) AttachProvenance(ociOpts *opt.OciOptions, options ...ProvenanceOption) error {
+	opts := &ProvenanceOptions{}
+	for _, option := range options {
+		if err := option(opts); err != nil {
+			return err
+		}
+	}
+	if opts.KclPkg == nil || len(opts.TarPath) == 0 {
+		return fmt.Errorf("the package to generate the provenance for is not specified")
+	}
+	kclPkg := opts.KclPkg
+	ref := fmt.Sprintf("%s:%s", utils.JoinPath(ociOpts.Reg, ociOpts.Repo), ociOpts.Tag)
+
+	manifestJson, err := c.FetchOciManifestIntoJsonStr(opt.OciFetchOptions{OciOptions: *ociOpts})
+	if err != nil {
+		return reporter.NewErrorEvent(reporter.FailedPush, err, fmt.Sprintf("failed to fetch the manifest of '%s'", ref))
+	}
+
+	tarDigest, err := fileDigest(opts.TarPath)
+	if err != nil {
+		return reporter.NewErrorEvent(reporter.FailedPush, err, fmt.Sprintf("failed to compute the digest of '%s'", opts.TarPath))
+	}
+
+	info := &provenance.BuildInfo{
+		Name:           kclPkg.GetPkgName(),
+		Version:        kclPkg.GetPkgVersion(),
+		Edition:        kclPkg.GetPkgEdition(),
+		Reference:      ref,
+		ManifestDigest: digest.FromString(manifestJson).String(),
+		TarName:        filepath.Base(opts.TarPath),
+		TarDigest:      tarDigest.String(),
+		KpmVersion:     version.GetVersionInStr(),
+		Dependencies:   provenanceDeps(&kclPkg.Dependencies),
+		StartedOn:      opts.StartedOn,
+		FinishedOn:     time.Now(),
+	}
+	// The package not in a git repo, e.g. pushed from a tar, has no source.
+	if url, commit, err := git.SourceOf(kclPkg.HomePath); err == nil {
+		info.SourceUrl, info.SourceCommit = url, commit
+		info.SourceDirty, err = git.IsDirty(kclPkg.HomePath)
+		if err != nil {
+			return reporter.NewErrorEvent(reporter.FailedPush, err, fmt.Sprintf("failed to get the git status of '%s'", kclPkg.HomePath))
+		}
+		if info.SourceDirty {
+			reporter.ReportMsgTo(
+				fmt.Sprintf("warning: '%s' has the changes not committed to '%s', the provenance records the source as dirty", kclPkg.HomePath, commit),
+				c.logWriter,
+			)
+		}
+	}
+
+	content, err := json.Marshal(provenance.NewStatement(info))
+	if err != nil {
+		return reporter.NewErrorEvent(reporter.Bug, err, "internal bugs, please contact us to fix it.")
+	}
+	mediaType := provenance.PROVENANCE_ARTIFACT_TYPE
+	if opts.Signer != nil {
+		envelope, err := signature.SignEnvelope(opts.Signer, provenance.PROVENANCE_ARTIFACT_TYPE, content)
+		if err != nil {
+			return reporter.NewErrorEvent(reporter.FailedSign, err, fmt.Sprintf("failed to sign the provenance of '%s'", ref))
+		}
+		content, err = json.Marshal(envelope)
+		if err != nil {
+			return reporter.NewErrorEvent(reporter.Bug, err, "internal bugs, please contact us to fix it.")
+		}
+		mediaType = signature.DSSE_MEDIA_TYPE
+	}
+
+	ociCli, err := c.newOciClient(&downloader.Oci{Reg: ociOpts.Reg, Repo: ociOpts.Repo, Tag: ociOpts.Tag})
+	if err != nil {
+		return err
+	}
+	_, err = ociCli.PushReferrer(ociOpts.Tag, provenance.PROVENANCE_ARTIFACT_TYPE, mediaType, content, nil)
+	return err
+}
+
+// VerifyProvenance will check the provenance attached to the version of the package published in the oci registry,
+// and return the provenance verified.
+// The provenance is verified if its subjects are the manifest of the version and the package tar in the manifest.
+// If the public keys 'keyPaths' are given or the signature policy in 'kpm.json' applies to the repo,
+// only the provenance in the DSSE envelope signed by one of the keys is verified.
+func (c *KpmClient) VerifyProvenance(ociSource *downloader.Oci, keyPaths ...string) (*provenance.Statement, error) {
+	if ociSource == nil || len(ociSource.Tag) == 0 {
+		return nil, reporter.NewErrorEvent(reporter.InvalidPkgRef, fmt.Errorf("the version of the package to verify is not specified"))
+	}
+	ref := fmt.Sprintf("%s:%s", utils.JoinPath(ociSource.Reg, ociSource.Repo), ociSource.Tag)
+
+	manifestJson, err := c.FetchOciManifestIntoJsonStr(opt.OciFetchOptions{
+		OciOptions: opt.OciOptions{
+			Reg:  ociSource.Reg,
+			Repo: ociSource.Repo,
+			Tag:  ociSource.Tag,
+		},
+	})
+	if err != nil {
+		return nil, reporter.NewErrorEvent(reporter.FailedGetPkg, err, fmt.Sprintf("failed to fetch the manifest of '%s'", ref))
+	}
+	var manifest v1.Manifest
+	if err := json.Unmarshal([]byte(manifestJson), &manifest); err != nil {
+		return nil, reporter.NewErrorEvent(reporter.FailedVerifyProvenance, err, fmt.Sprintf("failed to parse the manifest of '%s'", ref))
+	}
+	tarDigest, err := tarLayerDigest(&manifest)
+	if err != nil {
+		return nil, reporter.NewErrorEvent(reporter.FailedVerifyProvenance, err, fmt.Sprintf("'%s' is not a kcl package", ref))
+	}
+
+	if policy := c.GetSettings().SignaturePolicyFor(ociSource.Reg, ociSource.Repo); policy != nil {
+		keyPaths = append(keyPaths, policy.PublicKeys...)
+	}
+	var publicKeys []crypto.PublicKey
+	for _, keyPath := range keyPaths {
+		publicKey, err := signature.LoadPublicKey(keyPath)
+		if err != nil {
+			return nil, reporter.NewErrorEvent(reporter.FailedVerifyProvenance, err, fmt.Sprintf("failed to load the public key '%s'", keyPath))
+		}
+		publicKeys = append(publicKeys, publicKey)
+	}
+
+	ociCli, err := c.newOciClient(ociSource)
+	if err != nil {
+		return nil, err
+	}
+	contents, err := ociCli.FetchReferrerContents(ociSource.Tag, provenance.PROVENANCE_ARTIFACT_TYPE)
+	if err != nil {
+		return nil, err
+	}
+
+	manifestDigest := digest.FromString(manifestJson).String()
+	lastErr := fmt.Errorf("no provenance is found")
+	for _, content := range contents {
+		payload, signed, err := provenancePayload(content, publicKeys)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		statement, err := provenance.ParseStatement(payload)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if err := statement.Verify(manifestDigest, tarDigest); err != nil {
+			lastErr = err
+			continue
+		}
+		if !signed {
+			reporter.ReportMsgTo(fmt.Sprintf("warning: the signature of the provenance of '%s' is not verified, no public key is provided", ref), c.logWriter)
+		}
+		return statement, nil
+	}
+	return nil, reporter.NewErrorEvent(reporter.FailedVerifyProvenance, lastErr, fmt.Sprintf("failed to verify the provenance of '%s'", ref))
+}
+
+// provenancePayload returns the statement in the provenance content and whether the signature of it is verified.
+// The content is the statement or the DSSE envelope of the statement.
+// If the public keys are given, only the envelope signed by one of them is accepted.
+func provenancePayload(content []byte, publicKeys []crypto.PublicKey) ([]byte, bool, error) {
+	envelope, err := signature.ParseEnvelope(content)
+	if err != nil {
+		if len(publicKeys) != 0 {
+			return nil, false, fmt.Errorf("the provenance is not signed")
+		}
+		return content, false, nil
+	}
+	if envelope.PayloadType != provenance.PROVENANCE_ARTIFACT_TYPE {
+		return nil, false, fmt.Errorf("unsupported payload type '%s' of the provenance", envelope.PayloadType)
+	}
+	if len(publicKeys) == 0 {
+		payload, err := envelope.DecodePayload()
+		return payload, false, err
+	}
+	payload, err := signature.VerifyEnvelope(envelope, publicKeys)
+	if err != nil {
+		return nil, false, fmt.Errorf("the provenance is not signed by the trusted keys: %w", err)
+	}
+	return payload, true, nil
+}
+
+// provenanceDeps returns the dependencies resolved in kcl.mod.lock with the digests, sorted by the name.
+func provenanceDeps(deps *pkg.Dependencies) []provenance.ResourceDescriptor {
+	var resolved []provenance.ResourceDescriptor
+	if deps == nil || deps.Deps == nil {
+		return resolved
+	}
+	for _, name := range deps.Deps.Keys() {
+		dep, _ := deps.Deps.Get(name)
+		resource := provenance.ResourceDescriptor{
+			Name:   dep.Name,
+			Uri:    depLocation(&dep),
+			Digest: map[string]string{},
+		}
+		if dep.Source.Oci != nil && len(dep.Source.Oci.Digest) != 0 {
+			resource.Digest = provenance.DigestSet(dep.Source.Oci.Digest)
+		}
+		if dep.Source.Git != nil {
+			commit := dep.Source.Git.Commit
+			if len(commit) == 0 {
+				commit = dep.Source.Git.ResolvedCommit
+			}
+			if len(commit) != 0 {
+				resource.Digest[provenance.DIGEST_GIT_COMMIT] = commit
+			}
+		}
+		if len(dep.Sum) != 0 {
+			resource.Digest[provenance.DIGEST_KCL_SUM] = dep.Sum
+		}
+		resolved = append(resolved, resource)
+	}
+	sort.Slice(resolved, func(i, j int) bool {
+		return resolved[i].Name < resolved[j].Name
+	})
+	return resolved
+}
+
+// tarLayerDigest returns the digest of the package tar in the manifest of the package.
+func tarLayerDigest(manifest *v1.Manifest) (string, error) {
+	for _, layer := range manifest.Layers {
+		if layer.MediaType == oci.DEFAULT_OCI_ARTIFACT_TYPE {
+			return layer.Digest.String(), nil
+		}
+	}
+	return "", fmt.Errorf("no package tar is found in the manifest")
+}
+
+// fileDigest returns the sha256 digest of the file.
+func fileDigest(path string) (digest.Digest, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	return digest.FromReader(file)
+}
@@ -0,0 +1,53 @@
+package client
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"kcl-lang.io/kpm/pkg/provenance"
+	"kcl-lang.io/kpm/pkg/signature"
+)
+
+func TestProvenancePayload(t *testing.T) {
+	statement := []byte(`{"_type":"https://in-toto.io/Statement/v1"}`)
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NilError(t, err)
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NilError(t, err)
+
+	envelope, err := signature.SignEnvelope(key, provenance.PROVENANCE_ARTIFACT_TYPE, statement)
+	assert.NilError(t, err)
+	signed, err := json.Marshal(envelope)
+	assert.NilError(t, err)
+
+	// Without the public keys, both the statement and the envelope are accepted unverified.
+	payload, verified, err := provenancePayload(statement, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, string(payload), string(statement))
+	assert.Equal(t, verified, false)
+	payload, verified, err = provenancePayload(signed, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, string(payload), string(statement))
+	assert.Equal(t, verified, false)
+
+	// With the public keys, only the envelope signed by one of them is accepted.
+	payload, verified, err = provenancePayload(signed, []crypto.PublicKey{otherKey.Public(), key.Public()})
+	assert.NilError(t, err)
+	assert.Equal(t, string(payload), string(statement))
+	assert.Equal(t, verified, true)
+	_, _, err = provenancePayload(signed, []crypto.PublicKey{otherKey.Public()})
+	assert.ErrorContains(t, err, "the provenance is not signed by the trusted keys")
+	_, _, err = provenancePayload(statement, []crypto.PublicKey{key.Public()})
+	assert.ErrorContains(t, err, "the provenance is not signed")
+
+	envelope, err = signature.SignEnvelope(key, "application/json", statement)
+	assert.NilError(t, err)
+	other, err := json.Marshal(envelope)
+	assert.NilError(t, err)
+	_, _, err = provenancePayload(other, []crypto.PublicKey{key.Public()})
+	assert.ErrorContains(t, err, "unsupported payload type 'application/json'")
+}
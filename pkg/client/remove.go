@@ -0,0 +1,211 @@
+package client
+
+import (
+	"fmt"
+	"os"
+
+	pkg "kcl-lang.io/kpm/pkg/package"
+	"kcl-lang.io/kpm/pkg/reporter"
+	"kcl-lang.io/kpm/pkg/resolver"
+	"kcl-lang.io/kpm/pkg/utils"
+)
+
+// RemoveOptions is the option for removing dependencies from a package.
+// Removing a dependency means deleting it from kcl.mod and
+// cleaning the dependencies in kcl.mod.lock that are no longer required.
+type RemoveOptions struct {
+	// KclPkg is the package to remove the dependencies from.
+	KclPkg *pkg.KclPkg
+	// DepNames is the names of the dependencies to be removed.
+	DepNames []string
+	// PruneVendor is the flag to remove the dependencies from the vendor directory.
+	PruneVendor bool
+}
+
+type RemoveOption func(*RemoveOptions) error
+
+// WithRemoveKclPkg sets the kcl package to remove the dependencies from.
+func WithRemoveKclPkg(kpkg *pkg.KclPkg) RemoveOption {
+	return func(opts *RemoveOptions) error {
+		opts.KclPkg = kpkg
+		return nil
+	}
+}
+
+// WithRemoveDepName adds the name of a dependency to be removed.
+func WithRemoveDepName(depName string) RemoveOption {
+	return func(opts *RemoveOptions) error {
+		opts.DepNames = append(opts.DepNames, depName)
+		return nil
+	}
+}
+
+// WithRemoveDepNames sets the names of the dependencies to be removed.
+func WithRemoveDepNames(depNames []string) RemoveOption {
+	return func(opts *RemoveOptions) error {
+		opts.DepNames = depNames
+		return nil
+	}
+}
+
+// WithPruneVendor sets the flag to remove the dependencies from the vendor directory.
+func WithPruneVendor(pruneVendor bool) RemoveOption {
+	return func(opts *RemoveOptions) error {
+		opts.PruneVendor = pruneVendor
+		return nil
+	}
+}
+
+// Remove will remove the dependencies from kcl.mod, re-resolve the rest of the dependencies
+// and drop the dependencies that are no longer required from kcl.mod.lock.
+func (c *KpmClient) Remove(options ...RemoveOption) error {
+	opts := &RemoveOptions{}
+	for _, option := range options {
+		if err := option(opts); err != nil {
+			return err
+		}
+	}
+
+	kpkg := opts.KclPkg
+	if kpkg == nil {
+		return fmt.Errorf("kcl package is nil")
+	}
+
+	if len(opts.DepNames) == 0 {
+		return reporter.NewErrorEvent(reporter.InvalidCmd, fmt.Errorf("no dependency to be removed"))
+	}
+
+	modDeps := kpkg.ModFile.Dependencies.Deps
+	if modDeps == nil {
+		return fmt.Errorf("kcl.mod dependencies is nil")
+	}
+	lockDeps := kpkg.Dependencies.Deps
+	if lockDeps == nil {
+		return fmt.Errorf("kcl.mod.lock dependencies is nil")
+	}
+
+	// 1. Remove the dependencies from kcl.mod.
+	for _, depName := range opts.DepNames {
+		if kpkg.ModFile.IsDevDep(depName) {
+			reporter.ReportEventTo(
+				reporter.NewEvent(reporter.RemoveDep, fmt.Sprintf("removing dev-dependency '%s'", depName)),
+				c.logWriter,
+			)
+			kpkg.ModFile.DevDependencies.Deps.Delete(depName)
+			continue
+		}
+		if _, ok := modDeps.Get(depName); !ok {
+			return reporter.NewErrorEvent(
+				reporter.DependencyNotFound,
+				fmt.Errorf("dependency '%s' not found in 'kcl.mod'", depName),
+			)
+		}
+		reporter.ReportEventTo(
+			reporter.NewEvent(reporter.RemoveDep, fmt.Sprintf("removing dependency '%s'", depName)),
+			c.logWriter,
+		)
+		modDeps.Delete(depName)
+	}
+
+	// 2. Collect all the dependencies still required by the rest of the dependencies in kcl.mod.
+	requiredDeps, err := c.resolveRequiredDeps(kpkg, true)
+	if err != nil {
+		return err
+	}
+
+	// 3. Clean the dependencies in kcl.mod.lock which are no longer required.
+	// The dependents of the dependencies still required are recomputed,
+	// because the dependencies removed no longer require them.
+	var removedDeps []pkg.Dependency
+	for _, depName := range lockDeps.Keys() {
+		dep, ok := lockDeps.Get(depName)
+		if !ok {
+			return fmt.Errorf("failed to get dependency %s", depName)
+		}
+		if dependents, ok := requiredDeps[depName]; ok {
+			dep.Dependents = nil
+			dep.AddDependents(dependents...)
+			lockDeps.Set(depName, dep)
+			continue
+		}
+		removedDeps = append(removedDeps, dep)
+		lockDeps.Delete(depName)
+	}
+
+	// 4. Remove the dependencies that are no longer required from the vendor directory.
+	if opts.PruneVendor && utils.DirExists(kpkg.LocalVendorPath()) {
+		for _, dep := range removedDeps {
+			if dep.IsFromLocal() {
+				continue
+			}
+			vendorFullPath := c.getDepStorePath(kpkg.HomePath, &dep, true)
+			if utils.DirExists(vendorFullPath) {
+				err := os.RemoveAll(vendorFullPath)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	err = kpkg.UpdateModAndLockFile()
+	if err != nil {
+		return err
+	}
+
+	reporter.ReportMsgTo(
+		fmt.Sprintf("remove dependencies %v successfully", opts.DepNames),
+		c.logWriter,
+	)
+
+	return nil
+}
+
+// resolveRequiredDeps will traverse the dependency graph of the package by kcl.mod,
+// and return the names of all the dependencies directly or indirectly required by the package,
+// each with the names of the packages requiring it, i.e. the dependents in kcl.mod.lock.
+// The dev-dependencies are traversed only if 'withDevDeps' is true.
+func (c *KpmClient) resolveRequiredDeps(kpkg *pkg.KclPkg, withDevDeps bool) (map[string][]string, error) {
+	requiredDeps := make(map[string][]string)
+
+	depResolver := resolver.DepsResolver{
+		DefaultCachePath:      c.homePath,
+		InsecureSkipTLSverify: c.insecureSkipTLSverify,
+		Downloader:            c.DepDownloader,
+		Settings:              &c.settings,
+		LogWriter:             c.logWriter,
+		FetchModFunc:          c.fetchDepModFile,
+		Replaces:              kpkg.ModFile.Replaces,
+		LockDeps:              &kpkg.Dependencies,
+	}
+	depResolver.ResolveFuncs = append(depResolver.ResolveFuncs, func(dep *pkg.Dependency, parentPkg *pkg.KclPkg) error {
+		requiredDeps[dep.Name] = append(requiredDeps[dep.Name], parentPkg.GetPkgName())
+		return nil
+	})
+
+	modDeps := &kpkg.ModFile.Dependencies
+	if withDevDeps {
+		modDeps = kpkg.ModFile.DepsWithDevDeps()
+	}
+	for _, depName := range modDeps.Deps.Keys() {
+		dep, ok := modDeps.Deps.Get(depName)
+		if !ok {
+			return nil, fmt.Errorf("failed to get dependency %s", depName)
+		}
+		requiredDeps[depName] = append(requiredDeps[depName], kpkg.GetPkgName())
+
+		// Get the dependency source, the local path is transformed to an absolute path
+		// and the dependency redirected by the [replace] section is resolved from the replacement.
+		_, depSource := depResolver.ResolveSource(&dep, kpkg.HomePath)
+
+		err := depResolver.Resolve(
+			resolver.WithEnableCache(true),
+			resolver.WithSource(&depSource),
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return requiredDeps, nil
+}
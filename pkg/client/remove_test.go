@@ -0,0 +1,74 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/otiai10/copy"
+	"gotest.tools/v3/assert"
+	"kcl-lang.io/kpm/pkg/utils"
+)
+
+func TestRemove(t *testing.T) {
+	pkgPath := filepath.Join(getTestDir("test_remove"), "pkg")
+	modPath := filepath.Join(pkgPath, "kcl.mod")
+	lockPath := filepath.Join(pkgPath, "kcl.mod.lock")
+
+	if err := copy.Copy(modPath+".bk", modPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := copy.Copy(lockPath+".bk", lockPath); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = copy.Copy(modPath+".bk", modPath)
+		_ = copy.Copy(lockPath+".bk", lockPath)
+	}()
+
+	kpmcli, err := NewKpmClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kpkg, err := kpmcli.LoadPkgFromPath(pkgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = kpmcli.Remove(
+		WithRemoveKclPkg(kpkg),
+		WithRemoveDepName("not_exist"),
+	)
+	assert.ErrorContains(t, err, "dependency 'not_exist' not found in 'kcl.mod'")
+
+	// 'dep_1' is still required by 'dep_2' after 'dep_0' is removed,
+	// and 'dep_0' is dropped from its dependents in kcl.mod.lock.
+	err = kpmcli.Remove(
+		WithRemoveKclPkg(kpkg),
+		WithRemoveDepName("dep_0"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedMod, err := os.ReadFile(modPath + ".expect")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedModLock, err := os.ReadFile(lockPath + ".expect")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotMod, err := os.ReadFile(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotModLock, err := os.ReadFile(lockPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, utils.RmNewline(string(expectedMod)), utils.RmNewline(string(gotMod)))
+	assert.Equal(t, utils.RmNewline(string(expectedModLock)), utils.RmNewline(string(gotModLock)))
+}
@@ -0,0 +1,60 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	pkg "kcl-lang.io/kpm/pkg/package"
+)
+
+func TestResolveReplacedDeps(t *testing.T) {
+	testDir := getTestDir("test_replace")
+	pkgPath := filepath.Join(testDir, "pkg")
+	modPath := filepath.Join(pkgPath, "kcl.mod")
+	lockPath := filepath.Join(pkgPath, "kcl.mod.lock")
+
+	expectedMod, err := os.ReadFile(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.WriteFile(modPath, expectedMod, 0644)
+		_ = os.RemoveAll(lockPath)
+	}()
+
+	kpmcli, err := NewKpmClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kpkg, err := kpmcli.LoadPkgFromPath(pkgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The dependency is resolved from the replacement without downloading.
+	pkgMap, err := kpmcli.ResolveDepsIntoMap(kpkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, pkgMap["helloworld"], filepath.Join(testDir, "helloworld_fork"))
+
+	// kcl.mod is not changed, and kcl.mod.lock records the replacement.
+	gotMod, err := os.ReadFile(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(gotMod), string(expectedMod))
+
+	lockDeps, err := pkg.LoadLockDeps(pkgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lockedDep, ok := lockDeps.Deps.Get("helloworld")
+	assert.Equal(t, ok, true)
+	assert.Equal(t, lockedDep.Version, "0.1.2")
+	assert.Equal(t, lockedDep.Replace, "../helloworld_fork")
+	assert.Equal(t, lockedDep.Oci.Repo, "kcl-lang/helloworld")
+}
@@ -0,0 +1,514 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/mod/module"
+	"kcl-lang.io/kpm/pkg/downloader"
+	pkg "kcl-lang.io/kpm/pkg/package"
+	"kcl-lang.io/kpm/pkg/reporter"
+	"kcl-lang.io/kpm/pkg/utils"
+)
+
+const (
+	SBOM_FORMAT_SPDX_JSON      = "spdx-json"
+	SBOM_FORMAT_CYCLONEDX_JSON = "cyclonedx-json"
+)
+
+// SbomFormats are the output formats of the SBOM.
+var SbomFormats = []string{
+	SBOM_FORMAT_SPDX_JSON,
+	SBOM_FORMAT_CYCLONEDX_JSON,
+}
+
+// The media types of the SBOM attached to the package in the oci registry.
+var sbomMediaTypes = map[string]string{
+	SBOM_FORMAT_SPDX_JSON:      "application/spdx+json",
+	SBOM_FORMAT_CYCLONEDX_JSON: "application/vnd.cyclonedx+json",
+}
+
+const NOASSERTION = "NOASSERTION"
+
+// The checksum of kpm is not a checksum of the package file, so it is recorded as a kpm specific reference,
+// the SPDX external reference type and the CycloneDX property name of it.
+const (
+	SPDX_REF_TYPE_KPM_SUM      = "kpm-sum"
+	CYCLONEDX_PROPERTY_KPM_SUM = "kpm:sum"
+)
+
+// SbomComponent is a package in the SBOM.
+type SbomComponent struct {
+	Name    string
+	Version string
+	// Location is where the package is downloaded from, e.g. the oci url or the git url with the reference.
+	Location string
+	// Purl is the package url of the package.
+	Purl string
+	// Digest is the digest of the oci manifest of the package recorded in kcl.mod.lock, e.g. 'sha256:...'.
+	Digest string
+	// Sum is the checksum of the package recorded in kcl.mod.lock.
+	Sum     string
+	License string
+}
+
+// Ref returns the reference of the component in the SBOM in the format of '<name>@<version>'.
+func (c *SbomComponent) Ref() string {
+	return formatModule(module.Version{Path: c.Name, Version: c.Version})
+}
+
+// Sbom is the software bill of materials of a kcl package and the dependencies resolved.
+type Sbom struct {
+	// Root is the package the SBOM is generated for.
+	Root SbomComponent
+	// Components are the dependencies resolved, sorted by the name.
+	Components []SbomComponent
+	// Dependencies are the references of the components required by each component.
+	Dependencies map[string][]string
+	// Created is the time the SBOM is generated.
+	Created time.Time
+	// ID is the unique id of the SBOM document.
+	ID string
+}
+
+// SbomOptions is the option for generating the SBOM.
+type SbomOptions struct {
+	KclPkg *pkg.KclPkg
+}
+
+type SbomOption func(*SbomOptions) error
+
+// WithSbomKclPkg sets the kcl package to generate the SBOM for.
+func WithSbomKclPkg(kclPkg *pkg.KclPkg) SbomOption {
+	return func(opts *SbomOptions) error {
+		if kclPkg == nil {
+			return fmt.Errorf("kclPkg cannot be nil")
+		}
+		opts.KclPkg = kclPkg
+		return nil
+	}
+}
+
+// Sbom will resolve the dependencies of the kcl package and return the SBOM of them.
+// The dependencies are walked from kcl.mod.lock and the dependency graph.
+func (c *KpmClient) Sbom(options ...SbomOption) (*Sbom, error) {
+	opts := &SbomOptions{}
+	for _, option := range options {
+		if err := option(opts); err != nil {
+			return nil, err
+		}
+	}
+	if opts.KclPkg == nil {
+		return nil, fmt.Errorf("the package to generate the SBOM for is not specified")
+	}
+	kclPkg := opts.KclPkg
+
+	deps, depGraph, err := c.InitGraphAndDownloadDeps(kclPkg)
+	if err != nil {
+		return nil, err
+	}
+
+	sbom := &Sbom{
+		Root: SbomComponent{
+			Name:     kclPkg.GetPkgName(),
+			Version:  kclPkg.GetPkgVersion(),
+			Location: kclPkg.ModFile.Pkg.Repository,
+			License:  kclPkg.ModFile.Pkg.License,
+		},
+		Dependencies: make(map[string][]string),
+		Created:      time.Now().UTC(),
+		ID:           uuid.NewString(),
+	}
+
+	for _, name := range deps.Deps.Keys() {
+		dep, _ := deps.Deps.Get(name)
+		component := SbomComponent{
+			Name:     dep.Name,
+			Version:  dep.Version,
+			Location: depLocation(&dep),
+			Purl:     depPurl(&dep),
+			Sum:      dep.Sum,
+		}
+		if dep.Source.Oci != nil {
+			component.Digest = dep.Source.Oci.Digest
+		}
+		// The license is read from the kcl.mod of the dependency downloaded.
+		if len(dep.LocalFullPath) != 0 {
+			if modFile, err := c.LoadModFile(dep.LocalFullPath); err == nil {
+				component.License = modFile.Pkg.License
+			}
+		}
+		sbom.Components = append(sbom.Components, component)
+	}
+	sort.Slice(sbom.Components, func(i, j int) bool {
+		return sbom.Components[i].Ref() < sbom.Components[j].Ref()
+	})
+
+	adjMap, err := depGraph.AdjacencyMap()
+	if err != nil {
+		return nil, err
+	}
+	for m, edges := range adjMap {
+		for _, child := range sortedModules(edges) {
+			sbom.Dependencies[formatModule(m)] = append(sbom.Dependencies[formatModule(m)], formatModule(child))
+		}
+	}
+	return sbom, nil
+}
+
+// Format renders the SBOM in the format.
+func (s *Sbom) Format(format string) ([]byte, error) {
+	switch format {
+	case SBOM_FORMAT_SPDX_JSON:
+		return s.SpdxJson()
+	case SBOM_FORMAT_CYCLONEDX_JSON:
+		return s.CycloneDxJson()
+	default:
+		return nil, fmt.Errorf("invalid SBOM format '%s', only %s are supported", format, strings.Join(SbomFormats, ", "))
+	}
+}
+
+// AttachSbom will attach the SBOM in the format to the version of the package published in the oci registry as an oci referrer.
+func (c *KpmClient) AttachSbom(ociSource *downloader.Oci, format string, content []byte) error {
+	if ociSource == nil || len(ociSource.Tag) == 0 {
+		return reporter.NewErrorEvent(reporter.InvalidPkgRef, fmt.Errorf("the version of the package to attach the SBOM to is not specified"))
+	}
+	mediaType, ok := sbomMediaTypes[format]
+	if !ok {
+		return fmt.Errorf("invalid SBOM format '%s', only %s are supported", format, strings.Join(SbomFormats, ", "))
+	}
+
+	ociCli, err := c.newOciClient(ociSource)
+	if err != nil {
+		return err
+	}
+	_, err = ociCli.PushReferrer(ociSource.Tag, mediaType, mediaType, content, nil)
+	return err
+}
+
+type spdxDocument struct {
+	SpdxVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	Name             string            `json:"name"`
+	SPDXID           string            `json:"SPDXID"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	Checksums        []spdxChecksum    `json:"checksums,omitempty"`
+	LicenseConcluded string            `json:"licenseConcluded"`
+	LicenseDeclared  string            `json:"licenseDeclared"`
+	CopyrightText    string            `json:"copyrightText"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SpdxElementId      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSpdxElement string `json:"relatedSpdxElement"`
+}
+
+// SpdxJson renders the SBOM as a SPDX 2.3 document in json.
+func (s *Sbom) SpdxJson() ([]byte, error) {
+	spdxIds := make(map[string]string)
+	spdxId := func(ref string) string {
+		if id, ok := spdxIds[ref]; ok {
+			return id
+		}
+		// The SPDX id only consists of letters, numbers, '.' and '-'.
+		id := "SPDXRef-Package-" + strings.Map(func(r rune) rune {
+			if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '.' || r == '-' {
+				return r
+			}
+			return '-'
+		}, ref)
+		spdxIds[ref] = id
+		return id
+	}
+
+	doc := spdxDocument{
+		SpdxVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              s.Root.Ref(),
+		DocumentNamespace: fmt.Sprintf("https://kcl-lang.io/spdx/%s-%s", url.PathEscape(s.Root.Ref()), s.ID),
+		CreationInfo: spdxCreationInfo{
+			Created:  s.Created.UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: kpm"},
+		},
+		Packages: []spdxPackage{},
+		Relationships: []spdxRelationship{
+			{
+				SpdxElementId:      "SPDXRef-DOCUMENT",
+				RelationshipType:   "DESCRIBES",
+				RelatedSpdxElement: spdxId(s.Root.Ref()),
+			},
+		},
+	}
+
+	for _, component := range append([]SbomComponent{s.Root}, s.Components...) {
+		spdxPkg := spdxPackage{
+			Name:             component.Name,
+			SPDXID:           spdxId(component.Ref()),
+			VersionInfo:      component.Version,
+			DownloadLocation: orNoAssertion(component.Location),
+			LicenseConcluded: NOASSERTION,
+			LicenseDeclared:  orNoAssertion(component.License),
+			CopyrightText:    NOASSERTION,
+		}
+		if digest := digestInHex(component.Digest); len(digest) != 0 {
+			spdxPkg.Checksums = []spdxChecksum{{Algorithm: "SHA256", ChecksumValue: digest}}
+		}
+		if len(component.Purl) != 0 {
+			spdxPkg.ExternalRefs = append(spdxPkg.ExternalRefs, spdxExternalRef{
+				ReferenceCategory: "PACKAGE-MANAGER",
+				ReferenceType:     "purl",
+				ReferenceLocator:  component.Purl,
+			})
+		}
+		if len(component.Sum) != 0 {
+			spdxPkg.ExternalRefs = append(spdxPkg.ExternalRefs, spdxExternalRef{
+				ReferenceCategory: "OTHER",
+				ReferenceType:     SPDX_REF_TYPE_KPM_SUM,
+				ReferenceLocator:  component.Sum,
+			})
+		}
+		doc.Packages = append(doc.Packages, spdxPkg)
+	}
+
+	for _, from := range sortedKeys(s.Dependencies) {
+		for _, to := range s.Dependencies[from] {
+			doc.Relationships = append(doc.Relationships, spdxRelationship{
+				SpdxElementId:      spdxId(from),
+				RelationshipType:   "DEPENDS_ON",
+				RelatedSpdxElement: spdxId(to),
+			})
+		}
+	}
+
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+type cycloneDxDocument struct {
+	BomFormat    string                `json:"bomFormat"`
+	SpecVersion  string                `json:"specVersion"`
+	SerialNumber string                `json:"serialNumber"`
+	Version      int                   `json:"version"`
+	Metadata     cycloneDxMetadata     `json:"metadata"`
+	Components   []cycloneDxComponent  `json:"components"`
+	Dependencies []cycloneDxDependency `json:"dependencies"`
+}
+
+type cycloneDxMetadata struct {
+	Timestamp string             `json:"timestamp"`
+	Tools     []cycloneDxTool    `json:"tools"`
+	Component cycloneDxComponent `json:"component"`
+}
+
+type cycloneDxTool struct {
+	Name string `json:"name"`
+}
+
+type cycloneDxComponent struct {
+	Type               string                       `json:"type"`
+	BomRef             string                       `json:"bom-ref"`
+	Name               string                       `json:"name"`
+	Version            string                       `json:"version,omitempty"`
+	Purl               string                       `json:"purl,omitempty"`
+	Hashes             []cycloneDxHash              `json:"hashes,omitempty"`
+	Licenses           []cycloneDxLicense           `json:"licenses,omitempty"`
+	ExternalReferences []cycloneDxExternalReference `json:"externalReferences,omitempty"`
+	Properties         []cycloneDxProperty          `json:"properties,omitempty"`
+}
+
+type cycloneDxHash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+type cycloneDxLicense struct {
+	Expression string `json:"expression"`
+}
+
+type cycloneDxExternalReference struct {
+	Type string `json:"type"`
+	Url  string `json:"url"`
+}
+
+type cycloneDxProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type cycloneDxDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+// CycloneDxJson renders the SBOM as a CycloneDX 1.5 document in json.
+func (s *Sbom) CycloneDxJson() ([]byte, error) {
+	toComponent := func(component SbomComponent, componentType string) cycloneDxComponent {
+		cdxComponent := cycloneDxComponent{
+			Type:    componentType,
+			BomRef:  component.Ref(),
+			Name:    component.Name,
+			Version: component.Version,
+			Purl:    component.Purl,
+		}
+		if digest := digestInHex(component.Digest); len(digest) != 0 {
+			cdxComponent.Hashes = []cycloneDxHash{{Alg: "SHA-256", Content: digest}}
+		}
+		if len(component.Sum) != 0 {
+			cdxComponent.Properties = []cycloneDxProperty{{Name: CYCLONEDX_PROPERTY_KPM_SUM, Value: component.Sum}}
+		}
+		if len(component.License) != 0 {
+			cdxComponent.Licenses = []cycloneDxLicense{{Expression: component.License}}
+		}
+		if len(component.Location) != 0 {
+			refType := "distribution"
+			if strings.HasPrefix(component.Location, "git+") {
+				refType = "vcs"
+			}
+			cdxComponent.ExternalReferences = []cycloneDxExternalReference{{Type: refType, Url: component.Location}}
+		}
+		return cdxComponent
+	}
+
+	doc := cycloneDxDocument{
+		BomFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + s.ID,
+		Version:      1,
+		Metadata: cycloneDxMetadata{
+			Timestamp: s.Created.UTC().Format(time.RFC3339),
+			Tools:     []cycloneDxTool{{Name: "kpm"}},
+			Component: toComponent(s.Root, "application"),
+		},
+		Components:   []cycloneDxComponent{},
+		Dependencies: []cycloneDxDependency{},
+	}
+	for _, component := range s.Components {
+		doc.Components = append(doc.Components, toComponent(component, "library"))
+	}
+	for _, component := range append([]SbomComponent{s.Root}, s.Components...) {
+		doc.Dependencies = append(doc.Dependencies, cycloneDxDependency{
+			Ref:       component.Ref(),
+			DependsOn: s.Dependencies[component.Ref()],
+		})
+	}
+
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// depLocation returns where the dependency is downloaded from,
+// e.g. 'oci://ghcr.io/kcl-lang/helloworld@sha256:...' or 'git+https://github.com/kcl-lang/flask-demo-kcl-manifests.git@<commit>'.
+func depLocation(dep *pkg.Dependency) string {
+	if dep.Source.Oci != nil {
+		location := dep.Source.Oci.IntoOciUrl()
+		if len(dep.Source.Oci.Digest) != 0 {
+			return location + "@" + dep.Source.Oci.Digest
+		}
+		if len(dep.Source.Oci.Tag) != 0 {
+			return location + ":" + dep.Source.Oci.Tag
+		}
+		return location
+	}
+	if dep.Source.Git != nil {
+		location := "git+" + dep.Source.Git.Url
+		if ref := dep.Source.Git.GetPinnedReference(); len(ref) != 0 {
+			return location + "@" + ref
+		}
+		return location
+	}
+	return ""
+}
+
+// depPurl returns the package url of the dependency.
+// The oci dependency is 'pkg:oci/<name>@<digest>?repository_url=<reg>/<repo>&tag=<tag>',
+// the git dependency is 'pkg:generic/<name>@<version>?vcs_url=git+<url>@<ref>'.
+func depPurl(dep *pkg.Dependency) string {
+	if dep.Source.Oci != nil {
+		query := url.Values{}
+		query.Set("repository_url", utils.JoinPath(dep.Source.Oci.Reg, dep.Source.Oci.Repo))
+		if len(dep.Source.Oci.Tag) != 0 {
+			query.Set("tag", dep.Source.Oci.Tag)
+		}
+		purl := "pkg:oci/" + url.PathEscape(dep.Name)
+		if len(dep.Source.Oci.Digest) != 0 {
+			purl += "@" + url.PathEscape(dep.Source.Oci.Digest)
+		}
+		return purl + "?" + query.Encode()
+	}
+	if dep.Source.Git != nil {
+		purl := "pkg:generic/" + url.PathEscape(dep.Name)
+		if len(dep.Version) != 0 {
+			purl += "@" + url.PathEscape(dep.Version)
+		}
+		query := url.Values{}
+		query.Set("vcs_url", depLocation(dep))
+		return purl + "?" + query.Encode()
+	}
+	return ""
+}
+
+// digestInHex returns the sha256 of the oci digest in hex, e.g. '3cdd...' of 'sha256:3cdd...'.
+// It returns empty string if the digest is not a sha256 digest.
+func digestInHex(digest string) string {
+	encoded, ok := strings.CutPrefix(digest, "sha256:")
+	if !ok {
+		return ""
+	}
+	if decoded, err := hex.DecodeString(encoded); err != nil || len(decoded) != sha256.Size {
+		return ""
+	}
+	return encoded
+}
+
+// orNoAssertion returns 'NOASSERTION' if the string is empty.
+func orNoAssertion(s string) string {
+	if len(s) == 0 {
+		return NOASSERTION
+	}
+	return s
+}
+
+// sortedKeys returns the keys of the map in order.
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,135 @@
+package client
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	"kcl-lang.io/kpm/pkg/downloader"
+	pkg "kcl-lang.io/kpm/pkg/package"
+)
+
+func testSbom() *Sbom {
+	return &Sbom{
+		Root: SbomComponent{
+			Name:    "test_sbom",
+			Version: "0.0.1",
+			License: "Apache-2.0",
+		},
+		Components: []SbomComponent{
+			{
+				Name:     "helloworld",
+				Version:  "0.1.2",
+				Location: "oci://ghcr.io/kcl-lang/helloworld:0.1.2",
+				Purl:     "pkg:oci/helloworld?repository_url=ghcr.io%2Fkcl-lang%2Fhelloworld&tag=0.1.2",
+				Digest:   "sha256:9b8e2f7a0d3c4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7",
+				Sum:      "PN0OMEV9M8VGFn1CtA/T3bcgZmMJmOo+RkBrLKIWYeQ=",
+				License:  "MIT",
+			},
+		},
+		Dependencies: map[string][]string{
+			"test_sbom@0.0.1": {"helloworld@0.1.2"},
+		},
+		Created: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		ID:      "00000000-0000-0000-0000-000000000000",
+	}
+}
+
+func TestSbomSpdxJson(t *testing.T) {
+	out, err := testSbom().Format(SBOM_FORMAT_SPDX_JSON)
+	assert.NilError(t, err)
+
+	var doc spdxDocument
+	assert.NilError(t, json.Unmarshal(out, &doc))
+	assert.Equal(t, doc.SpdxVersion, "SPDX-2.3")
+	assert.Equal(t, doc.CreationInfo.Created, "2024-01-01T00:00:00Z")
+	assert.Equal(t, len(doc.Packages), 2)
+	assert.Equal(t, doc.Packages[0].SPDXID, "SPDXRef-Package-test-sbom-0.0.1")
+	assert.Equal(t, doc.Packages[0].DownloadLocation, NOASSERTION)
+	assert.Equal(t, doc.Packages[0].LicenseDeclared, "Apache-2.0")
+	assert.Equal(t, doc.Packages[1].DownloadLocation, "oci://ghcr.io/kcl-lang/helloworld:0.1.2")
+	assert.Equal(t, doc.Packages[1].LicenseDeclared, "MIT")
+	// The checksum is the digest of the oci manifest, the checksum of kpm is recorded as a kpm specific reference.
+	assert.Equal(t, doc.Packages[1].Checksums[0].ChecksumValue, "9b8e2f7a0d3c4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7")
+	assert.Equal(t, doc.Packages[1].ExternalRefs[0].ReferenceLocator, testSbom().Components[0].Purl)
+	assert.DeepEqual(t, doc.Packages[1].ExternalRefs[1], spdxExternalRef{
+		ReferenceCategory: "OTHER",
+		ReferenceType:     SPDX_REF_TYPE_KPM_SUM,
+		ReferenceLocator:  "PN0OMEV9M8VGFn1CtA/T3bcgZmMJmOo+RkBrLKIWYeQ=",
+	})
+	assert.Equal(t, len(doc.Packages[0].Checksums), 0)
+	assert.DeepEqual(t, doc.Relationships, []spdxRelationship{
+		{SpdxElementId: "SPDXRef-DOCUMENT", RelationshipType: "DESCRIBES", RelatedSpdxElement: "SPDXRef-Package-test-sbom-0.0.1"},
+		{SpdxElementId: "SPDXRef-Package-test-sbom-0.0.1", RelationshipType: "DEPENDS_ON", RelatedSpdxElement: "SPDXRef-Package-helloworld-0.1.2"},
+	})
+}
+
+func TestSbomCycloneDxJson(t *testing.T) {
+	out, err := testSbom().Format(SBOM_FORMAT_CYCLONEDX_JSON)
+	assert.NilError(t, err)
+
+	var doc cycloneDxDocument
+	assert.NilError(t, json.Unmarshal(out, &doc))
+	assert.Equal(t, doc.SpecVersion, "1.5")
+	assert.Equal(t, doc.SerialNumber, "urn:uuid:00000000-0000-0000-0000-000000000000")
+	assert.Equal(t, doc.Metadata.Component.BomRef, "test_sbom@0.0.1")
+	assert.Equal(t, len(doc.Components), 1)
+	assert.Equal(t, doc.Components[0].Hashes[0].Content, "9b8e2f7a0d3c4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7")
+	assert.DeepEqual(t, doc.Components[0].Properties, []cycloneDxProperty{
+		{Name: CYCLONEDX_PROPERTY_KPM_SUM, Value: "PN0OMEV9M8VGFn1CtA/T3bcgZmMJmOo+RkBrLKIWYeQ="},
+	})
+	assert.Equal(t, doc.Components[0].Licenses[0].Expression, "MIT")
+	assert.Equal(t, doc.Components[0].ExternalReferences[0].Type, "distribution")
+	assert.DeepEqual(t, doc.Dependencies, []cycloneDxDependency{
+		{Ref: "test_sbom@0.0.1", DependsOn: []string{"helloworld@0.1.2"}},
+		{Ref: "helloworld@0.1.2"},
+	})
+
+	_, err = testSbom().Format("invalid")
+	assert.ErrorContains(t, err, "invalid SBOM format 'invalid'")
+}
+
+func TestSbomDepLocationAndPurl(t *testing.T) {
+	ociDep := &pkg.Dependency{
+		Name:    "helloworld",
+		Version: "0.1.2",
+		Source: downloader.Source{
+			Oci: &downloader.Oci{
+				Reg:  "ghcr.io",
+				Repo: "kcl-lang/helloworld",
+				Tag:  "0.1.2",
+			},
+		},
+	}
+	assert.Equal(t, depLocation(ociDep), "oci://ghcr.io/kcl-lang/helloworld:0.1.2")
+	assert.Equal(t, depPurl(ociDep), "pkg:oci/helloworld?repository_url=ghcr.io%2Fkcl-lang%2Fhelloworld&tag=0.1.2")
+
+	ociDep.Source.Oci.Digest = "sha256:3cdd0e30457d33c546167d42b40fd3ddb72066630998ea3e46406b2ca21661e4"
+	assert.Equal(t, depLocation(ociDep), "oci://ghcr.io/kcl-lang/helloworld@sha256:3cdd0e30457d33c546167d42b40fd3ddb72066630998ea3e46406b2ca21661e4")
+	assert.Equal(t, depPurl(ociDep), "pkg:oci/helloworld@sha256:3cdd0e30457d33c546167d42b40fd3ddb72066630998ea3e46406b2ca21661e4?repository_url=ghcr.io%2Fkcl-lang%2Fhelloworld&tag=0.1.2")
+
+	gitDep := &pkg.Dependency{
+		Name:    "flask_manifests",
+		Version: "0.0.1",
+		Source: downloader.Source{
+			Git: &downloader.Git{
+				Url:    "https://github.com/kcl-lang/flask-demo-kcl-manifests.git",
+				Commit: "ade147b",
+			},
+		},
+	}
+	assert.Equal(t, depLocation(gitDep), "git+https://github.com/kcl-lang/flask-demo-kcl-manifests.git@ade147b")
+	assert.Equal(t, depPurl(gitDep), "pkg:generic/flask_manifests@0.0.1?vcs_url=git%2Bhttps%3A%2F%2Fgithub.com%2Fkcl-lang%2Fflask-demo-kcl-manifests.git%40ade147b")
+
+	assert.Equal(t, depLocation(&pkg.Dependency{Name: "local"}), "")
+	assert.Equal(t, depPurl(&pkg.Dependency{Name: "local"}), "")
+}
+
+func TestDigestInHex(t *testing.T) {
+	assert.Equal(t, digestInHex("sha256:9b8e2f7a0d3c4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7"), "9b8e2f7a0d3c4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7")
+	assert.Equal(t, digestInHex("sha512:9b8e2f7a0d3c4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7"), "")
+	assert.Equal(t, digestInHex("sha256:not hex"), "")
+	assert.Equal(t, digestInHex("h1:PN0OMEV9M8VGFn1CtA/T3bcgZmMJmOo+RkBrLKIWYeQ="), "")
+	assert.Equal(t, digestInHex(""), "")
+}
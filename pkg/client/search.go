@@ -0,0 +1,191 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"kcl-lang.io/kpm/pkg/constants"
+	"kcl-lang.io/kpm/pkg/downloader"
+	"kcl-lang.io/kpm/pkg/oci"
+	"kcl-lang.io/kpm/pkg/opt"
+	"kcl-lang.io/kpm/pkg/semver"
+)
+
+// SearchResult is a kcl package found in the oci registry.
+type SearchResult struct {
+	// Name is the name of the package.
+	Name string `json:"name"`
+	// Source is the oci url of the package, e.g. 'oci://ghcr.io/kcl-lang/k8s'.
+	Source string `json:"source"`
+	// Latest is the latest version of the package.
+	Latest string `json:"latest"`
+	// Description is the description of the package.
+	Description string `json:"description,omitempty"`
+	// Keywords are the keywords of the package.
+	Keywords []string `json:"keywords,omitempty"`
+}
+
+// SearchOptions is the option for searching the kcl packages in the oci registry.
+type SearchOptions struct {
+	// Registry is the oci registry to search, the default oci registry is used if it is empty.
+	Registry string
+	// Namespace only keeps the repos under it, e.g. 'kcl-lang'.
+	// The default oci repo is used if both the registry and the namespace are empty.
+	Namespace string
+	// Query only keeps the packages whose name or keywords contain it.
+	Query string
+}
+
+type SearchOption func(*SearchOptions) error
+
+// WithSearchRegistry sets the oci registry to search.
+func WithSearchRegistry(registry string) SearchOption {
+	return func(opts *SearchOptions) error {
+		opts.Registry = registry
+		return nil
+	}
+}
+
+// WithSearchNamespace sets the namespace of the repos to search.
+func WithSearchNamespace(namespace string) SearchOption {
+	return func(opts *SearchOptions) error {
+		opts.Namespace = strings.Trim(namespace, "/")
+		return nil
+	}
+}
+
+// WithSearchQuery sets the query of the name or the keywords of the packages.
+func WithSearchQuery(query string) SearchOption {
+	return func(opts *SearchOptions) error {
+		opts.Query = query
+		return nil
+	}
+}
+
+// Search will return the kcl packages in the oci registry.
+// The repos are listed by the '_catalog' api of the registry and the versions by the tag list api,
+// the name, the description and the keywords are read from the annotations of the manifest of the latest version.
+// The repos without the kcl annotations are not kcl packages and they are skipped.
+func (c *KpmClient) Search(options ...SearchOption) ([]SearchResult, error) {
+	opts := &SearchOptions{}
+	for _, option := range options {
+		if err := option(opts); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(opts.Registry) == 0 {
+		opts.Registry = c.GetSettings().DefaultOciRegistry()
+		if len(opts.Namespace) == 0 {
+			opts.Namespace = c.GetSettings().DefaultOciRepo()
+		}
+	}
+
+	cred, err := c.GetCredentials(opts.Registry)
+	if err != nil {
+		return nil, err
+	}
+
+	repos, err := oci.Repositories(opts.Registry, cred, c.GetSettings(), c.insecureSkipTLSverify)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(repos)
+
+	var results []SearchResult
+	for _, repo := range repos {
+		if len(opts.Namespace) != 0 && !strings.HasPrefix(repo, opts.Namespace+"/") {
+			continue
+		}
+
+		ociSource := &downloader.Oci{
+			Reg:  opts.Registry,
+			Repo: repo,
+		}
+		tags, err := c.getOciTags(ociSource)
+		if err != nil {
+			return nil, err
+		}
+		tags = semver.FilterValidVersions(tags)
+		if len(tags) == 0 {
+			continue
+		}
+		ociSource.Tag, err = semver.LatestVersion(tags)
+		if err != nil {
+			return nil, err
+		}
+
+		manifestJson, err := c.FetchOciManifestIntoJsonStr(opt.OciFetchOptions{
+			OciOptions: opt.OciOptions{
+				Reg:  ociSource.Reg,
+				Repo: ociSource.Repo,
+				Tag:  ociSource.Tag,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		result, ok, err := searchResultFromManifest(manifestJson, ociSource)
+		if err != nil {
+			return nil, err
+		}
+		if ok && result.Matches(opts.Query) {
+			results = append(results, *result)
+		}
+	}
+
+	return results, nil
+}
+
+// Matches returns true if the name or one of the keywords of the package contains the query.
+// The query is case-insensitive, and the empty query matches all the packages.
+func (r *SearchResult) Matches(query string) bool {
+	query = strings.ToLower(query)
+	if strings.Contains(strings.ToLower(r.Name), query) {
+		return true
+	}
+	for _, keyword := range r.Keywords {
+		if strings.Contains(strings.ToLower(keyword), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// searchResultFromManifest returns the package from the annotations of the manifest of the oci source.
+// It returns false if the manifest is not the manifest of a kcl package.
+func searchResultFromManifest(manifestJson string, ociSource *downloader.Oci) (*SearchResult, bool, error) {
+	var manifest v1.Manifest
+	if err := json.Unmarshal([]byte(manifestJson), &manifest); err != nil {
+		return nil, false, fmt.Errorf("failed to parse the manifest of '%s:%s': %w", ociSource.Repo, ociSource.Tag, err)
+	}
+
+	name, ok := manifest.Annotations[constants.DEFAULT_KCL_OCI_MANIFEST_NAME]
+	if !ok {
+		return nil, false, nil
+	}
+
+	source, err := (&downloader.Oci{Reg: ociSource.Reg, Repo: ociSource.Repo}).ToString()
+	if err != nil {
+		return nil, false, err
+	}
+
+	result := &SearchResult{
+		Name:        name,
+		Source:      source,
+		Latest:      ociSource.Tag,
+		Description: manifest.Annotations[constants.DEFAULT_KCL_OCI_MANIFEST_DESCRIPTION],
+	}
+	if keywords := manifest.Annotations[constants.DEFAULT_KCL_OCI_MANIFEST_KEYWORDS]; len(keywords) != 0 {
+		for _, keyword := range strings.Split(keywords, ",") {
+			if keyword = strings.TrimSpace(keyword); len(keyword) != 0 {
+				result.Keywords = append(result.Keywords, keyword)
+			}
+		}
+	}
+	return result, true, nil
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"kcl-lang.io/kpm/pkg/downloader"
+)
+
+func TestSearchResultFromManifest(t *testing.T) {
+	ociSource := &downloader.Oci{
+		Reg:  "localhost:5001",
+		Repo: "test/k8s",
+		Tag:  "1.28.1",
+	}
+
+	result, ok, err := searchResultFromManifest(`{
+  "schemaVersion": 2,
+  "annotations": {
+    "org.kcllang.package.name": "k8s",
+    "org.kcllang.package.version": "1.28.1",
+    "org.kcllang.package.description": "Kubernetes schemas",
+    "org.kcllang.package.keywords": "kubernetes, k8s,,schemas"
+  }
+}`, ociSource)
+	assert.NilError(t, err)
+	assert.Equal(t, ok, true)
+	assert.DeepEqual(t, *result, SearchResult{
+		Name:        "k8s",
+		Source:      "oci://localhost:5001/test/k8s",
+		Latest:      "1.28.1",
+		Description: "Kubernetes schemas",
+		Keywords:    []string{"kubernetes", "k8s", "schemas"},
+	})
+
+	assert.Equal(t, result.Matches(""), true)
+	assert.Equal(t, result.Matches("K8S"), true)
+	assert.Equal(t, result.Matches("kube"), true)
+	assert.Equal(t, result.Matches("helm"), false)
+
+	_, ok, err = searchResultFromManifest(`{"schemaVersion": 2}`, ociSource)
+	assert.NilError(t, err)
+	assert.Equal(t, ok, false)
+
+	_, _, err = searchResultFromManifest(`{`, ociSource)
+	assert.ErrorContains(t, err, "failed to parse the manifest of 'test/k8s:1.28.1'")
+}
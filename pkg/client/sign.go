@@ -0,0 +1,29 @@
+package client
+
+import (
+	"fmt"
+
+	"kcl-lang.io/kpm/pkg/downloader"
+	"kcl-lang.io/kpm/pkg/reporter"
+	"kcl-lang.io/kpm/pkg/signature"
+)
+
+// Sign will sign the version of the package published in the oci registry by the private key in 'keyPath',
+// and attach the signature to the package as an oci referrer.
+// The package pulled from the registries or namespaces in the signature policies of 'kpm.json' is verified by the signature.
+func (c *KpmClient) Sign(ociSource *downloader.Oci, keyPath string) error {
+	if ociSource == nil || len(ociSource.Tag) == 0 {
+		return reporter.NewErrorEvent(reporter.InvalidPkgRef, fmt.Errorf("the version of the package to sign is not specified"))
+	}
+
+	signer, err := signature.LoadPrivateKey(keyPath)
+	if err != nil {
+		return reporter.NewErrorEvent(reporter.FailedSign, err)
+	}
+
+	ociCli, err := c.newOciClient(ociSource)
+	if err != nil {
+		return err
+	}
+	return ociCli.PushSignature(ociSource.Tag, signer)
+}
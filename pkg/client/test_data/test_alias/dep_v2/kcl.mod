@@ -0,0 +1,4 @@
+[package]
+name = "dep"
+edition = "v0.10.0"
+version = "2.0.0"
@@ -0,0 +1,8 @@
+[package]
+name = "pkg"
+edition = "v0.10.0"
+version = "0.0.1"
+
+[dependencies]
+dep = { path = "../dep_v2" }
+dep_old = { path = "../dep_v1" }
@@ -0,0 +1,7 @@
+[package]
+name = "consumer"
+edition = "v0.10.0"
+version = "0.0.1"
+
+[dependencies]
+pkg = { path = "../pkg" }
@@ -0,0 +1,4 @@
+[package]
+name = "dev_0"
+edition = "v0.10.0"
+version = "0.0.1"
@@ -0,0 +1,10 @@
+[package]
+name = "pkg"
+edition = "v0.10.0"
+version = "0.0.1"
+
+[dependencies]
+dep_0 = { path = "../dep_0" }
+
+[dev-dependencies]
+dev_0 = { path = "../dev_0" }
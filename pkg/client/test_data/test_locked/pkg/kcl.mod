@@ -0,0 +1,8 @@
+[package]
+name = "pkg"
+edition = "v0.10.0"
+version = "0.0.1"
+
+[dependencies]
+dep_0 = { path = "../dep_0" }
+dep_1 = { path = "../dep_1" }
@@ -0,0 +1,7 @@
+[package]
+name = "pkg_git_branch"
+edition = "v0.10.0"
+version = "0.0.1"
+
+[dependencies]
+flask_manifests = { git = "https://github.com/kcl-lang/flask-demo-kcl-manifests.git", branch = "main" }
@@ -0,0 +1,10 @@
+[package]
+name = "pkg"
+edition = "v0.10.0"
+version = "0.0.1"
+
+[dependencies]
+helloworld = "0.1.2"
+
+[replace]
+helloworld = { path = "../helloworld_fork" }
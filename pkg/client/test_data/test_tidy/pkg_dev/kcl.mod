@@ -0,0 +1,11 @@
+[package]
+name = "pkg_dev"
+edition = "v0.10.0"
+version = "0.0.1"
+
+[dependencies]
+dep_1 = { path = "../dep_1" }
+
+[dev-dependencies]
+dep_0 = { path = "../dep_0" }
+dep_2 = { path = "../dep_2" }
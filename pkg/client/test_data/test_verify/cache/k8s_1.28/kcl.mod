@@ -0,0 +1,4 @@
+[package]
+name = "k8s"
+edition = "v0.10.0"
+version = "1.28"
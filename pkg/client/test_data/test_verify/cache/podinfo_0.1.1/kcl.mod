@@ -0,0 +1,4 @@
+[package]
+name = "podinfo"
+edition = "v0.10.0"
+version = "0.1.0"
@@ -0,0 +1,11 @@
+[package]
+name = "test_verify"
+edition = "v0.10.0"
+version = "0.0.1"
+
+[dependencies]
+helloworld = "0.1.0"
+json_merge_patch = "0.1.0"
+k8s = "1.28"
+nginx = "0.1.0"
+podinfo = "0.1.1"
@@ -0,0 +1,7 @@
+[package]
+name = "dep_2"
+edition = "v0.10.0"
+version = "0.0.1"
+
+[dependencies]
+dep_1 = { path = "../dep_1" }
@@ -0,0 +1,7 @@
+[package]
+name = "a"
+edition = "v0.10.0"
+version = "0.0.1"
+
+[dependencies]
+b = "0.0.1"
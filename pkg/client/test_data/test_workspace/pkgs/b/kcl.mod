@@ -0,0 +1,4 @@
+[package]
+name = "b"
+edition = "v0.10.0"
+version = "0.0.1"
@@ -0,0 +1,231 @@
+package client
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"kcl-lang.io/kcl-go/pkg/ast"
+	"kcl-lang.io/kcl-go/pkg/parser"
+	"kcl-lang.io/kpm/pkg/constants"
+	"kcl-lang.io/kpm/pkg/downloader"
+	pkg "kcl-lang.io/kpm/pkg/package"
+	"kcl-lang.io/kpm/pkg/reporter"
+	"kcl-lang.io/kpm/pkg/utils"
+)
+
+// kclSystemModules is the set of the system modules of KCL,
+// importing them does not require any dependency.
+var kclSystemModules = map[string]struct{}{
+	"base64":     {},
+	"collection": {},
+	"crypto":     {},
+	"datetime":   {},
+	"file":       {},
+	"json":       {},
+	"manifests":  {},
+	"math":       {},
+	"net":        {},
+	"regex":      {},
+	"runtime":    {},
+	"template":   {},
+	"units":      {},
+	"yaml":       {},
+}
+
+// kclPluginModulePrefix is the prefix of the KCL plugin modules.
+const kclPluginModulePrefix = "kcl_plugin"
+
+// TidyOptions is the option for tidying the dependencies of a package.
+type TidyOptions struct {
+	// KclPkg is the package to be tidied.
+	KclPkg *pkg.KclPkg
+}
+
+type TidyOption func(*TidyOptions) error
+
+// WithTidyKclPkg sets the kcl package to be tidied.
+func WithTidyKclPkg(kpkg *pkg.KclPkg) TidyOption {
+	return func(opts *TidyOptions) error {
+		opts.KclPkg = kpkg
+		return nil
+	}
+}
+
+// Tidy will make kcl.mod and kcl.mod.lock match the imports in the KCL files of the package.
+// The dependencies and the dev-dependencies that are no longer imported will be removed,
+// and the external packages imported but not declared in either of them will be added from the default OCI registry.
+func (c *KpmClient) Tidy(options ...TidyOption) error {
+	opts := &TidyOptions{}
+	for _, option := range options {
+		if err := option(opts); err != nil {
+			return err
+		}
+	}
+
+	kpkg := opts.KclPkg
+	if kpkg == nil {
+		return fmt.Errorf("kcl package is nil")
+	}
+
+	importedRoots, err := c.parseImportedPkgRoots(kpkg)
+	if err != nil {
+		return err
+	}
+
+	// Find the dependencies and the dev-dependencies in kcl.mod that are not imported by any KCL file.
+	// The unused ones are removed from the section they are declared in by 'Remove'.
+	declaredRoots := make(map[string]struct{})
+	var unusedDeps []string
+	allModDeps := kpkg.ModFile.DepsWithDevDeps().Deps
+	for _, depName := range allModDeps.Keys() {
+		dep, ok := allModDeps.Get(depName)
+		if !ok {
+			return fmt.Errorf("failed to get dependency %s", depName)
+		}
+		declaredRoots[dep.GetAliasName()] = struct{}{}
+		if _, ok := importedRoots[dep.GetAliasName()]; !ok {
+			unusedDeps = append(unusedDeps, depName)
+		}
+	}
+
+	// Find the external packages imported but not declared in kcl.mod.
+	var missingDeps []string
+	for root := range importedRoots {
+		if _, ok := declaredRoots[root]; !ok {
+			missingDeps = append(missingDeps, root)
+		}
+	}
+	sort.Strings(missingDeps)
+
+	if len(unusedDeps) != 0 {
+		err = c.Remove(
+			WithRemoveKclPkg(kpkg),
+			WithRemoveDepNames(unusedDeps),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(missingDeps) != 0 {
+		var sources []*downloader.Source
+		for _, depName := range missingDeps {
+			ociSource := downloader.Oci{
+				Reg:  c.GetSettings().Conf.DefaultOciRegistry,
+				Repo: utils.JoinPath(c.GetSettings().Conf.DefaultOciRepo, depName),
+			}
+			latestTag, err := c.AcquireTheLatestOciVersion(ociSource)
+			if err != nil {
+				return reporter.NewErrorEvent(
+					reporter.DependencyNotFound,
+					err,
+					fmt.Sprintf("failed to find the package '%s' imported in the KCL files from '%s'", depName, ociSource.Reg),
+				)
+			}
+			reporter.ReportEventTo(
+				reporter.NewEvent(reporter.AddDep, fmt.Sprintf("adding dependency '%s:%s'", depName, latestTag)),
+				c.logWriter,
+			)
+			sources = append(sources, &downloader.Source{
+				ModSpec: &downloader.ModSpec{
+					Name:    depName,
+					Version: latestTag,
+				},
+			})
+		}
+
+		err = c.Add(
+			WithAddKclPkg(kpkg),
+			WithAddSources(sources),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(unusedDeps) == 0 && len(missingDeps) == 0 {
+		// Nothing changed in kcl.mod, only make sure kcl.mod.lock is consistent with kcl.mod.
+		err = kpkg.UpdateModAndLockFile()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseImportedPkgRoots will parse all the KCL files in the package,
+// and return the root names of the external packages imported by them.
+// The relative imports, the system modules, the plugin modules and
+// the modules inside the package itself are not external packages.
+func (c *KpmClient) parseImportedPkgRoots(kpkg *pkg.KclPkg) (map[string]struct{}, error) {
+	importedRoots := make(map[string]struct{})
+
+	err := filepath.WalkDir(kpkg.HomePath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if path == kpkg.HomePath {
+				return nil
+			}
+			// Skip the hidden directories, the vendor directory and the sub packages with their own kcl.mod.
+			if strings.HasPrefix(d.Name(), ".") ||
+				path == kpkg.LocalVendorPath() ||
+				utils.DirExists(filepath.Join(path, constants.KCL_MOD)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !strings.HasSuffix(path, constants.KFilePathSuffix) {
+			return nil
+		}
+
+		module, err := parser.ParseFile(path, nil)
+		if err != nil {
+			return reporter.NewErrorEvent(reporter.FailedParseKclFile, err, fmt.Sprintf("failed to parse '%s'", path))
+		}
+
+		for _, stmt := range module.Body {
+			importStmt, ok := stmt.Node.(*ast.ImportStmt)
+			if !ok {
+				continue
+			}
+
+			importPath := importStmt.Rawpath
+			if len(importPath) == 0 && importStmt.Path != nil {
+				importPath = importStmt.Path.Node
+			}
+			// Skip the relative imports.
+			if len(importPath) == 0 || strings.HasPrefix(importPath, ".") {
+				continue
+			}
+
+			root := strings.Split(importPath, ".")[0]
+			if _, ok := kclSystemModules[root]; ok || root == kclPluginModulePrefix {
+				continue
+			}
+
+			// Skip the modules inside the package itself.
+			if utils.DirExists(filepath.Join(kpkg.HomePath, root)) ||
+				utils.DirExists(filepath.Join(kpkg.HomePath, root+constants.KFilePathSuffix)) {
+				continue
+			}
+
+			importedRoots[root] = struct{}{}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return importedRoots, nil
+}
@@ -0,0 +1,111 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/otiai10/copy"
+	"gotest.tools/v3/assert"
+	"kcl-lang.io/kpm/pkg/utils"
+)
+
+func TestTidy(t *testing.T) {
+	pkgPath := filepath.Join(getTestDir("test_tidy"), "pkg")
+	modPath := filepath.Join(pkgPath, "kcl.mod")
+	lockPath := filepath.Join(pkgPath, "kcl.mod.lock")
+
+	if err := copy.Copy(modPath+".bk", modPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := copy.Copy(lockPath+".bk", lockPath); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = copy.Copy(modPath+".bk", modPath)
+		_ = copy.Copy(lockPath+".bk", lockPath)
+	}()
+
+	kpmcli, err := NewKpmClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kpkg, err := kpmcli.LoadPkgFromPath(pkgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	importedRoots, err := kpmcli.parseImportedPkgRoots(kpkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, len(importedRoots), 1)
+	_, ok := importedRoots["dep_1"]
+	assert.Equal(t, ok, true)
+
+	err = kpmcli.Tidy(WithTidyKclPkg(kpkg))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedMod, err := os.ReadFile(modPath + ".expect")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedModLock, err := os.ReadFile(lockPath + ".expect")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotMod, err := os.ReadFile(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotModLock, err := os.ReadFile(lockPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, utils.RmNewline(string(expectedMod)), utils.RmNewline(string(gotMod)))
+	assert.Equal(t, utils.RmNewline(string(expectedModLock)), utils.RmNewline(string(gotModLock)))
+}
+
+func TestTidyWithDevDeps(t *testing.T) {
+	testDir := filepath.Join(t.TempDir(), "test_tidy")
+	if err := copy.Copy(getTestDir("test_tidy"), testDir); err != nil {
+		t.Fatal(err)
+	}
+	pkgPath := filepath.Join(testDir, "pkg_dev")
+
+	kpmcli, err := NewKpmClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kpkg, err := kpmcli.LoadPkgFromPath(pkgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = kpmcli.Tidy(WithTidyKclPkg(kpkg))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kpkg, err = kpmcli.LoadPkgFromPath(pkgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The imported dev-dependency is kept in [dev-dependencies] and not added to [dependencies].
+	assert.DeepEqual(t, kpkg.ModFile.Dependencies.Deps.Keys(), []string{"dep_1"})
+	assert.DeepEqual(t, kpkg.ModFile.DevDependencies.Deps.Keys(), []string{"dep_0"})
+
+	// The unused dev-dependency is removed from kcl.mod.lock.
+	_, ok := kpkg.Dependencies.Deps.Get("dep_2")
+	assert.Equal(t, ok, false)
+	_, ok = kpkg.Dependencies.Deps.Get("dep_0")
+	assert.Equal(t, ok, true)
+	_, ok = kpkg.Dependencies.Deps.Get("dep_1")
+	assert.Equal(t, ok, true)
+}
@@ -2,11 +2,14 @@ package client
 
 import (
 	"fmt"
-	"path/filepath"
 
-	"kcl-lang.io/kpm/pkg/downloader"
+	"github.com/dominikbraun/graph"
+	"golang.org/x/mod/module"
 	pkg "kcl-lang.io/kpm/pkg/package"
+	"kcl-lang.io/kpm/pkg/reporter"
 	"kcl-lang.io/kpm/pkg/resolver"
+	"kcl-lang.io/kpm/pkg/semver"
+	"kcl-lang.io/kpm/pkg/utils"
 )
 
 // UpdateOptions is the option for updating a package.
@@ -55,6 +58,8 @@ func (c *KpmClient) Update(options ...UpdateOption) (*pkg.KclPkg, error) {
 		Downloader:            c.DepDownloader,
 		Settings:              &c.settings,
 		LogWriter:             c.logWriter,
+		FetchModFunc:          c.fetchDepModFile,
+		Replaces:              kpkg.ModFile.Replaces,
 	}
 	// ResolveFunc is the function for resolving each dependency when traversing the dependency graph.
 	resolverFunc := func(dep *pkg.Dependency, parentPkg *pkg.KclPkg) error {
@@ -75,6 +80,10 @@ func (c *KpmClient) Update(options ...UpdateOption) (*pkg.KclPkg, error) {
 			// check the version and select the greater one.
 			if less, err := existDep.VersionLessThan(dep); less && err == nil {
 				kpkg.Dependencies.Deps.Set(dep.Name, *dep)
+			} else if !lockedVersionInRange(kpkg, &existDep) {
+				// If the locked version is out of the version range in kcl.mod,
+				// the locked version is replaced by the version selected in the range.
+				kpkg.Dependencies.Deps.Set(dep.Name, *dep)
 			}
 		} else {
 			// if the dependency does not exist in the lock file,
@@ -82,38 +91,50 @@ func (c *KpmClient) Update(options ...UpdateOption) (*pkg.KclPkg, error) {
 			kpkg.Dependencies.Deps.Set(dep.Name, *dep)
 		}
 
+		// Record the package requiring the dependency in the lock file.
+		if lockedDep, exist := lockDeps.Get(dep.Name); exist && parentPkg != nil {
+			lockedDep.AddDependents(parentPkg.GetPkgName())
+			lockDeps.Set(dep.Name, lockedDep)
+		}
+
 		return nil
 	}
 	depResolver.ResolveFuncs = append(depResolver.ResolveFuncs, resolverFunc)
+	// The versions not locked yet are checked whether they are deprecated or yanked by the publisher.
+	depResolver.CheckFuncs = append(depResolver.CheckFuncs, func(dep *pkg.Dependency) error {
+		return c.checkOciVersionStatus(dep, &kpkg.Dependencies)
+	})
 
-	// Iterate all the dependencies of the package in kcl.mod and resolve each dependency.
-	for _, depName := range modDeps.Keys() {
-		dep, ok := modDeps.Get(depName)
+	// Iterate all the dependencies and dev-dependencies of the package in kcl.mod and resolve each dependency.
+	allModDeps := kpkg.ModFile.DepsWithDevDeps().Deps
+	for _, depName := range allModDeps.Keys() {
+		modDep, ok := allModDeps.Get(depName)
 		if !ok {
 			return nil, fmt.Errorf("failed to get dependency %s", depName)
 		}
 
-		// Check if the dependency is a local path and it is not an absolute path.
-		// If it is not an absolute path, transform the path to an absolute path.
-		var depSource *downloader.Source
-		if dep.Source.IsLocalPath() && !filepath.IsAbs(dep.Source.Local.Path) {
-			depSource = &downloader.Source{
-				Local: &downloader.Local{
-					Path: filepath.Join(kpkg.HomePath, dep.Source.Local.Path),
-				},
-			}
-		} else {
-			depSource = &dep.Source
+		// If the dependency is required by a version range, select the highest version in the range.
+		exactDep, err := depResolver.ResolveVersionRange(&modDep)
+		if err != nil {
+			return nil, err
+		}
+		// Get the dependency source, the local path is transformed to an absolute path
+		// and the dependency redirected by the [replace] section is resolved from the replacement.
+		dep, depSource := depResolver.ResolveSource(exactDep, kpkg.HomePath)
+
+		err = depResolver.Check(dep, depSource)
+		if err != nil {
+			return nil, err
 		}
 
-		err := resolverFunc(&dep, kpkg)
+		err = resolverFunc(dep, kpkg)
 		if err != nil {
 			return nil, err
 		}
 
 		err = depResolver.Resolve(
 			resolver.WithEnableCache(true),
-			resolver.WithSource(depSource),
+			resolver.WithSource(&depSource),
 		)
 		if err != nil {
 			return nil, err
@@ -127,3 +148,195 @@ func (c *KpmClient) Update(options ...UpdateOption) (*pkg.KclPkg, error) {
 
 	return kpkg, nil
 }
+
+// lockedVersionInRange returns false if the dependency is required by a version range in kcl.mod
+// and the locked version is out of the range.
+func lockedVersionInRange(kpkg *pkg.KclPkg, lockedDep *pkg.Dependency) bool {
+	modDep, ok := kpkg.ModFile.DepsWithDevDeps().Deps.Get(lockedDep.Name)
+	if !ok || !modDep.Source.IsVersionRange() {
+		return true
+	}
+	inRange, err := semver.VersionInRange(lockedDep.Version, modDep.Source.VersionRange())
+	return err == nil && inRange
+}
+
+// ApplyBuildList will update kcl.mod and kcl.mod.lock of the package to the versions selected in the build list.
+// The build list is calculated by MVS on the dependency graph of the package,
+// and the vertices of the graph provide the sources of the dependencies not locked yet.
+// Only the dependencies whose versions changed are updated,
+// and the dependencies in kcl.mod.lock which are not in the build list are removed.
+func (c *KpmClient) ApplyBuildList(kpkg *pkg.KclPkg, depGraph graph.Graph[module.Version, module.Version], buildList []module.Version) error {
+	if kpkg == nil {
+		return fmt.Errorf("kcl package is nil")
+	}
+
+	modDeps := kpkg.ModFile.Dependencies.Deps
+	if modDeps == nil {
+		return fmt.Errorf("kcl.mod dependencies is nil")
+	}
+	lockDeps := kpkg.Dependencies.Deps
+	if lockDeps == nil {
+		return fmt.Errorf("kcl.mod.lock dependencies is nil")
+	}
+
+	selected := make(map[string]module.Version, len(buildList))
+	for _, m := range buildList {
+		if m.Path == kpkg.GetPkgName() {
+			continue
+		}
+		selected[m.Path] = m
+	}
+
+	// 1. Update the direct dependencies in kcl.mod.
+	for _, name := range modDeps.Keys() {
+		modDep, ok := modDeps.Get(name)
+		if !ok {
+			return fmt.Errorf("failed to get dependency %s", name)
+		}
+		m, ok := selected[name]
+		if !ok || modDep.IsFromLocal() || modDep.Version == m.Version {
+			continue
+		}
+		// The version range in kcl.mod is kept if the selected version is still in the range.
+		if modDep.Source.IsVersionRange() {
+			if inRange, err := semver.VersionInRange(m.Version, modDep.Source.VersionRange()); err == nil && inRange {
+				continue
+			}
+		}
+		modDeps.Set(name, modDep.WithVersion(m.Version))
+	}
+
+	// 2. Remove the dependencies in kcl.mod.lock which are no longer required.
+	for _, name := range lockDeps.Keys() {
+		lockDep, ok := lockDeps.Get(name)
+		if !ok {
+			return fmt.Errorf("failed to get dependency %s", name)
+		}
+		if _, ok := selected[name]; ok || lockDep.IsFromLocal() {
+			continue
+		}
+		reporter.ReportEventTo(
+			reporter.NewEvent(reporter.RemoveDep, fmt.Sprintf("removing dependency '%s'", name)),
+			c.logWriter,
+		)
+		lockDeps.Delete(name)
+	}
+
+	// 3. Lock the selected versions of the dependencies.
+	for _, m := range buildList {
+		if m.Path == kpkg.GetPkgName() {
+			continue
+		}
+		lockDep, locked := lockDeps.Get(m.Path)
+		if locked && (lockDep.Version == m.Version || lockDep.IsFromLocal()) {
+			continue
+		}
+
+		var dep pkg.Dependency
+		if locked {
+			dep = lockDep.WithVersion(m.Version)
+		} else {
+			_, properties, err := depGraph.VertexWithProperties(m)
+			if err != nil {
+				return reporter.NewErrorEvent(reporter.FailedGetVertexProperties, err, "failed to get vertex with properties")
+			}
+			dep = pkg.Dependency{
+				Name:    m.Path,
+				Version: m.Version,
+			}
+			for sourceType, uri := range properties.Attributes {
+				dep.Source, err = pkg.GenSource(sourceType, uri, m.Version)
+				if err != nil {
+					return reporter.NewErrorEvent(reporter.FailedGenerateSource, err, "failed to generate source")
+				}
+			}
+			dep.FullName = dep.GenDepFullName()
+		}
+
+		newDep, err := c.lockDependency(kpkg, &dep)
+		if err != nil {
+			return err
+		}
+		lockDeps.Set(m.Path, *newDep)
+
+		if !locked {
+			reporter.ReportEventTo(
+				reporter.NewEvent(reporter.AddDep, fmt.Sprintf("adding dependency '%s:%s'", m.Path, m.Version)),
+				c.logWriter,
+			)
+			continue
+		}
+		action := "upgrading"
+		if less, err := newDep.VersionLessThan(&lockDep); err == nil && less {
+			action = "downgrading"
+		}
+		reporter.ReportEventTo(
+			reporter.NewEvent(reporter.UpdateDep, fmt.Sprintf("%s '%s' from %s to %s", action, m.Path, lockDep.Version, m.Version)),
+			c.logWriter,
+		)
+	}
+
+	// 4. Record the dependents of the dependencies locked.
+	root := module.Version{Path: kpkg.GetPkgName(), Version: kpkg.GetPkgVersion()}
+	err := fillLockDependents(&kpkg.Dependencies, depGraph, root)
+	if err != nil {
+		return err
+	}
+
+	if kpkg.IsVendorMode() {
+		err := c.vendorDeps(kpkg, kpkg.LocalVendorPath())
+		if err != nil {
+			return err
+		}
+	}
+
+	return kpkg.UpdateModAndLockFile()
+}
+
+// lockDependency will download the dependency into the package cache if it does not exist,
+// and return the dependency with the checksum to be locked in kcl.mod.lock.
+// The dependency redirected by the [replace] section in kcl.mod is downloaded from the replacement.
+func (c *KpmClient) lockDependency(kpkg *pkg.KclPkg, dep *pkg.Dependency) (*pkg.Dependency, error) {
+	replacedDep, isReplaced := kpkg.ModFile.Replaces.ReplaceDep(dep)
+	if !isReplaced {
+		// The version not locked yet is checked whether it is deprecated or yanked by the publisher.
+		if err := c.checkOciVersionStatus(dep, &kpkg.Dependencies); err != nil {
+			return nil, err
+		}
+		return c.lockDependencyFromSource(dep, kpkg.HomePath)
+	}
+
+	if err := c.checkOciVersionStatus(replacedDep, &kpkg.Dependencies); err != nil {
+		return nil, err
+	}
+	lockedDep, err := c.lockDependencyFromSource(replacedDep, kpkg.HomePath)
+	if err != nil {
+		return nil, err
+	}
+	recordedDep := dep.RecordReplace(lockedDep)
+	return &recordedDep, nil
+}
+
+// lockDependencyFromSource will download the dependency from its source if it does not exist,
+// and return the dependency with the checksum.
+func (c *KpmClient) lockDependencyFromSource(dep *pkg.Dependency, pkghome string) (*pkg.Dependency, error) {
+	existDep, err := c.dependencyExistsLocal(c.homePath, dep, false)
+	if err != nil {
+		return nil, err
+	}
+	if existDep == nil {
+		return c.Download(dep, pkghome, c.getDepStorePath(c.homePath, dep, false))
+	}
+
+	existDep.Sum, err = c.AcquireDepSum(*existDep)
+	if err != nil {
+		return nil, err
+	}
+	if existDep.Sum == "" {
+		existDep.Sum, err = utils.HashDirH1(existDep.LocalFullPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return existDep, nil
+}
@@ -33,7 +33,7 @@ func (c *KpmClient) vendorDeps(kclPkg *pkg.KclPkg, vendorPath string) error {
 	if ok, err := features.Enabled(features.SupportMVS); err == nil && ok {
 		// Select all the vendored dependencies
 		// and fill the vendored dependencies into kclPkg.Dependencies.Deps
-		err := c.selectVendoredDeps(kclPkg, vendorPath, kclPkg.Dependencies.Deps)
+		err := c.selectVendoredDeps(kclPkg, kclPkg.ModFile.DepsWithDevDeps(), vendorPath, kclPkg.Dependencies.Deps)
 		if err != nil {
 			return err
 		}
@@ -161,7 +161,8 @@ func (c *KpmClient) vendorDeps(kclPkg *pkg.KclPkg, vendorPath string) error {
 	return nil
 }
 
-func (c *KpmClient) selectVendoredDeps(kpkg *pkg.KclPkg, vendorPath string, vendoredDeps *orderedmap.OrderedMap[string, pkg.Dependency]) error {
+// selectVendoredDeps selects the dependencies 'modDeps' required by 'kpkg' and their indirect dependencies to be vendored.
+func (c *KpmClient) selectVendoredDeps(kpkg *pkg.KclPkg, modDeps *pkg.Dependencies, vendorPath string, vendoredDeps *orderedmap.OrderedMap[string, pkg.Dependency]) error {
 	// visitorSelectorFunc selects the visitor for the source.
 	// For remote source, it will use the RemoteVisitor and enable the cache.
 	// For local source, it will use the PkgVisitor.
@@ -198,8 +199,8 @@ func (c *KpmClient) selectVendoredDeps(kpkg *pkg.KclPkg, vendorPath string, vend
 	}
 
 	// Iterate all the dependencies of the package in kcl.mod.
-	for _, depName := range kpkg.ModFile.Dependencies.Deps.Keys() {
-		dep, ok := kpkg.ModFile.Dependencies.Deps.Get(depName)
+	for _, depName := range modDeps.Deps.Keys() {
+		dep, ok := modDeps.Deps.Get(depName)
 		if !ok {
 			return fmt.Errorf("failed to get dependency %s", depName)
 		}
@@ -246,7 +247,7 @@ func (c *KpmClient) selectVendoredDeps(kpkg *pkg.KclPkg, vendorPath string, vend
 				return err
 			}
 			// Vendor the indirected dependencies of the vendored dependency
-			err = c.selectVendoredDeps(dpkg, vendorPath, vendoredDeps)
+			err = c.selectVendoredDeps(dpkg, &dpkg.ModFile.Dependencies, vendorPath, vendoredDeps)
 			if err != nil {
 				return err
 			}
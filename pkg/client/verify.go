@@ -0,0 +1,184 @@
+package client
+
+import (
+	"fmt"
+
+	pkg "kcl-lang.io/kpm/pkg/package"
+	"kcl-lang.io/kpm/pkg/reporter"
+	"kcl-lang.io/kpm/pkg/utils"
+)
+
+// VerifyStatus is the result of verifying a dependency locked in kcl.mod.lock.
+type VerifyStatus string
+
+const (
+	// VerifyOk means the content of the dependency matches the sum in kcl.mod.lock.
+	VerifyOk VerifyStatus = "ok"
+	// VerifyUnchecked means there is no sum recorded in kcl.mod.lock for the dependency,
+	// so the content of the dependency can not be verified.
+	VerifyUnchecked VerifyStatus = "unchecked"
+	// VerifyMissing means the dependency can not be found in the package cache or the vendor directory.
+	VerifyMissing VerifyStatus = "missing"
+	// VerifyMismatched means the package found is not the name or the version locked in kcl.mod.lock.
+	VerifyMismatched VerifyStatus = "mismatched"
+	// VerifyModified means the content of the dependency is different from the sum in kcl.mod.lock.
+	VerifyModified VerifyStatus = "modified"
+)
+
+// VerifiedDep is the result of verifying a dependency locked in kcl.mod.lock.
+type VerifiedDep struct {
+	Name        string       `json:"name"`
+	Version     string       `json:"version"`
+	Path        string       `json:"path"`
+	ExpectedSum string       `json:"expected_sum,omitempty"`
+	ActualSum   string       `json:"actual_sum,omitempty"`
+	Status      VerifyStatus `json:"status"`
+	Reason      string       `json:"reason,omitempty"`
+}
+
+// Failed returns true if the dependency is not verified,
+// which means it is missing, mismatched, modified or has no sum in kcl.mod.lock.
+func (d *VerifiedDep) Failed() bool {
+	return d.Status != VerifyOk
+}
+
+// VerifyOptions is the option for verifying the dependencies of a package.
+type VerifyOptions struct {
+	// KclPkg is the package whose dependencies in kcl.mod.lock will be verified.
+	KclPkg *pkg.KclPkg
+}
+
+type VerifyOption func(*VerifyOptions) error
+
+// WithVerifyKclPkg sets the kcl package whose dependencies will be verified.
+func WithVerifyKclPkg(kpkg *pkg.KclPkg) VerifyOption {
+	return func(opts *VerifyOptions) error {
+		opts.KclPkg = kpkg
+		return nil
+	}
+}
+
+// Verify will find each dependency locked in kcl.mod.lock in the package cache or the vendor directory,
+// recompute the sum of its content and compare it with the sum in kcl.mod.lock.
+// The dependencies from the local path are skipped, because their sums are not locked.
+// The results of all the dependencies are returned,
+// and an error is returned together if any dependency is missing, mismatched, modified or has no sum locked.
+func (c *KpmClient) Verify(options ...VerifyOption) ([]VerifiedDep, error) {
+	opts := &VerifyOptions{}
+	for _, option := range options {
+		if err := option(opts); err != nil {
+			return nil, err
+		}
+	}
+
+	kpkg := opts.KclPkg
+	if kpkg == nil {
+		return nil, fmt.Errorf("kcl package is nil")
+	}
+
+	lockDeps := kpkg.Dependencies.Deps
+	if lockDeps == nil {
+		return nil, fmt.Errorf("kcl.mod.lock dependencies is nil")
+	}
+
+	var results []VerifiedDep
+	var failed []string
+	for _, name := range lockDeps.Keys() {
+		dep, ok := lockDeps.Get(name)
+		if !ok {
+			return nil, fmt.Errorf("failed to get dependency %s", name)
+		}
+		// The dependency redirected by the [replace] section in kcl.mod is verified in the replacement.
+		if replacedDep, ok := kpkg.ModFile.Replaces.ReplaceDep(&dep); ok {
+			replacedDep.Sum = dep.Sum
+			dep = *replacedDep
+		}
+		if dep.IsFromLocal() {
+			continue
+		}
+
+		result := c.verifyDep(kpkg, &dep)
+		if result.Failed() {
+			failed = append(failed, result.Name)
+			reporter.ReportMsgTo(
+				fmt.Sprintf("dependency '%s:%s' is %s: %s", result.Name, result.Version, result.Status, result.Reason),
+				c.logWriter,
+			)
+		}
+		results = append(results, result)
+	}
+
+	if len(failed) != 0 {
+		return results, reporter.NewErrorEvent(
+			reporter.FailedVerifyDeps,
+			fmt.Errorf("%d dependencies failed to verify: %v", len(failed), failed),
+			"the package cache or the vendor directory may be corrupted, please remove them and download again",
+		)
+	}
+
+	return results, nil
+}
+
+// verifyDep will verify a dependency locked in kcl.mod.lock
+// against the package in the package cache or the vendor directory.
+func (c *KpmClient) verifyDep(kpkg *pkg.KclPkg, dep *pkg.Dependency) VerifiedDep {
+	depPath := c.getDepStorePath(kpkg.HomePath, dep, kpkg.IsVendorMode())
+	result := VerifiedDep{
+		Name:        dep.Name,
+		Version:     dep.Version,
+		Path:        depPath,
+		ExpectedSum: dep.Sum,
+	}
+
+	if !utils.DirExists(depPath) {
+		result.Status = VerifyMissing
+		result.Reason = fmt.Sprintf("not found in '%s'", depPath)
+		return result
+	}
+
+	pkgPath := depPath
+	if dep.GetPackage() != "" {
+		var err error
+		pkgPath, err = utils.FindPackage(depPath, dep.GetPackage())
+		if err != nil {
+			result.Status = VerifyMissing
+			result.Reason = err.Error()
+			return result
+		}
+	}
+
+	depPkg, err := c.LoadPkgFromPath(pkgPath)
+	if err != nil {
+		result.Status = VerifyModified
+		result.Reason = fmt.Sprintf("failed to load the package: %s", err.Error())
+		return result
+	}
+	// The version of the replacement may be different from the version of the dependency it replaces.
+	if (dep.GetPackage() == "" && depPkg.GetPkgName() != dep.Name && depPkg.GetPkgName() != dep.PkgName()) || (depPkg.GetPkgVersion() != dep.Version && !dep.IsReplaced()) {
+		result.Status = VerifyMismatched
+		result.Reason = fmt.Sprintf("found '%s:%s' in '%s'", depPkg.GetPkgName(), depPkg.GetPkgVersion(), depPath)
+		return result
+	}
+
+	if dep.Sum == "" {
+		result.Status = VerifyUnchecked
+		result.Reason = "no sum recorded in kcl.mod.lock, run 'kpm update' to lock the sum"
+		return result
+	}
+
+	// The sum computed by the algorithm before 'h1:' is still verified by the same algorithm.
+	result.ActualSum, err = utils.HashDirAs(depPath, dep.Sum)
+	if err != nil {
+		result.Status = VerifyModified
+		result.Reason = fmt.Sprintf("failed to compute the sum: %s", err.Error())
+		return result
+	}
+	if result.ActualSum != dep.Sum {
+		result.Status = VerifyModified
+		result.Reason = fmt.Sprintf("sum '%s' does not match '%s' in kcl.mod.lock", result.ActualSum, dep.Sum)
+		return result
+	}
+
+	result.Status = VerifyOk
+	return result
+}
@@ -0,0 +1,37 @@
+package client
+
+import (
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestVerify(t *testing.T) {
+	testDir := getTestDir("test_verify")
+	kpmcli, err := NewKpmClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	kpmcli.SetHomePath(filepath.Join(testDir, "cache"))
+
+	kpkg, err := kpmcli.LoadPkgFromPath(filepath.Join(testDir, "pkg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedDeps, err := kpmcli.Verify(WithVerifyKclPkg(kpkg))
+	assert.ErrorContains(t, err, "4 dependencies failed to verify: [json_merge_patch k8s nginx podinfo]")
+
+	statuses := make(map[string]VerifyStatus)
+	for _, dep := range verifiedDeps {
+		statuses[dep.Name] = dep.Status
+	}
+	assert.DeepEqual(t, statuses, map[string]VerifyStatus{
+		"helloworld":       VerifyOk,
+		"json_merge_patch": VerifyMissing,
+		"k8s":              VerifyModified,
+		"nginx":            VerifyUnchecked,
+		"podinfo":          VerifyMismatched,
+	})
+}
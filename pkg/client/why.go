@@ -0,0 +1,137 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+
+	"golang.org/x/mod/module"
+	pkg "kcl-lang.io/kpm/pkg/package"
+	"kcl-lang.io/kpm/pkg/reporter"
+)
+
+// WhyOptions is the option for explaining why a dependency is required by a package.
+type WhyOptions struct {
+	// KclPkg is the root package of the dependency graph.
+	KclPkg *pkg.KclPkg
+	// DepName is the name of the dependency to be explained.
+	DepName string
+}
+
+type WhyOption func(*WhyOptions) error
+
+// WithWhyKclPkg sets the root package of the dependency graph.
+func WithWhyKclPkg(kpkg *pkg.KclPkg) WhyOption {
+	return func(opts *WhyOptions) error {
+		opts.KclPkg = kpkg
+		return nil
+	}
+}
+
+// WithWhyDepName sets the name of the dependency to be explained.
+func WithWhyDepName(depName string) WhyOption {
+	return func(opts *WhyOptions) error {
+		opts.DepName = depName
+		return nil
+	}
+}
+
+// Why will return all the shortest paths from the root package to the dependency in the dependency graph.
+// Each path starts with the root package and ends with the dependency,
+// and the version of each module in the path is the version required by its parent.
+// If the dependency is required by the package in different versions,
+// the shortest paths to each version will be returned.
+func (c *KpmClient) Why(options ...WhyOption) ([][]module.Version, error) {
+	opts := &WhyOptions{}
+	for _, option := range options {
+		if err := option(opts); err != nil {
+			return nil, err
+		}
+	}
+
+	kpkg := opts.KclPkg
+	if kpkg == nil {
+		return nil, fmt.Errorf("kcl package is nil")
+	}
+
+	_, depGraph, err := c.InitGraphAndDownloadDeps(kpkg)
+	if err != nil {
+		return nil, err
+	}
+
+	adjMap, err := depGraph.AdjacencyMap()
+	if err != nil {
+		return nil, err
+	}
+
+	root := module.Version{Path: kpkg.GetPkgName(), Version: kpkg.GetPkgVersion()}
+
+	// Calculate the distance from the root to each module and the parents on the shortest paths by BFS.
+	distances := map[module.Version]int{root: 0}
+	parents := map[module.Version][]module.Version{}
+	queue := []module.Version{root}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range sortedModules(adjMap[current]) {
+			if dist, ok := distances[next]; !ok {
+				distances[next] = distances[current] + 1
+				parents[next] = append(parents[next], current)
+				queue = append(queue, next)
+			} else if dist == distances[current]+1 {
+				parents[next] = append(parents[next], current)
+			}
+		}
+	}
+
+	var targets []module.Version
+	for m := range distances {
+		if m.Path == opts.DepName && m != root {
+			targets = append(targets, m)
+		}
+	}
+	if len(targets) == 0 {
+		return nil, reporter.NewErrorEvent(
+			reporter.DependencyNotFound,
+			fmt.Errorf("'%s' is not required by '%s'", opts.DepName, kpkg.GetPkgName()),
+		)
+	}
+	sortModules(targets)
+
+	var paths [][]module.Version
+	var walk func(m module.Version, suffix []module.Version)
+	walk = func(m module.Version, suffix []module.Version) {
+		path := append([]module.Version{m}, suffix...)
+		if m == root {
+			paths = append(paths, path)
+			return
+		}
+		for _, parent := range parents[m] {
+			walk(parent, path)
+		}
+	}
+	for _, target := range targets {
+		walk(target, nil)
+	}
+
+	return paths, nil
+}
+
+// sortedModules returns the modules in the map sorted by path and version.
+func sortedModules[T any](m map[module.Version]T) []module.Version {
+	modules := make([]module.Version, 0, len(m))
+	for k := range m {
+		modules = append(modules, k)
+	}
+	sortModules(modules)
+	return modules
+}
+
+// sortModules sorts the modules by path and version.
+func sortModules(modules []module.Version) {
+	sort.Slice(modules, func(i, j int) bool {
+		if modules[i].Path != modules[j].Path {
+			return modules[i].Path < modules[j].Path
+		}
+		return modules[i].Version < modules[j].Version
+	})
+}
@@ -0,0 +1,46 @@
+package client
+
+import (
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/mod/module"
+	"gotest.tools/v3/assert"
+)
+
+func TestWhy(t *testing.T) {
+	pkgPath := filepath.Join(getTestDir("test_why"), "pkg")
+
+	kpmcli, err := NewKpmClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kpkg, err := kpmcli.LoadPkgFromPath(pkgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paths, err := kpmcli.Why(
+		WithWhyKclPkg(kpkg),
+		WithWhyDepName("dep_1"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := module.Version{Path: "pkg", Version: "0.0.1"}
+	dep0 := module.Version{Path: "dep_0", Version: "0.0.1"}
+	dep1 := module.Version{Path: "dep_1", Version: "0.0.1"}
+	dep2 := module.Version{Path: "dep_2", Version: "0.0.1"}
+	assert.DeepEqual(t, paths, [][]module.Version{
+		{root, dep0, dep1},
+		{root, dep2, dep1},
+	})
+
+	_, err = kpmcli.Why(
+		WithWhyKclPkg(kpkg),
+		WithWhyDepName("not_exist"),
+	)
+	assert.ErrorContains(t, err, "'not_exist' is not required by 'pkg'")
+}
@@ -0,0 +1,182 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dominikbraun/graph"
+	"golang.org/x/mod/module"
+	pkg "kcl-lang.io/kpm/pkg/package"
+	"kcl-lang.io/kpm/pkg/reporter"
+	"kcl-lang.io/kpm/pkg/semver"
+)
+
+// SetWorkspace will set the workspace of the client.
+// After the workspace is set, the dependencies on the member packages of the workspace
+// are resolved to the local copies of the members instead of being downloaded.
+// Passing nil will unset the workspace.
+func (c *KpmClient) SetWorkspace(ws *pkg.Workspace) error {
+	if ws == nil {
+		c.workspace = nil
+		c.workspaceMembers = nil
+		return nil
+	}
+
+	memberPaths, err := ws.MemberPaths()
+	if err != nil {
+		return err
+	}
+
+	members := make(map[string]*pkg.KclPkg, len(memberPaths))
+	for _, memberPath := range memberPaths {
+		member, err := c.LoadPkgFromPath(memberPath)
+		if err != nil {
+			return err
+		}
+		if exist, ok := members[member.GetPkgName()]; ok {
+			return reporter.NewErrorEvent(
+				reporter.FailedLoadKclWork,
+				fmt.Errorf("package '%s' exists in both '%s' and '%s'", member.GetPkgName(), exist.HomePath, memberPath),
+				"the names of the workspace members must be unique",
+			)
+		}
+		members[member.GetPkgName()] = member
+	}
+
+	c.workspace = ws
+	c.workspaceMembers = members
+	return nil
+}
+
+// GetWorkspace returns the workspace of the client, returns nil if the workspace is not set.
+func (c *KpmClient) GetWorkspace() *pkg.Workspace {
+	return c.workspace
+}
+
+// LoadWorkspaceMembers will load all the member packages of the workspace, sorted by path.
+func (c *KpmClient) LoadWorkspaceMembers() ([]*pkg.KclPkg, error) {
+	if c.workspace == nil {
+		return nil, reporter.NewErrorEvent(reporter.FailedLoadKclWork, fmt.Errorf("no workspace found"))
+	}
+
+	memberPaths, err := c.workspace.MemberPaths()
+	if err != nil {
+		return nil, err
+	}
+
+	var members []*pkg.KclPkg
+	for _, memberPath := range memberPaths {
+		member, err := c.LoadPkgFromPath(memberPath)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+// InitWorkspaceGraphAndDownloadDeps will download the dependencies of all the members in the workspace,
+// and build one dependency graph of them. Each member is a root of the graph,
+// and a member required by another member is the same vertex as the root of the member.
+func (c *KpmClient) InitWorkspaceGraphAndDownloadDeps() ([]*pkg.KclPkg, graph.Graph[module.Version, module.Version], error) {
+	members, err := c.LoadWorkspaceMembers()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	moduleHash := func(m module.Version) module.Version {
+		return m
+	}
+	depGraph := graph.New(moduleHash, graph.Directed(), graph.PreventCycles())
+
+	for _, member := range members {
+		root := module.Version{Path: member.GetPkgName(), Version: member.GetPkgVersion()}
+		err := depGraph.AddVertex(root, graph.VertexAttribute(pkg.LOCAL, member.HomePath))
+		if err != nil && err != graph.ErrVertexAlreadyExists {
+			return nil, nil, err
+		}
+	}
+
+	for _, member := range members {
+		root := module.Version{Path: member.GetPkgName(), Version: member.GetPkgVersion()}
+		_, err := c.downloadRootDeps(member, depGraph, root)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return members, depGraph, nil
+}
+
+// workspaceMember returns the path of the workspace member required by the dependency.
+// The dependencies from the local path are not replaced by the workspace members.
+// If the version of the member does not satisfy the version required by the dependency,
+// the member is not used and the dependency is resolved as usual with a warning.
+func (c *KpmClient) workspaceMember(dep *pkg.Dependency) (string, bool) {
+	if c.workspaceMembers == nil || dep.IsFromLocal() {
+		return "", false
+	}
+	name := dep.Name
+	if dep.IsAliased() {
+		name = dep.PkgName()
+	}
+	member, ok := c.workspaceMembers[name]
+	if !ok {
+		return "", false
+	}
+	if !memberSatisfies(dep, member.GetPkgVersion()) {
+		reporter.ReportMsgTo(
+			fmt.Sprintf(
+				"warning: the workspace member '%s:%s' in '%s' does not match the version '%s' required by '%s', the required version is used",
+				name, member.GetPkgVersion(), member.HomePath, requiredVersion(dep), dep.Name,
+			),
+			c.logWriter,
+		)
+		return "", false
+	}
+	return member.HomePath, true
+}
+
+// memberSatisfies returns true if the version of the workspace member satisfies the version required by the dependency.
+// The dependency without a version, e.g. on a git branch, is satisfied by any version of the member.
+func memberSatisfies(dep *pkg.Dependency, memberVersion string) bool {
+	if versionRange := dep.Source.VersionRange(); versionRange != "" {
+		inRange, err := semver.VersionInRange(memberVersion, versionRange)
+		return err == nil && inRange
+	}
+	required := dep.Source.ExactVersion()
+	if required == "" {
+		return true
+	}
+	return strings.TrimPrefix(required, "v") == strings.TrimPrefix(memberVersion, "v")
+}
+
+// requiredVersion returns the version or the version range required by the dependency.
+func requiredVersion(dep *pkg.Dependency) string {
+	if versionRange := dep.Source.VersionRange(); versionRange != "" {
+		return versionRange
+	}
+	return dep.Source.ExactVersion()
+}
+
+// workspaceMemberDep returns the dependency resolved to the local copy of the workspace member.
+// The checksum in kcl.mod.lock is kept, because the member is not downloaded.
+// The member without the checksum locked for its version is kept out of kcl.mod.lock,
+// so that the lock file only records the packages from the registry.
+func (c *KpmClient) workspaceMemberDep(dep *pkg.Dependency, memberPath string, lockDeps *pkg.Dependencies) (*pkg.Dependency, error) {
+	member, err := c.LoadPkgFromPath(memberPath)
+	if err != nil {
+		return nil, err
+	}
+
+	memberDep := *dep
+	memberDep.Source = dep.Source.WithExactVersion(member.GetPkgVersion())
+	memberDep.FromKclPkg(member)
+	if lockDeps != nil {
+		if lockedDep, ok := lockDeps.Deps.Get(dep.Name); ok && lockedDep.Version == memberDep.Version {
+			memberDep.Sum = lockedDep.Sum
+		}
+	}
+	memberDep.FromWorkspace = len(memberDep.Sum) == 0
+	return &memberDep, nil
+}
@@ -0,0 +1,145 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"kcl-lang.io/kpm/pkg/downloader"
+	pkg "kcl-lang.io/kpm/pkg/package"
+)
+
+func TestWorkspaceMemberDeps(t *testing.T) {
+	wsPath := getTestDir("test_workspace")
+	pkgPath := filepath.Join(wsPath, "pkgs", "a")
+	lockPath := filepath.Join(pkgPath, "kcl.mod.lock")
+	defer func() {
+		_ = os.RemoveAll(lockPath)
+	}()
+
+	kpmcli, err := NewKpmClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ws, err := pkg.LoadWorkspace(wsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = kpmcli.SetWorkspace(ws)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	members, err := kpmcli.LoadWorkspaceMembers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, len(members), 2)
+	assert.Equal(t, members[0].GetPkgName(), "a")
+	assert.Equal(t, members[1].GetPkgName(), "b")
+
+	kpkg, err := kpmcli.LoadPkgFromPath(pkgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The dependency on the member 'b' is resolved to the local copy without downloading.
+	pkgMap, err := kpmcli.ResolveDepsIntoMap(kpkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, pkgMap["b"], filepath.Join(wsPath, "pkgs", "b"))
+
+	// The member not locked from the registry is kept out of kcl.mod.lock.
+	lockDeps, err := pkg.LoadLockDeps(pkgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, ok := lockDeps.Deps.Get("b")
+	assert.Equal(t, ok, false)
+
+	// The member locked from the registry with the same version keeps its entry in kcl.mod.lock.
+	lockedSum := "h1:rWHyD1lSJW8Pb7t2Tm2s7fUDQyLsUy9DdnQOS6zrGwU="
+	err = os.WriteFile(lockPath, []byte(`[dependencies]
+  [dependencies.b]
+    name = "b"
+    full_name = "b_0.0.1"
+    version = "0.0.1"
+    sum = "`+lockedSum+`"
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kpkg, err = kpmcli.LoadPkgFromPath(pkgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkgMap, err = kpmcli.ResolveDepsIntoMap(kpkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, pkgMap["b"], filepath.Join(wsPath, "pkgs", "b"))
+	lockDeps, err = pkg.LoadLockDeps(pkgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lockedB, ok := lockDeps.Deps.Get("b")
+	assert.Equal(t, ok, true)
+	assert.Equal(t, lockedB.Version, "0.0.1")
+	assert.Equal(t, lockedB.Sum, lockedSum)
+
+	_, depGraph, err := kpmcli.InitWorkspaceGraphAndDownloadDeps()
+	if err != nil {
+		t.Fatal(err)
+	}
+	adjMap, err := depGraph.AdjacencyMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	edges := 0
+	for _, targets := range adjMap {
+		edges += len(targets)
+	}
+	assert.Equal(t, edges, 1)
+}
+
+func TestWorkspaceMemberVersion(t *testing.T) {
+	wsPath := getTestDir("test_workspace")
+	kpmcli, err := NewKpmClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ws, err := pkg.LoadWorkspace(wsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = kpmcli.SetWorkspace(ws)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	depOn := func(version string) *pkg.Dependency {
+		return &pkg.Dependency{
+			Name:    "b",
+			Version: version,
+			Source: downloader.Source{
+				ModSpec: &downloader.ModSpec{Name: "b", Version: version},
+			},
+		}
+	}
+
+	memberPath, ok := kpmcli.workspaceMember(depOn("0.0.1"))
+	assert.Equal(t, ok, true)
+	assert.Equal(t, memberPath, filepath.Join(wsPath, "pkgs", "b"))
+
+	_, ok = kpmcli.workspaceMember(depOn(">=0.0.1"))
+	assert.Equal(t, ok, true)
+
+	// The member of another version does not replace the version required.
+	_, ok = kpmcli.workspaceMember(depOn("0.0.2"))
+	assert.Equal(t, ok, false)
+	_, ok = kpmcli.workspaceMember(depOn("^0.1.0"))
+	assert.Equal(t, ok, false)
+}
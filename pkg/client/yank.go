@@ -0,0 +1,84 @@
+package client
+
+import (
+	"fmt"
+
+	"kcl-lang.io/kpm/pkg/downloader"
+	"kcl-lang.io/kpm/pkg/oci"
+	pkg "kcl-lang.io/kpm/pkg/package"
+	"kcl-lang.io/kpm/pkg/reporter"
+	"kcl-lang.io/kpm/pkg/utils"
+)
+
+// MarkOciVersion will mark the version of the package published in the oci registry as deprecated, yanked or active again.
+func (c *KpmClient) MarkOciVersion(ociSource *downloader.Oci, status *oci.VersionStatus) error {
+	if ociSource == nil || len(ociSource.Tag) == 0 {
+		return reporter.NewErrorEvent(reporter.InvalidPkgRef, fmt.Errorf("the version of the package to mark is not specified"))
+	}
+
+	switch status.Status {
+	case oci.VERSION_STATUS_ACTIVE, oci.VERSION_STATUS_DEPRECATED, oci.VERSION_STATUS_YANKED:
+	default:
+		return reporter.NewErrorEvent(reporter.FailedMarkPkgVersion, fmt.Errorf("invalid version status '%s'", status.Status))
+	}
+
+	ociCli, err := c.newOciClient(ociSource)
+	if err != nil {
+		return err
+	}
+	return ociCli.PushVersionStatus(ociSource.Tag, status)
+}
+
+// FetchOciVersionStatus will return the status of the version of the package published in the oci registry.
+// It returns nil if the version has never been marked.
+func (c *KpmClient) FetchOciVersionStatus(ociSource *downloader.Oci) (*oci.VersionStatus, error) {
+	ociCli, err := c.newOciClient(ociSource)
+	if err != nil {
+		return nil, err
+	}
+	return ociCli.FetchVersionStatus(ociSource.Tag)
+}
+
+// checkOciVersionStatus warns if the version of the oci dependency is deprecated,
+// and refuses the yanked version unless the version has been locked in kcl.mod.lock.
+// The version locked is not checked, so that the locked dependencies can be rebuilt without accessing the registry.
+func (c *KpmClient) checkOciVersionStatus(dep *pkg.Dependency, lockDeps *pkg.Dependencies) error {
+	// The dependency pinned by the digest is the content the user chooses, the status of the tag is not checked.
+	if dep.Source.Oci == nil || len(dep.Source.Oci.Tag) == 0 || dep.Source.Oci.IsPinned() {
+		return nil
+	}
+	if lockDeps != nil && lockDeps.Deps != nil {
+		if lockedDep, ok := lockDeps.Deps.Get(dep.Name); ok && lockedDep.Source.Oci != nil &&
+			lockedDep.Source.Oci.Reg == dep.Source.Oci.Reg &&
+			lockedDep.Source.Oci.Repo == dep.Source.Oci.Repo &&
+			lockedDep.Source.Oci.Tag == dep.Source.Oci.Tag {
+			return nil
+		}
+	}
+
+	ref := fmt.Sprintf("%s:%s", utils.JoinPath(dep.Source.Oci.Reg, dep.Source.Oci.Repo), dep.Source.Oci.Tag)
+	status, err := c.FetchOciVersionStatus(dep.Source.Oci)
+	if err != nil {
+		// The status is advisory, the dependency is still resolved if the status is not available.
+		reporter.ReportMsgTo(fmt.Sprintf("warning: failed to get the status of '%s': %v", ref, err), c.logWriter)
+		return nil
+	}
+
+	if status.IsYanked() {
+		return reporter.NewErrorEvent(
+			reporter.PkgVersionYanked,
+			fmt.Errorf("the version '%s' of '%s' is %s", dep.Source.Oci.Tag, dep.Name, status.Describe()),
+			fmt.Sprintf("failed to resolve '%s'", ref),
+		)
+	}
+	if status.IsDeprecated() {
+		reporter.ReportEventTo(
+			reporter.NewEvent(
+				reporter.PkgVersionDeprecated,
+				fmt.Sprintf("warning: the version '%s' of '%s' is %s", dep.Source.Oci.Tag, dep.Name, status.Describe()),
+			),
+			c.logWriter,
+		)
+	}
+	return nil
+}
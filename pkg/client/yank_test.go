@@ -0,0 +1,50 @@
+package client
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/elliotchance/orderedmap/v2"
+	"gotest.tools/v3/assert"
+	"kcl-lang.io/kpm/pkg/downloader"
+	pkg "kcl-lang.io/kpm/pkg/package"
+)
+
+func TestCheckOciVersionStatusOfLockedDep(t *testing.T) {
+	kpmcli, err := NewKpmClient()
+	assert.NilError(t, err)
+
+	dep := pkg.Dependency{
+		Name:    "helloworld",
+		Version: "0.1.2",
+		Source: downloader.Source{
+			Oci: &downloader.Oci{
+				Reg:  "localhost:1",
+				Repo: "kcl-lang/helloworld",
+				Tag:  "0.1.2",
+			},
+		},
+	}
+	lockDeps := &pkg.Dependencies{Deps: orderedmap.NewOrderedMap[string, pkg.Dependency]()}
+	lockDeps.Deps.Set(dep.Name, dep)
+
+	// The version locked is not checked, so the unreachable registry is not accessed.
+	assert.NilError(t, kpmcli.checkOciVersionStatus(&dep, lockDeps))
+
+	// The dependency not from the oci registry is not checked.
+	gitDep := pkg.Dependency{
+		Name:   "flask",
+		Source: downloader.Source{Git: &downloader.Git{Url: "https://github.com/kcl-lang/flask-demo-kcl-manifests.git"}},
+	}
+	assert.NilError(t, kpmcli.checkOciVersionStatus(&gitDep, lockDeps))
+
+	// The version not locked is checked against the registry,
+	// and the dependency is still resolved with a warning if the status is not available.
+	var buf bytes.Buffer
+	kpmcli.SetLogWriter(&buf)
+	newDep := dep
+	newDep.Source.Oci = &downloader.Oci{Reg: "localhost:1", Repo: "kcl-lang/helloworld", Tag: "0.1.3"}
+	assert.NilError(t, kpmcli.checkOciVersionStatus(&newDep, lockDeps))
+	assert.Assert(t, strings.Contains(buf.String(), "warning: failed to get the status of 'localhost:1/kcl-lang/helloworld:0.1.3'"))
+}
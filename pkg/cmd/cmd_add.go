@@ -43,7 +43,7 @@ func NewAddCmd(kpmcli *client.KpmClient) *cli.Command {
 			},
 			&cli.StringFlag{
 				Name:  "rename",
-				Usage: "rename the package name in kcl.mod.lock",
+				Usage: "add the dependency under a new name, e.g. to depend on two versions of one package",
 			},
 			&cli.StringSliceFlag{
 				Name:  "package",
@@ -20,3 +20,22 @@ const FLAG_SORT_KEYS = "sort_keys"
 
 const FLAG_QUIET = "quiet"
 const FLAG_NO_SUM_CHECK = "no_sum_check"
+const FLAG_LOCKED = "locked"
+const FLAG_WORKSPACE = "workspace"
+
+const FLAG_FORMAT = "format"
+const FLAG_DEPTH = "depth"
+const FLAG_FILTER = "filter"
+const FLAG_EXIT_CODE = "exit_code"
+
+const FLAG_REGISTRY = "registry"
+const FLAG_NAMESPACE = "namespace"
+
+const FLAG_MESSAGE = "message"
+const FLAG_REPLACEMENT = "replacement"
+const FLAG_UNDO = "undo"
+
+const FLAG_KEY = "key"
+const FLAG_NO_PROVENANCE = "no_provenance"
+
+const FLAG_ATTACH = "attach"
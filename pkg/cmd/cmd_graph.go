@@ -5,6 +5,8 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 
 	"github.com/dominikbraun/graph"
 	"github.com/urfave/cli/v2"
@@ -21,6 +23,25 @@ func NewGraphCmd(kpmcli *client.KpmClient) *cli.Command {
 		Hidden: false,
 		Name:   "graph",
 		Usage:  "prints the module dependency graph",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  FLAG_WORKSPACE,
+				Usage: "print the dependency graph of all the members in the workspace",
+			},
+			&cli.StringFlag{
+				Name:  FLAG_FORMAT,
+				Usage: "output format, 'text', 'dot', 'mermaid', 'json' or 'tree'",
+				Value: client.GRAPH_FORMAT_TEXT,
+			},
+			&cli.IntFlag{
+				Name:  FLAG_DEPTH,
+				Usage: "the max depth of the dependencies to print, 0 means no limit",
+			},
+			&cli.StringFlag{
+				Name:  FLAG_FILTER,
+				Usage: "only print the dependencies whose name contains the filter and the paths to them",
+			},
+		},
 		Action: func(c *cli.Context) error {
 			return KpmGraph(c, kpmcli)
 		},
@@ -28,6 +49,14 @@ func NewGraphCmd(kpmcli *client.KpmClient) *cli.Command {
 }
 
 func KpmGraph(c *cli.Context, kpmcli *client.KpmClient) error {
+	format := c.String(FLAG_FORMAT)
+	if !slices.Contains(client.GraphFormats, format) {
+		return reporter.NewErrorEvent(
+			reporter.InvalidCmd,
+			fmt.Errorf("invalid output format '%s', only %s are supported", format, strings.Join(client.GraphFormats, ", ")),
+		)
+	}
+
 	// acquire the lock of the package cache.
 	err := kpmcli.AcquirePackageCacheLock()
 	if err != nil {
@@ -53,47 +82,56 @@ func KpmGraph(c *cli.Context, kpmcli *client.KpmClient) error {
 		return err
 	}
 
-	kclPkg, err := pkg.LoadKclPkg(pwd)
-	if err != nil {
-		return err
-	}
+	var roots []module.Version
+	var depGraph graph.Graph[module.Version, module.Version]
+	if c.Bool(FLAG_WORKSPACE) {
+		if err = requireWorkspace(kpmcli); err != nil {
+			return err
+		}
+		var members []*pkg.KclPkg
+		members, depGraph, err = kpmcli.InitWorkspaceGraphAndDownloadDeps()
+		if err != nil {
+			return err
+		}
+		for _, member := range members {
+			roots = append(roots, module.Version{Path: member.GetPkgName(), Version: member.GetPkgVersion()})
+		}
+	} else {
+		kclPkg, err := pkg.LoadKclPkg(pwd)
+		if err != nil {
+			return err
+		}
 
-	err = kclPkg.ValidateKpmHome(globalPkgPath)
-	if err != (*reporter.KpmEvent)(nil) {
-		return err
-	}
+		err = kclPkg.ValidateKpmHome(globalPkgPath)
+		if err != (*reporter.KpmEvent)(nil) {
+			return err
+		}
 
-	_, depGraph, err := kpmcli.InitGraphAndDownloadDeps(kclPkg)
-	if err != nil {
-		return err
+		_, depGraph, err = kpmcli.InitGraphAndDownloadDeps(kclPkg)
+		if err != nil {
+			return err
+		}
+		roots = append(roots, module.Version{Path: kclPkg.GetPkgName(), Version: kclPkg.GetPkgVersion()})
 	}
 
-	adjMap, err := depGraph.AdjacencyMap()
+	depGraphView, err := client.NewDepGraph(
+		depGraph,
+		roots,
+		client.WithDepGraphDepth(c.Int(FLAG_DEPTH)),
+		client.WithDepGraphFilter(c.String(FLAG_FILTER)),
+	)
 	if err != nil {
-		return err
-	}
-
-	format := func(m module.Version) string {
-		formattedMsg := m.Path
-		if m.Version != "" {
-			formattedMsg += "@" + m.Version
-		}
-		return formattedMsg
+		return reporter.NewErrorEvent(reporter.InvalidCmd, err)
 	}
 
 	// print the dependency graph to stdout.
-	root := module.Version{Path: kclPkg.GetPkgName(), Version: kclPkg.GetPkgVersion()}
-	err = graph.BFS(depGraph, root, func(source module.Version) bool {
-		for target := range adjMap[source] {
-			reporter.ReportMsgTo(
-				fmt.Sprint(format(source), " ", format(target)),
-				kpmcli.GetLogWriter(),
-			)
-		}
-		return false
-	})
+	// The edges shared by the members of the workspace are only printed once.
+	out, err := depGraphView.Format(format)
 	if err != nil {
-		return err
+		return reporter.NewErrorEvent(reporter.InvalidCmd, err)
+	}
+	if len(out) != 0 {
+		reporter.ReportMsgTo(out, kpmcli.GetLogWriter())
 	}
 	return nil
 }
@@ -0,0 +1,87 @@
+// Copyright 2023 The KCL Authors. All rights reserved.
+// Deprecated: The entire contents of this file will be deprecated.
+// Please use the kcl cli - https://github.com/kcl-lang/cli.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+	"kcl-lang.io/kpm/pkg/client"
+	"kcl-lang.io/kpm/pkg/reporter"
+)
+
+const (
+	INFO_FORMAT_TEXT = "text"
+	INFO_FORMAT_JSON = "json"
+)
+
+// NewInfoCmd new a Command for `kpm info`.
+func NewInfoCmd(kpmcli *client.KpmClient) *cli.Command {
+	return &cli.Command{
+		Hidden:    false,
+		Name:      "info",
+		Usage:     "show the information of a remote package without downloading it",
+		ArgsUsage: "<oci url | git url | name:tag>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  FLAG_FORMAT,
+				Usage: "output format, 'text' or 'json'",
+				Value: INFO_FORMAT_TEXT,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			return KpmInfo(c, kpmcli)
+		},
+	}
+}
+
+func KpmInfo(c *cli.Context, kpmcli *client.KpmClient) error {
+	format := c.String(FLAG_FORMAT)
+	if format != INFO_FORMAT_TEXT && format != INFO_FORMAT_JSON {
+		return reporter.NewErrorEvent(
+			reporter.InvalidCmd,
+			fmt.Errorf("invalid output format '%s', only 'text' and 'json' are supported", format),
+		)
+	}
+
+	if c.NArg() != 1 {
+		return reporter.NewErrorEvent(reporter.InvalidCmd, fmt.Errorf("a package reference is required, e.g. 'kpm info oci://ghcr.io/kcl-lang/k8s?tag=1.28'"))
+	}
+
+	source, err := kpmcli.ParsePkgRef(c.Args().First())
+	if err != nil {
+		return err
+	}
+
+	info, err := kpmcli.Info(client.WithInfoSource(source))
+	if err != nil {
+		return err
+	}
+
+	if format == INFO_FORMAT_JSON {
+		jsonData, err := json.MarshalIndent(info, "", "  ")
+		if err != nil {
+			return reporter.NewErrorEvent(reporter.Bug, err, "internal bugs, please contact us to fix it.")
+		}
+		fmt.Println(string(jsonData))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "name:\t%s\n", info.Name)
+	fmt.Fprintf(w, "version:\t%s\n", orNone(info.Version))
+	fmt.Fprintf(w, "description:\t%s\n", orNone(info.Description))
+	fmt.Fprintf(w, "source:\t%s\n", info.Source)
+	fmt.Fprintf(w, "sum:\t%s\n", orNone(info.Sum))
+	fmt.Fprintf(w, "created:\t%s\n", orNone(info.Created))
+	fmt.Fprintf(w, "size:\t%d bytes\n", info.Size)
+	fmt.Fprintf(w, "digest:\t%s\n", orNone(info.Digest))
+	fmt.Fprintf(w, "dependencies:\t%s\n", orNone(strings.Join(info.Dependencies, ", ")))
+	return w.Flush()
+}
@@ -0,0 +1,142 @@
+// Copyright 2023 The KCL Authors. All rights reserved.
+// Deprecated: The entire contents of this file will be deprecated.
+// Please use the kcl cli - https://github.com/kcl-lang/cli.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+	"kcl-lang.io/kpm/pkg/client"
+	"kcl-lang.io/kpm/pkg/env"
+	"kcl-lang.io/kpm/pkg/reporter"
+)
+
+const (
+	OUTDATED_FORMAT_TABLE = "table"
+	OUTDATED_FORMAT_JSON  = "json"
+)
+
+// NewOutdatedCmd new a Command for `kpm outdated`.
+func NewOutdatedCmd(kpmcli *client.KpmClient) *cli.Command {
+	return &cli.Command{
+		Hidden: false,
+		Name:   "outdated",
+		Usage:  "show the dependencies with newer versions available",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  FLAG_FORMAT,
+				Usage: "output format, 'table' or 'json'",
+				Value: OUTDATED_FORMAT_TABLE,
+			},
+			&cli.BoolFlag{
+				Name:  FLAG_EXIT_CODE,
+				Usage: "exit with a non-zero code if any dependency is outdated",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			return KpmOutdated(c, kpmcli)
+		},
+	}
+}
+
+func KpmOutdated(c *cli.Context, kpmcli *client.KpmClient) error {
+	format := c.String(FLAG_FORMAT)
+	if format != OUTDATED_FORMAT_TABLE && format != OUTDATED_FORMAT_JSON {
+		return reporter.NewErrorEvent(
+			reporter.InvalidCmd,
+			fmt.Errorf("invalid output format '%s', only 'table' and 'json' are supported", format),
+		)
+	}
+
+	pwd, err := os.Getwd()
+	if err != nil {
+		return reporter.NewErrorEvent(reporter.Bug, err, "internal bugs, please contact us to fix it.")
+	}
+
+	globalPkgPath, err := env.GetAbsPkgPath()
+	if err != nil {
+		return err
+	}
+
+	kclPkg, err := kpmcli.LoadPkgFromPath(pwd)
+	if err != nil {
+		return err
+	}
+
+	err = kclPkg.ValidateKpmHome(globalPkgPath)
+	if err != (*reporter.KpmEvent)(nil) {
+		return err
+	}
+
+	outdatedDeps, err := kpmcli.Outdated(client.WithOutdatedKclPkg(kclPkg))
+	if err != nil {
+		return err
+	}
+
+	if format == OUTDATED_FORMAT_JSON {
+		if outdatedDeps == nil {
+			outdatedDeps = []client.OutdatedDep{}
+		}
+		jsonData, err := json.MarshalIndent(outdatedDeps, "", "  ")
+		if err != nil {
+			return reporter.NewErrorEvent(reporter.Bug, err, "internal bugs, please contact us to fix it.")
+		}
+		fmt.Println(string(jsonData))
+		return outdatedError(c, outdatedDeps)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSOURCE\tCURRENT\tCOMPATIBLE\tLATEST")
+	for _, dep := range outdatedDeps {
+		current := orNone(dep.Current)
+		if dep.Pinned {
+			current += " (pinned)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			dep.Name,
+			dep.Source,
+			current,
+			orNone(dep.LatestCompatible),
+			orNone(dep.Latest),
+		)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return outdatedError(c, outdatedDeps)
+}
+
+// outdatedError returns an error if '--exit_code' is set and any dependency is outdated,
+// so that the outdated dependencies can fail the ci.
+func outdatedError(c *cli.Context, outdatedDeps []client.OutdatedDep) error {
+	if !c.Bool(FLAG_EXIT_CODE) {
+		return nil
+	}
+	var names []string
+	for _, dep := range outdatedDeps {
+		if dep.IsOutdated() {
+			names = append(names, dep.Name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	return reporter.NewErrorEvent(
+		reporter.DepsOutdated,
+		fmt.Errorf("%d dependencies are outdated: %s", len(names), strings.Join(names, ", ")),
+	)
+}
+
+// orNone returns '-' if the string is empty.
+func orNone(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,34 @@
+// Copyright 2023 The KCL Authors. All rights reserved.
+// Deprecated: The entire contents of this file will be deprecated.
+// Please use the kcl cli - https://github.com/kcl-lang/cli.
+
+package cmd
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli/v2"
+	"kcl-lang.io/kpm/pkg/client"
+)
+
+func TestOutdatedError(t *testing.T) {
+	deps := []client.OutdatedDep{
+		{Name: "helloworld", Source: "oci", Current: "0.1.0", LatestCompatible: "0.1.2", Latest: "0.1.2"},
+		{Name: "k8s", Source: "oci", Current: "1.28", LatestCompatible: "1.28", Latest: "1.28"},
+		// The git dependency pinned to a branch does not fail the exit code.
+		{Name: "flask", Source: "git", Current: "main", Latest: "v0.1.0", Pinned: true},
+	}
+
+	set := flag.NewFlagSet("outdated", flag.ContinueOnError)
+	set.Bool(FLAG_EXIT_CODE, false, "")
+	c := cli.NewContext(cli.NewApp(), set, nil)
+	assert.Nil(t, outdatedError(c, deps))
+
+	assert.Nil(t, set.Set(FLAG_EXIT_CODE, "true"))
+	err := outdatedError(c, deps)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "1 dependencies are outdated: helloworld")
+	assert.Nil(t, outdatedError(c, deps[1:]))
+}
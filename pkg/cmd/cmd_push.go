@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"time"
 
 	"github.com/urfave/cli/v2"
 	"kcl-lang.io/kpm/pkg/client"
@@ -16,6 +17,7 @@ import (
 	"kcl-lang.io/kpm/pkg/opt"
 	pkg "kcl-lang.io/kpm/pkg/package"
 	"kcl-lang.io/kpm/pkg/reporter"
+	"kcl-lang.io/kpm/pkg/signature"
 	"kcl-lang.io/kpm/pkg/utils"
 )
 
@@ -37,6 +39,14 @@ func NewPushCmd(kpmcli *client.KpmClient) *cli.Command {
 				Name:  FLAG_VENDOR,
 				Usage: "push in vendor mode",
 			},
+			&cli.StringFlag{
+				Name:  FLAG_KEY,
+				Usage: "the path of the ed25519 or ECDSA private key in PEM to sign the provenance",
+			},
+			&cli.BoolFlag{
+				Name:  FLAG_NO_PROVENANCE,
+				Usage: "push without attaching the provenance",
+			},
 		},
 		Action: func(c *cli.Context) error {
 			return KpmPush(c, kpmcli)
@@ -48,15 +58,18 @@ func KpmPush(c *cli.Context, kpmcli *client.KpmClient) error {
 	localTarPath := c.String(FLAG_TAR_PATH)
 	ociUrl := c.Args().First()
 
-	var err error
+	provenanceOpts, err := genProvenanceOpts(c)
+	if err != nil {
+		return err
+	}
 
 	if len(localTarPath) == 0 {
 		// If the tar package to be pushed is not specified,
 		// the current kcl package is packaged into tar and pushed.
-		err = pushCurrentPackage(ociUrl, c.Bool(FLAG_VENDOR), kpmcli)
+		err = pushCurrentPackage(ociUrl, c.Bool(FLAG_VENDOR), provenanceOpts, kpmcli)
 	} else {
 		// Else push the tar package specified.
-		err = pushTarPackage(ociUrl, localTarPath, c.Bool(FLAG_VENDOR), kpmcli)
+		err = pushTarPackage(ociUrl, localTarPath, c.Bool(FLAG_VENDOR), provenanceOpts, kpmcli)
 	}
 
 	if err != nil {
@@ -66,6 +79,27 @@ func KpmPush(c *cli.Context, kpmcli *client.KpmClient) error {
 	return nil
 }
 
+// genProvenanceOpts returns the options of the provenance attached to the package pushed,
+// and nil if the provenance is not attached by '--no_provenance'.
+func genProvenanceOpts(c *cli.Context) ([]client.ProvenanceOption, error) {
+	if c.Bool(FLAG_NO_PROVENANCE) {
+		if len(c.String(FLAG_KEY)) != 0 {
+			return nil, reporter.NewErrorEvent(reporter.InvalidCmd, fmt.Errorf("'--%s' cannot be used with '--%s'", FLAG_KEY, FLAG_NO_PROVENANCE))
+		}
+		return nil, nil
+	}
+
+	opts := []client.ProvenanceOption{}
+	if keyPath := c.String(FLAG_KEY); len(keyPath) != 0 {
+		signer, err := signature.LoadPrivateKey(keyPath)
+		if err != nil {
+			return nil, reporter.NewErrorEvent(reporter.FailedSign, err)
+		}
+		opts = append(opts, client.WithProvenanceSigner(signer))
+	}
+	return opts, nil
+}
+
 // genDefaultOciUrlForKclPkg will generate the default oci url from the current package.
 func genDefaultOciUrlForKclPkg(pkg *pkg.KclPkg, kpmcli *client.KpmClient) (string, error) {
 
@@ -81,7 +115,7 @@ func genDefaultOciUrlForKclPkg(pkg *pkg.KclPkg, kpmcli *client.KpmClient) (strin
 }
 
 // pushCurrentPackage will push the current package to the oci registry.
-func pushCurrentPackage(ociUrl string, vendorMode bool, kpmcli *client.KpmClient) error {
+func pushCurrentPackage(ociUrl string, vendorMode bool, provenanceOpts []client.ProvenanceOption, kpmcli *client.KpmClient) error {
 	pwd, err := os.Getwd()
 
 	if err != nil {
@@ -102,12 +136,12 @@ func pushCurrentPackage(ociUrl string, vendorMode bool, kpmcli *client.KpmClient
 	}
 
 	// 2. push the package
-	return pushPackage(ociUrl, kclPkg, vendorMode, kpmcli)
+	return pushPackage(ociUrl, kclPkg, vendorMode, provenanceOpts, kpmcli)
 }
 
 // pushTarPackage will push the kcl package in tarPath to the oci registry.
 // If the tar in 'tarPath' is not a kcl package tar, pushTarPackage will return an error.
-func pushTarPackage(ociUrl, localTarPath string, vendorMode bool, kpmcli *client.KpmClient) error {
+func pushTarPackage(ociUrl, localTarPath string, vendorMode bool, provenanceOpts []client.ProvenanceOption, kpmcli *client.KpmClient) error {
 	var kclPkg *pkg.KclPkg
 	var err error
 
@@ -133,7 +167,7 @@ func pushTarPackage(ociUrl, localTarPath string, vendorMode bool, kpmcli *client
 	}
 
 	// 2. push the package
-	return pushPackage(ociUrl, kclPkg, vendorMode, kpmcli)
+	return pushPackage(ociUrl, kclPkg, vendorMode, provenanceOpts, kpmcli)
 }
 
 // pushPackage will push the kcl package to the oci registry.
@@ -141,7 +175,9 @@ func pushTarPackage(ociUrl, localTarPath string, vendorMode bool, kpmcli *client
 // 2. If the oci url is not specified, generate the default oci url from the current package.
 // 3. Generate the OCI options from oci url and the version of current kcl package.
 // 4. Push the package to the oci registry.
-func pushPackage(ociUrl string, kclPkg *pkg.KclPkg, vendorMode bool, kpmcli *client.KpmClient) error {
+// 5. Attach the provenance of the package to the package pushed, unless 'provenanceOpts' is nil.
+func pushPackage(ociUrl string, kclPkg *pkg.KclPkg, vendorMode bool, provenanceOpts []client.ProvenanceOption, kpmcli *client.KpmClient) error {
+	startedOn := time.Now()
 
 	tarPath, err := kpmcli.PackagePkg(kclPkg, vendorMode)
 	if err != nil {
@@ -185,11 +221,29 @@ func pushPackage(ociUrl string, kclPkg *pkg.KclPkg, vendorMode bool, kpmcli *cli
 		return err
 	}
 
+	ociOpts.Config, err = kclPkg.GenOciConfigFromPkg()
+	if err != nil {
+		return err
+	}
+
 	reporter.ReportMsgTo(fmt.Sprintf("package '%s' will be pushed", kclPkg.GetPkgName()), kpmcli.GetLogWriter())
 	// 4. Push it.
 	err = kpmcli.PushToOci(tarPath, ociOpts)
 	if err != (*reporter.KpmEvent)(nil) {
 		return err
 	}
-	return nil
+
+	// 5. Attach the provenance.
+	if provenanceOpts == nil {
+		return nil
+	}
+	return kpmcli.AttachProvenance(
+		ociOpts,
+		append(
+			provenanceOpts,
+			client.WithProvenanceKclPkg(kclPkg),
+			client.WithProvenanceTarPath(tarPath),
+			client.WithProvenanceStartedOn(startedOn),
+		)...,
+	)
 }
@@ -5,32 +5,55 @@
 package cmd
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"flag"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
-	"kcl-lang.io/kpm/pkg/client"
-	pkg "kcl-lang.io/kpm/pkg/package"
+	"github.com/urfave/cli/v2"
+	"kcl-lang.io/kpm/pkg/signature"
 )
 
-const testDataDir = "test_data"
+func TestGenProvenanceOpts(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	assert.Nil(t, err)
+	keyBytes, err := x509.MarshalPKCS8PrivateKey(key)
+	assert.Nil(t, err)
+	keyPath := filepath.Join(t.TempDir(), "key.pem")
+	assert.Nil(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: signature.PEM_TYPE_PRIVATE_KEY, Bytes: keyBytes}), 0600))
 
-func getTestDir(subDir string) string {
-	pwd, _ := os.Getwd()
-	testDir := filepath.Join(pwd, testDataDir)
-	testDir = filepath.Join(testDir, subDir)
+	set := flag.NewFlagSet("push", flag.ContinueOnError)
+	set.String(FLAG_KEY, "", "")
+	set.Bool(FLAG_NO_PROVENANCE, false, "")
+	c := cli.NewContext(cli.NewApp(), set, nil)
 
-	return testDir
-}
+	// The provenance is attached unsigned by default.
+	opts, err := genProvenanceOpts(c)
+	assert.Nil(t, err)
+	assert.NotNil(t, opts)
+	assert.Equal(t, 0, len(opts))
+
+	assert.Nil(t, set.Set(FLAG_KEY, keyPath))
+	opts, err = genProvenanceOpts(c)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(opts))
+
+	assert.Nil(t, set.Set(FLAG_NO_PROVENANCE, "true"))
+	_, err = genProvenanceOpts(c)
+	assert.ErrorContains(t, err, "'--key' cannot be used with '--no_provenance'")
+
+	assert.Nil(t, set.Set(FLAG_KEY, ""))
+	opts, err = genProvenanceOpts(c)
+	assert.Nil(t, err)
+	assert.Nil(t, opts)
 
-func TestGenDefaultOciUrlForKclPkg(t *testing.T) {
-	pkgPath := getTestDir("test_gen_oci_url")
-	kclPkg, err := pkg.LoadKclPkg(pkgPath)
-	assert.Equal(t, err, nil)
-	kpmcli, err := client.NewKpmClient()
-	assert.Equal(t, err, nil)
-	url, err := genDefaultOciUrlForKclPkg(kclPkg, kpmcli)
-	assert.Equal(t, err, nil)
-	assert.Equal(t, url, "oci://ghcr.io/kcl-lang/test_gen_oci_url")
+	assert.Nil(t, set.Set(FLAG_NO_PROVENANCE, "false"))
+	assert.Nil(t, set.Set(FLAG_KEY, filepath.Join(t.TempDir(), "not_exist.pem")))
+	_, err = genProvenanceOpts(c)
+	assert.NotNil(t, err)
 }
@@ -0,0 +1,80 @@
+// Copyright 2023 The KCL Authors. All rights reserved.
+// Deprecated: The entire contents of this file will be deprecated.
+// Please use the kcl cli - https://github.com/kcl-lang/cli.
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/urfave/cli/v2"
+	"kcl-lang.io/kpm/pkg/client"
+	"kcl-lang.io/kpm/pkg/env"
+	"kcl-lang.io/kpm/pkg/errors"
+	"kcl-lang.io/kpm/pkg/reporter"
+)
+
+// NewRemoveCmd new a Command for `kpm remove`.
+func NewRemoveCmd(kpmcli *client.KpmClient) *cli.Command {
+	return &cli.Command{
+		Hidden:    false,
+		Name:      "remove",
+		Usage:     "remove dependencies from kcl.mod and kcl.mod.lock",
+		ArgsUsage: "<name>...",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  FLAG_VENDOR,
+				Usage: "remove the dependencies no longer required from the vendor directory",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			return KpmRemove(c, kpmcli)
+		},
+	}
+}
+
+func KpmRemove(c *cli.Context, kpmcli *client.KpmClient) error {
+	if c.NArg() == 0 {
+		return reporter.NewErrorEvent(reporter.InvalidCmd, errors.InvalidRemoveOptions)
+	}
+
+	// acquire the lock of the package cache.
+	err := kpmcli.AcquirePackageCacheLock()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		// release the lock of the package cache after the function returns.
+		releaseErr := kpmcli.ReleasePackageCacheLock()
+		if releaseErr != nil && err == nil {
+			err = releaseErr
+		}
+	}()
+
+	pwd, err := os.Getwd()
+	if err != nil {
+		return reporter.NewErrorEvent(reporter.Bug, err, "internal bugs, please contact us to fix it.")
+	}
+
+	globalPkgPath, err := env.GetAbsPkgPath()
+	if err != nil {
+		return err
+	}
+
+	kclPkg, err := kpmcli.LoadPkgFromPath(pwd)
+	if err != nil {
+		return err
+	}
+
+	err = kclPkg.ValidateKpmHome(globalPkgPath)
+	if err != (*reporter.KpmEvent)(nil) {
+		return err
+	}
+
+	return kpmcli.Remove(
+		client.WithRemoveKclPkg(kclPkg),
+		client.WithRemoveDepNames(c.Args().Slice()),
+		client.WithPruneVendor(c.Bool(FLAG_VENDOR)),
+	)
+}
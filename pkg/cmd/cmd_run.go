@@ -46,6 +46,11 @@ func NewRunCmd(kpmcli *client.KpmClient) *cli.Command {
 				Name:  FLAG_NO_SUM_CHECK,
 				Usage: "do not check the checksum of the package and update kcl.mod.lock",
 			},
+			// '--workspace' will compile all the members in the workspace.
+			&cli.BoolFlag{
+				Name:  FLAG_WORKSPACE,
+				Usage: "run all the members in the workspace",
+			},
 
 			// KCL arg: --setting, -Y
 			&cli.StringSliceFlag{
@@ -103,6 +108,10 @@ func KpmRun(c *cli.Context, kpmcli *client.KpmClient) error {
 		}
 	}()
 
+	if c.Bool(FLAG_WORKSPACE) {
+		return runWorkspace(c, kpmcli)
+	}
+
 	kclOpts := CompileOptionFromCli(c)
 	kclOpts.SetNoSumCheck(c.Bool(FLAG_NO_SUM_CHECK))
 	runEntry, errEvent := runner.FindRunEntryFrom(c.Args().Slice())
@@ -159,6 +168,46 @@ func KpmRun(c *cli.Context, kpmcli *client.KpmClient) error {
 	return nil
 }
 
+// runWorkspace will compile all the members in the workspace,
+// and print the results of the members as the YAML documents separated by '---'.
+func runWorkspace(c *cli.Context, kpmcli *client.KpmClient) error {
+	err := requireWorkspace(kpmcli)
+	if err != nil {
+		return err
+	}
+
+	if c.NArg() != 0 {
+		return reporter.NewErrorEvent(
+			reporter.InvalidCmd,
+			fmt.Errorf("'--%s' can not be used together with the run entries", FLAG_WORKSPACE),
+		)
+	}
+
+	members, err := kpmcli.LoadWorkspaceMembers()
+	if err != nil {
+		return err
+	}
+
+	for i, member := range members {
+		kclOpts := CompileOptionFromCli(c)
+		kclOpts.SetNoSumCheck(c.Bool(FLAG_NO_SUM_CHECK))
+		kclOpts.SetPkgPath(member.HomePath)
+		compileResult, err := kpmcli.CompileWithOpts(kclOpts)
+		if err != nil {
+			return reporter.NewErrorEvent(
+				reporter.CompileFailed,
+				err,
+				fmt.Sprintf("failed to compile the workspace member '%s'", member.GetPkgName()),
+			)
+		}
+		if i != 0 {
+			fmt.Println("---")
+		}
+		fmt.Println(compileResult.GetRawYamlResult())
+	}
+	return nil
+}
+
 // CompileOptionFromCli will parse the kcl options from the cli context.
 func CompileOptionFromCli(c *cli.Context) *opt.CompileOptions {
 	opts := opt.DefaultCompileOptions()
@@ -0,0 +1,109 @@
+// Copyright 2023 The KCL Authors. All rights reserved.
+// Deprecated: The entire contents of this file will be deprecated.
+// Please use the kcl cli - https://github.com/kcl-lang/cli.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+	"kcl-lang.io/kpm/pkg/client"
+	"kcl-lang.io/kpm/pkg/env"
+	pkg "kcl-lang.io/kpm/pkg/package"
+	"kcl-lang.io/kpm/pkg/reporter"
+)
+
+// NewSbomCmd new a Command for `kpm sbom`.
+func NewSbomCmd(kpmcli *client.KpmClient) *cli.Command {
+	return &cli.Command{
+		Hidden: false,
+		Name:   "sbom",
+		Usage:  "generate the software bill of materials of the package and the dependencies",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  FLAG_FORMAT,
+				Usage: "output format, 'spdx-json' or 'cyclonedx-json'",
+				Value: client.SBOM_FORMAT_SPDX_JSON,
+			},
+			&cli.StringFlag{
+				Name:  FLAG_ATTACH,
+				Usage: "attach the SBOM to the version published in the oci registry, e.g. 'oci://ghcr.io/kcl-lang/k8s?tag=1.28'",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			return KpmSbom(c, kpmcli)
+		},
+	}
+}
+
+func KpmSbom(c *cli.Context, kpmcli *client.KpmClient) error {
+	format := c.String(FLAG_FORMAT)
+	if !slices.Contains(client.SbomFormats, format) {
+		return reporter.NewErrorEvent(
+			reporter.InvalidCmd,
+			fmt.Errorf("invalid output format '%s', only %s are supported", format, strings.Join(client.SbomFormats, ", ")),
+		)
+	}
+
+	// acquire the lock of the package cache.
+	err := kpmcli.AcquirePackageCacheLock()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		// release the lock of the package cache after the function returns.
+		releaseErr := kpmcli.ReleasePackageCacheLock()
+		if releaseErr != nil && err == nil {
+			err = releaseErr
+		}
+	}()
+
+	pwd, err := os.Getwd()
+	if err != nil {
+		return reporter.NewErrorEvent(reporter.Bug, err, "internal bugs, please contact us to fix it.")
+	}
+
+	globalPkgPath, err := env.GetAbsPkgPath()
+	if err != nil {
+		return err
+	}
+
+	kclPkg, err := pkg.LoadKclPkg(pwd)
+	if err != nil {
+		return err
+	}
+
+	err = kclPkg.ValidateKpmHome(globalPkgPath)
+	if err != (*reporter.KpmEvent)(nil) {
+		return err
+	}
+
+	sbom, err := kpmcli.Sbom(client.WithSbomKclPkg(kclPkg))
+	if err != nil {
+		return err
+	}
+
+	out, err := sbom.Format(format)
+	if err != nil {
+		return reporter.NewErrorEvent(reporter.InvalidCmd, err)
+	}
+
+	if attach := c.String(FLAG_ATTACH); len(attach) != 0 {
+		source, err := kpmcli.ParsePkgRef(attach)
+		if err != nil {
+			return err
+		}
+		if source.Oci == nil {
+			return reporter.NewErrorEvent(reporter.InvalidCmd, fmt.Errorf("the SBOM can only be attached to the versions published in the oci registry"))
+		}
+		return kpmcli.AttachSbom(source.Oci, format, out)
+	}
+
+	fmt.Println(string(out))
+	return nil
+}
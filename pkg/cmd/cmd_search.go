@@ -0,0 +1,96 @@
+// Copyright 2023 The KCL Authors. All rights reserved.
+// Deprecated: The entire contents of this file will be deprecated.
+// Please use the kcl cli - https://github.com/kcl-lang/cli.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+	"kcl-lang.io/kpm/pkg/client"
+	"kcl-lang.io/kpm/pkg/reporter"
+)
+
+const (
+	SEARCH_FORMAT_TABLE = "table"
+	SEARCH_FORMAT_JSON  = "json"
+)
+
+// NewSearchCmd new a Command for `kpm search`.
+func NewSearchCmd(kpmcli *client.KpmClient) *cli.Command {
+	return &cli.Command{
+		Hidden:    false,
+		Name:      "search",
+		Usage:     "search the kcl packages in the oci registry",
+		ArgsUsage: "[query]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  FLAG_REGISTRY,
+				Usage: "the oci registry to search, the default oci registry is used if it is not specified",
+			},
+			&cli.StringFlag{
+				Name:  FLAG_NAMESPACE,
+				Usage: "only search the repos under the namespace, e.g. 'kcl-lang'",
+			},
+			&cli.StringFlag{
+				Name:  FLAG_FORMAT,
+				Usage: "output format, 'table' or 'json'",
+				Value: SEARCH_FORMAT_TABLE,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			return KpmSearch(c, kpmcli)
+		},
+	}
+}
+
+func KpmSearch(c *cli.Context, kpmcli *client.KpmClient) error {
+	format := c.String(FLAG_FORMAT)
+	if format != SEARCH_FORMAT_TABLE && format != SEARCH_FORMAT_JSON {
+		return reporter.NewErrorEvent(
+			reporter.InvalidCmd,
+			fmt.Errorf("invalid output format '%s', only 'table' and 'json' are supported", format),
+		)
+	}
+
+	if c.NArg() > 1 {
+		return reporter.NewErrorEvent(reporter.InvalidCmd, fmt.Errorf("only one query can be searched at a time"))
+	}
+
+	results, err := kpmcli.Search(
+		client.WithSearchRegistry(c.String(FLAG_REGISTRY)),
+		client.WithSearchNamespace(c.String(FLAG_NAMESPACE)),
+		client.WithSearchQuery(c.Args().First()),
+	)
+	if err != nil {
+		return err
+	}
+
+	if format == SEARCH_FORMAT_JSON {
+		if results == nil {
+			results = []client.SearchResult{}
+		}
+		jsonData, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return reporter.NewErrorEvent(reporter.Bug, err, "internal bugs, please contact us to fix it.")
+		}
+		fmt.Println(string(jsonData))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tLATEST\tDESCRIPTION\tSOURCE")
+	for _, result := range results {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+			result.Name,
+			result.Latest,
+			orNone(result.Description),
+			result.Source,
+		)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,49 @@
+// Copyright 2023 The KCL Authors. All rights reserved.
+// Deprecated: The entire contents of this file will be deprecated.
+// Please use the kcl cli - https://github.com/kcl-lang/cli.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+	"kcl-lang.io/kpm/pkg/client"
+	"kcl-lang.io/kpm/pkg/reporter"
+)
+
+// NewSignCmd new a Command for `kpm sign`.
+func NewSignCmd(kpmcli *client.KpmClient) *cli.Command {
+	return &cli.Command{
+		Hidden:    false,
+		Name:      "sign",
+		Usage:     "sign a version published in the oci registry",
+		ArgsUsage: "<oci url | name:tag>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     FLAG_KEY,
+				Usage:    "the path of the ed25519 or ECDSA private key in PEM",
+				Required: true,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			return KpmSign(c, kpmcli)
+		},
+	}
+}
+
+func KpmSign(c *cli.Context, kpmcli *client.KpmClient) error {
+	if c.NArg() != 1 {
+		return reporter.NewErrorEvent(reporter.InvalidCmd, fmt.Errorf("a package reference with the version is required, e.g. 'oci://ghcr.io/kcl-lang/k8s?tag=1.28'"))
+	}
+
+	source, err := kpmcli.ParsePkgRef(c.Args().First())
+	if err != nil {
+		return err
+	}
+	if source.Oci == nil {
+		return reporter.NewErrorEvent(reporter.InvalidCmd, fmt.Errorf("only the versions published in the oci registry can be signed"))
+	}
+
+	return kpmcli.Sign(source.Oci, c.String(FLAG_KEY))
+}
@@ -10,6 +10,7 @@ import (
 	"slices"
 	"strings"
 
+	"github.com/hashicorp/go-version"
 	"github.com/urfave/cli/v2"
 	"golang.org/x/mod/module"
 	"kcl-lang.io/kpm/pkg/client"
@@ -24,14 +25,19 @@ import (
 // NewUpdateCmd new a Command for `kpm update`.
 func NewUpdateCmd(kpmcli *client.KpmClient) *cli.Command {
 	return &cli.Command{
-		Hidden: false,
-		Name:   "update",
-		Usage:  "Update dependencies listed in kcl.mod.lock based on kcl.mod",
+		Hidden:    false,
+		Name:      "update",
+		Usage:     "Update dependencies listed in kcl.mod.lock based on kcl.mod, or upgrade and downgrade the specified dependencies",
+		ArgsUsage: "[<pkg_name>[:<version>] ...]",
 		Flags: []cli.Flag{
 			&cli.BoolFlag{
 				Name:  FLAG_NO_SUM_CHECK,
 				Usage: "do not check the checksum of the package and update kcl.mod.lock",
 			},
+			&cli.BoolFlag{
+				Name:  FLAG_WORKSPACE,
+				Usage: "update the dependencies of all the members in the workspace",
+			},
 		},
 		Action: func(c *cli.Context) error {
 			return KpmUpdate(c, kpmcli)
@@ -56,6 +62,10 @@ func KpmUpdate(c *cli.Context, kpmcli *client.KpmClient) error {
 		}
 	}()
 
+	if c.Bool(FLAG_WORKSPACE) {
+		return updateWorkspace(c, kpmcli)
+	}
+
 	pwd, err := os.Getwd()
 	if err != nil {
 		return reporter.NewErrorEvent(reporter.Bug, err, "internal bugs, please contact us to fix it.")
@@ -76,17 +86,173 @@ func KpmUpdate(c *cli.Context, kpmcli *client.KpmClient) error {
 		return err
 	}
 
-	err = kpmcli.UpdateDeps(kclPkg)
+	if c.NArg() == 0 {
+		err = kpmcli.UpdateDeps(kclPkg)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+
+	return UpdateModules(kpmcli, kclPkg, c.Args().Slice())
+}
+
+// updateWorkspace will update the dependencies of all the members in the workspace.
+// If the dependencies are specified, only the members requiring them are updated.
+func updateWorkspace(c *cli.Context, kpmcli *client.KpmClient) error {
+	err := requireWorkspace(kpmcli)
 	if err != nil {
 		return err
 	}
+
+	globalPkgPath, err := env.GetAbsPkgPath()
+	if err != nil {
+		return err
+	}
+
+	members, err := kpmcli.LoadWorkspaceMembers()
+	if err != nil {
+		return err
+	}
+
+	// Find the specified dependencies required by each member before updating anything.
+	memberPkgInfos := make([][]string, len(members))
+	required := make(map[string]bool)
+	for i, member := range members {
+		for _, pkgInfo := range c.Args().Slice() {
+			pkgName, _, err := opt.ParseOciPkgNameAndVersion(strings.TrimSpace(pkgInfo))
+			if err != nil {
+				return err
+			}
+			if _, ok := member.ModFile.Dependencies.Deps.Get(pkgName); ok {
+				memberPkgInfos[i] = append(memberPkgInfos[i], pkgInfo)
+				required[pkgName] = true
+			}
+		}
+	}
+	for _, pkgInfo := range c.Args().Slice() {
+		pkgName, _, _ := opt.ParseOciPkgNameAndVersion(strings.TrimSpace(pkgInfo))
+		if !required[pkgName] {
+			return reporter.NewErrorEvent(
+				reporter.DependencyNotFound,
+				fmt.Errorf("dependency '%s' not found in any member of the workspace", pkgName),
+			)
+		}
+	}
+
+	for i, member := range members {
+		err = member.ValidateKpmHome(globalPkgPath)
+		if err != (*reporter.KpmEvent)(nil) {
+			return err
+		}
+
+		if c.NArg() == 0 {
+			err = kpmcli.UpdateDeps(member)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if len(memberPkgInfos[i]) == 0 {
+			continue
+		}
+		reporter.ReportMsgTo(fmt.Sprintf("updating the workspace member '%s'", member.GetPkgName()), kpmcli.GetLogWriter())
+		err = UpdateModules(kpmcli, member, memberPkgInfos[i])
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// UpdateModules upgrades or downgrades the dependencies specified by pkgInfos in the form of '<pkg_name>[:<version>]'.
+// The build list is recalculated by MVS with only these dependencies changed,
+// and kcl.mod and kcl.mod.lock are updated to record the versions that changed.
+func UpdateModules(kpmcli *client.KpmClient, kclPkg *pkg.KclPkg, pkgInfos []string) error {
+	// The dependencies on git branches are updated to the latest commits of the branches,
+	// which are not selected by MVS.
+	pkgInfos, gitBranches, err := splitGitBranches(kclPkg, pkgInfos)
+	if err != nil {
+		return err
+	}
+	if len(gitBranches) != 0 {
+		kclPkg.Dependencies.UnpinGitBranches(gitBranches...)
+		_, err = kpmcli.ResolveDepsMetadataInJsonStr(kclPkg, true)
+		if err != nil {
+			return err
+		}
+		err = kclPkg.UpdateModAndLockFile()
+		if err != nil {
+			return err
+		}
+		if len(pkgInfos) == 0 {
+			return nil
+		}
+	}
+
+	var modulesToUpgrade, modulesToDowngrade []module.Version
+	for _, pkgInfo := range pkgInfos {
+		err := GetModulesToUpdate(kclPkg, &modulesToUpgrade, &modulesToDowngrade, pkgInfo)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(modulesToUpgrade) == 0 && len(modulesToDowngrade) == 0 {
+		reporter.ReportMsgTo("all the dependencies are already up to date", kpmcli.GetLogWriter())
+		return nil
+	}
+
+	// The dependency graph is built from a copy of the package,
+	// because downloading the dependencies will change the dependencies of the package in memory.
+	graphPkg, err := kpmcli.LoadPkgFromPath(kclPkg.HomePath)
+	if err != nil {
+		return err
+	}
+	_, depGraph, err := kpmcli.InitGraphAndDownloadDeps(graphPkg)
+	if err != nil {
+		return err
+	}
+
+	reqs := mvs.ReqsGraph{
+		Graph:     depGraph,
+		KpmClient: kpmcli,
+		KpmPkg:    graphPkg,
+	}
+	target := module.Version{Path: kclPkg.GetPkgName(), Version: kclPkg.GetPkgVersion()}
+	buildList, err := mvs.UpdateBuildList(target, modulesToUpgrade, modulesToDowngrade, &reqs)
+	if err != nil {
+		return err
+	}
+
+	return kpmcli.ApplyBuildList(kclPkg, depGraph, buildList)
+}
+
+// splitGitBranches splits the dependencies on git branches specified without the version out of pkgInfos.
+// The names of the git branches and the rest of pkgInfos are returned.
+func splitGitBranches(kclPkg *pkg.KclPkg, pkgInfos []string) ([]string, []string, error) {
+	var rest, gitBranches []string
+	for _, pkgInfo := range pkgInfos {
+		pkgName, pkgVersion, err := opt.ParseOciPkgNameAndVersion(strings.TrimSpace(pkgInfo))
+		if err != nil {
+			return nil, nil, err
+		}
+		dep, ok := kclPkg.Dependencies.Deps.Get(pkgName)
+		if ok && pkgVersion == "" && dep.Source.Git != nil && len(dep.Source.Git.Branch) != 0 {
+			gitBranches = append(gitBranches, pkgName)
+			continue
+		}
+		rest = append(rest, pkgInfo)
+	}
+	return rest, gitBranches, nil
+}
+
 // GetModulesToUpdate validates if the packages is present in kcl.mod file and
 // find the latest version if version is not specified. Depending on the value of pkgVersion,
 // modulesToUpgrade or modulesToDowngrade will be updated.
-func GetModulesToUpdate(kclPkg *pkg.KclPkg, modulesToUpgrade []module.Version, modulesToDowngrade []module.Version, pkgInfo string) error {
+func GetModulesToUpdate(kclPkg *pkg.KclPkg, modulesToUpgrade *[]module.Version, modulesToDowngrade *[]module.Version, pkgInfo string) error {
 	pkgInfo = strings.TrimSpace(pkgInfo)
 	pkgName, pkgVersion, err := opt.ParseOciPkgNameAndVersion(pkgInfo)
 	if err != nil {
@@ -96,7 +262,17 @@ func GetModulesToUpdate(kclPkg *pkg.KclPkg, modulesToUpgrade []module.Version, m
 	var dep pkg.Dependency
 	var ok bool
 	if dep, ok = kclPkg.Deps.Get(pkgName); !ok {
-		return err
+		return reporter.NewErrorEvent(
+			reporter.DependencyNotFound,
+			fmt.Errorf("dependency '%s' not found in 'kcl.mod'", pkgName),
+		)
+	}
+
+	// The version in kcl.mod.lock is the version currently selected,
+	// which is different from the version in kcl.mod if the dependency is required by a version range.
+	currentVersion := dep.Version
+	if lockedDep, ok := kclPkg.Dependencies.Deps.Get(pkgName); ok {
+		currentVersion = lockedDep.Version
 	}
 
 	if pkgVersion == "" {
@@ -109,7 +285,7 @@ func GetModulesToUpdate(kclPkg *pkg.KclPkg, modulesToUpgrade []module.Version, m
 				fmt.Sprintf("failed to get releases for %s", pkgName),
 			)
 		}
-		pkgVersion, err = semver.LatestCompatibleVersion(releases, dep.Version)
+		pkgVersion, err = semver.LatestCompatibleVersion(releases, currentVersion)
 		if err != nil {
 			return reporter.NewErrorEvent(
 				reporter.FailedSelectLatestCompatibleVersion,
@@ -118,10 +294,20 @@ func GetModulesToUpdate(kclPkg *pkg.KclPkg, modulesToUpgrade []module.Version, m
 			)
 		}
 	}
-	if pkgVersion < dep.Version {
-		modulesToDowngrade = append(modulesToDowngrade, module.Version{Path: pkgName, Version: pkgVersion})
-	} else if pkgVersion > dep.Version {
-		modulesToUpgrade = append(modulesToUpgrade, module.Version{Path: pkgName, Version: pkgVersion})
+
+	newVer, err := version.NewVersion(pkgVersion)
+	if err != nil {
+		return reporter.NewErrorEvent(reporter.FailedParseVersion, err, fmt.Sprintf("failed to parse version %s for module %s", pkgVersion, pkgName))
+	}
+	currentVer, err := version.NewVersion(currentVersion)
+	if err != nil {
+		return reporter.NewErrorEvent(reporter.FailedParseVersion, err, fmt.Sprintf("failed to parse version %s for module %s", currentVersion, pkgName))
+	}
+
+	if newVer.LessThan(currentVer) {
+		*modulesToDowngrade = append(*modulesToDowngrade, module.Version{Path: pkgName, Version: pkgVersion})
+	} else if newVer.GreaterThan(currentVer) {
+		*modulesToUpgrade = append(*modulesToUpgrade, module.Version{Path: pkgName, Version: pkgVersion})
 	}
 	return nil
 }
@@ -0,0 +1,55 @@
+// Copyright 2023 The KCL Authors. All rights reserved.
+// Deprecated: The entire contents of this file will be deprecated.
+// Please use the kcl cli - https://github.com/kcl-lang/cli.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/elliotchance/orderedmap/v2"
+	"github.com/stretchr/testify/assert"
+	"kcl-lang.io/kpm/pkg/downloader"
+	pkg "kcl-lang.io/kpm/pkg/package"
+)
+
+func TestSplitGitBranches(t *testing.T) {
+	kclPkg := &pkg.KclPkg{
+		Dependencies: pkg.Dependencies{
+			Deps: orderedmap.NewOrderedMap[string, pkg.Dependency](),
+		},
+	}
+	kclPkg.Dependencies.Deps.Set("catalog", pkg.Dependency{
+		Name:    "catalog",
+		Version: "0.1.0",
+		Source: downloader.Source{
+			Git: &downloader.Git{
+				Url:            "https://github.com/kcl-lang/catalog.git",
+				Branch:         "main",
+				ResolvedCommit: "1fe7ea1",
+			},
+		},
+	})
+	kclPkg.Dependencies.Deps.Set("helloworld", pkg.Dependency{
+		Name:    "helloworld",
+		Version: "0.1.0",
+		Source: downloader.Source{
+			Oci: &downloader.Oci{
+				Reg:  "ghcr.io",
+				Repo: "kcl-lang/helloworld",
+				Tag:  "0.1.0",
+			},
+		},
+	})
+
+	rest, gitBranches, err := splitGitBranches(kclPkg, []string{"catalog", "helloworld:0.1.1"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"catalog"}, gitBranches)
+	assert.Equal(t, []string{"helloworld:0.1.1"}, rest)
+
+	// The git branch specified with the version is updated by MVS.
+	rest, gitBranches, err = splitGitBranches(kclPkg, []string{"catalog:0.1.1"})
+	assert.Nil(t, err)
+	assert.Empty(t, gitBranches)
+	assert.Equal(t, []string{"catalog:0.1.1"}, rest)
+}
@@ -0,0 +1,81 @@
+// Copyright 2023 The KCL Authors. All rights reserved.
+// Deprecated: The entire contents of this file will be deprecated.
+// Please use the kcl cli - https://github.com/kcl-lang/cli.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+	"kcl-lang.io/kpm/pkg/client"
+	"kcl-lang.io/kpm/pkg/env"
+	"kcl-lang.io/kpm/pkg/reporter"
+)
+
+// NewVerifyCmd new a Command for `kpm verify`.
+func NewVerifyCmd(kpmcli *client.KpmClient) *cli.Command {
+	return &cli.Command{
+		Hidden: false,
+		Name:   "verify",
+		Usage:  "verify the dependencies in the package cache or vendor against the sums in kcl.mod.lock",
+		Action: func(c *cli.Context) error {
+			return KpmVerify(c, kpmcli)
+		},
+	}
+}
+
+func KpmVerify(c *cli.Context, kpmcli *client.KpmClient) error {
+	// acquire the lock of the package cache.
+	err := kpmcli.AcquirePackageCacheLock()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		// release the lock of the package cache after the function returns.
+		releaseErr := kpmcli.ReleasePackageCacheLock()
+		if releaseErr != nil && err == nil {
+			err = releaseErr
+		}
+	}()
+
+	pwd, err := os.Getwd()
+	if err != nil {
+		return reporter.NewErrorEvent(reporter.Bug, err, "internal bugs, please contact us to fix it.")
+	}
+
+	globalPkgPath, err := env.GetAbsPkgPath()
+	if err != nil {
+		return err
+	}
+
+	kclPkg, err := kpmcli.LoadPkgFromPath(pwd)
+	if err != nil {
+		return err
+	}
+
+	err = kclPkg.ValidateKpmHome(globalPkgPath)
+	if err != (*reporter.KpmEvent)(nil) {
+		return err
+	}
+
+	verifiedDeps, verifyErr := kpmcli.Verify(client.WithVerifyKclPkg(kclPkg))
+	if verifiedDeps == nil && verifyErr != nil {
+		return verifyErr
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tVERSION\tSTATUS\tPATH")
+	for _, dep := range verifiedDeps {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", dep.Name, orNone(dep.Version), dep.Status, dep.Path)
+	}
+	err = w.Flush()
+	if err != nil {
+		return err
+	}
+
+	return verifyErr
+}
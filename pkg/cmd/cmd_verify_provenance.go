@@ -0,0 +1,107 @@
+// Copyright 2023 The KCL Authors. All rights reserved.
+// Deprecated: The entire contents of this file will be deprecated.
+// Please use the kcl cli - https://github.com/kcl-lang/cli.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+	"kcl-lang.io/kpm/pkg/client"
+	"kcl-lang.io/kpm/pkg/reporter"
+)
+
+// NewVerifyProvenanceCmd new a Command for `kpm verify-provenance`.
+func NewVerifyProvenanceCmd(kpmcli *client.KpmClient) *cli.Command {
+	return &cli.Command{
+		Hidden:    false,
+		Name:      "verify-provenance",
+		Usage:     "verify the build provenance attached to a version published in the oci registry",
+		ArgsUsage: "<oci url | name:tag>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  FLAG_FORMAT,
+				Usage: "output format, 'text' or 'json'",
+				Value: INFO_FORMAT_TEXT,
+			},
+			&cli.StringSliceFlag{
+				Name:  FLAG_KEY,
+				Usage: "the path of the ed25519 or ECDSA public key in PEM to verify the signature of the provenance",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			return KpmVerifyProvenance(c, kpmcli)
+		},
+	}
+}
+
+func KpmVerifyProvenance(c *cli.Context, kpmcli *client.KpmClient) error {
+	format := c.String(FLAG_FORMAT)
+	if format != INFO_FORMAT_TEXT && format != INFO_FORMAT_JSON {
+		return reporter.NewErrorEvent(
+			reporter.InvalidCmd,
+			fmt.Errorf("invalid output format '%s', only 'text' and 'json' are supported", format),
+		)
+	}
+
+	if c.NArg() != 1 {
+		return reporter.NewErrorEvent(reporter.InvalidCmd, fmt.Errorf("a package reference with the version is required, e.g. 'oci://ghcr.io/kcl-lang/k8s?tag=1.28'"))
+	}
+
+	source, err := kpmcli.ParsePkgRef(c.Args().First())
+	if err != nil {
+		return err
+	}
+	if source.Oci == nil {
+		return reporter.NewErrorEvent(reporter.InvalidCmd, fmt.Errorf("only the versions published in the oci registry have the provenance"))
+	}
+
+	statement, err := kpmcli.VerifyProvenance(source.Oci, c.StringSlice(FLAG_KEY)...)
+	if err != nil {
+		return err
+	}
+
+	if format == INFO_FORMAT_JSON {
+		jsonData, err := json.MarshalIndent(statement, "", "  ")
+		if err != nil {
+			return reporter.NewErrorEvent(reporter.Bug, err, "internal bugs, please contact us to fix it.")
+		}
+		fmt.Println(string(jsonData))
+		return nil
+	}
+
+	params := statement.Predicate.BuildDefinition.ExternalParameters
+	url, commit := statement.Source()
+	var deps []string
+	for _, dep := range statement.Dependencies() {
+		var digests []string
+		for algorithm, value := range dep.Digest {
+			digests = append(digests, fmt.Sprintf("%s:%s", algorithm, value))
+		}
+		sort.Strings(digests)
+		deps = append(deps, fmt.Sprintf("%s (%s)", dep.Name, strings.Join(digests, ", ")))
+	}
+
+	reporter.ReportMsgTo(fmt.Sprintf("verified the provenance of '%s'", params.Reference), kpmcli.GetLogWriter())
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "name:\t%s\n", params.Package)
+	fmt.Fprintf(w, "version:\t%s\n", orNone(params.Version))
+	fmt.Fprintf(w, "edition:\t%s\n", orNone(statement.Predicate.BuildDefinition.InternalParameters.Edition))
+	fmt.Fprintf(w, "source:\t%s\n", orNone(url))
+	if statement.SourceDirty() {
+		commit += " (dirty)"
+	}
+	fmt.Fprintf(w, "commit:\t%s\n", orNone(commit))
+	fmt.Fprintf(w, "kpm version:\t%s\n", orNone(statement.KpmVersion()))
+	for _, subject := range statement.Subject {
+		fmt.Fprintf(w, "subject:\t%s (sha256:%s)\n", subject.Name, subject.Digest["sha256"])
+	}
+	fmt.Fprintf(w, "dependencies:\t%s\n", orNone(strings.Join(deps, ", ")))
+	return w.Flush()
+}
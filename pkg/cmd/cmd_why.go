@@ -0,0 +1,105 @@
+// Copyright 2023 The KCL Authors. All rights reserved.
+// Deprecated: The entire contents of this file will be deprecated.
+// Please use the kcl cli - https://github.com/kcl-lang/cli.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+	"golang.org/x/mod/module"
+	"kcl-lang.io/kpm/pkg/client"
+	"kcl-lang.io/kpm/pkg/env"
+	"kcl-lang.io/kpm/pkg/errors"
+	"kcl-lang.io/kpm/pkg/reporter"
+)
+
+// NewWhyCmd new a Command for `kpm why`.
+func NewWhyCmd(kpmcli *client.KpmClient) *cli.Command {
+	return &cli.Command{
+		Hidden:    false,
+		Name:      "why",
+		Usage:     "explain why a dependency is required",
+		ArgsUsage: "<dep>",
+		Action: func(c *cli.Context) error {
+			return KpmWhy(c, kpmcli)
+		},
+	}
+}
+
+func KpmWhy(c *cli.Context, kpmcli *client.KpmClient) error {
+	if c.NArg() != 1 {
+		return reporter.NewErrorEvent(reporter.InvalidCmd, errors.InvalidWhyOptions)
+	}
+	depName := c.Args().First()
+
+	// acquire the lock of the package cache.
+	err := kpmcli.AcquirePackageCacheLock()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		// release the lock of the package cache after the function returns.
+		releaseErr := kpmcli.ReleasePackageCacheLock()
+		if releaseErr != nil && err == nil {
+			err = releaseErr
+		}
+	}()
+
+	pwd, err := os.Getwd()
+	if err != nil {
+		return reporter.NewErrorEvent(reporter.Bug, err, "internal bugs, please contact us to fix it.")
+	}
+
+	globalPkgPath, err := env.GetAbsPkgPath()
+	if err != nil {
+		return err
+	}
+
+	kclPkg, err := kpmcli.LoadPkgFromPath(pwd)
+	if err != nil {
+		return err
+	}
+
+	err = kclPkg.ValidateKpmHome(globalPkgPath)
+	if err != (*reporter.KpmEvent)(nil) {
+		return err
+	}
+
+	paths, err := kpmcli.Why(
+		client.WithWhyKclPkg(kclPkg),
+		client.WithWhyDepName(depName),
+	)
+	if err != nil {
+		return err
+	}
+
+	format := func(m module.Version) string {
+		formattedMsg := m.Path
+		if m.Version != "" {
+			formattedMsg += "@" + m.Version
+		}
+		return formattedMsg
+	}
+
+	header := "# " + depName
+	if lockedDep, ok := kclPkg.Dependencies.Deps.Get(depName); ok && lockedDep.Version != "" {
+		header += fmt.Sprintf(" (locked at %s)", lockedDep.Version)
+	}
+	reporter.ReportMsgTo(header, kpmcli.GetLogWriter())
+
+	// print each shortest path from the root package to the dependency.
+	for _, path := range paths {
+		var formatted []string
+		for _, m := range path {
+			formatted = append(formatted, format(m))
+		}
+		reporter.ReportMsgTo(strings.Join(formatted, " -> "), kpmcli.GetLogWriter())
+	}
+
+	return nil
+}
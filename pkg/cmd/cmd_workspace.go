@@ -0,0 +1,64 @@
+// Copyright 2023 The KCL Authors. All rights reserved.
+// Deprecated: The entire contents of this file will be deprecated.
+// Please use the kcl cli - https://github.com/kcl-lang/cli.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v2"
+	"kcl-lang.io/kpm/pkg/client"
+	pkg "kcl-lang.io/kpm/pkg/package"
+	"kcl-lang.io/kpm/pkg/reporter"
+)
+
+// SetWorkspaceFromWorkDir will set the workspace containing the current directory to the client,
+// so that the dependencies on the workspace members are resolved to the local copies.
+// Nothing happens if the current directory is not in any workspace.
+func SetWorkspaceFromWorkDir(kpmcli *client.KpmClient) error {
+	pwd, err := os.Getwd()
+	if err != nil {
+		return reporter.NewErrorEvent(reporter.Bug, err, "internal bugs, please contact us to fix it.")
+	}
+
+	root, ok := pkg.FindWorkspaceRoot(pwd)
+	if !ok {
+		return nil
+	}
+
+	ws, err := pkg.LoadWorkspace(root)
+	if err != nil {
+		return err
+	}
+	return kpmcli.SetWorkspace(ws)
+}
+
+// InWorkspace will load the workspace containing the current directory before running the command.
+// Only the commands resolving the dependencies of the current package use the workspace,
+// so that the other commands, e.g. 'kpm init', are not broken by an invalid 'kcl.work'.
+func InWorkspace(kpmcli *client.KpmClient, command *cli.Command) *cli.Command {
+	before := command.Before
+	command.Before = func(c *cli.Context) error {
+		if err := SetWorkspaceFromWorkDir(kpmcli); err != nil {
+			return err
+		}
+		if before != nil {
+			return before(c)
+		}
+		return nil
+	}
+	return command
+}
+
+// requireWorkspace will return an error if the commands with '--workspace' are not run in a workspace.
+func requireWorkspace(kpmcli *client.KpmClient) error {
+	if kpmcli.GetWorkspace() == nil {
+		return reporter.NewErrorEvent(
+			reporter.FailedLoadKclWork,
+			fmt.Errorf("'--%s' is set, but no 'kcl.work' found in the current directory or any parent directory", FLAG_WORKSPACE),
+		)
+	}
+	return nil
+}
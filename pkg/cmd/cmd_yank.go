@@ -0,0 +1,84 @@
+// Copyright 2023 The KCL Authors. All rights reserved.
+// Deprecated: The entire contents of this file will be deprecated.
+// Please use the kcl cli - https://github.com/kcl-lang/cli.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+	"kcl-lang.io/kpm/pkg/client"
+	"kcl-lang.io/kpm/pkg/oci"
+	"kcl-lang.io/kpm/pkg/reporter"
+)
+
+// NewDeprecateCmd new a Command for `kpm deprecate`.
+func NewDeprecateCmd(kpmcli *client.KpmClient) *cli.Command {
+	return &cli.Command{
+		Hidden:    false,
+		Name:      "deprecate",
+		Usage:     "mark a version published in the oci registry as deprecated",
+		ArgsUsage: "<oci url | name:tag>",
+		Flags:     markVersionFlags("deprecation"),
+		Action: func(c *cli.Context) error {
+			return KpmMarkVersion(c, kpmcli, oci.VERSION_STATUS_DEPRECATED)
+		},
+	}
+}
+
+// NewYankCmd new a Command for `kpm yank`.
+func NewYankCmd(kpmcli *client.KpmClient) *cli.Command {
+	return &cli.Command{
+		Hidden:    false,
+		Name:      "yank",
+		Usage:     "mark a version published in the oci registry as yanked, it can only be used by the packages having locked it",
+		ArgsUsage: "<oci url | name:tag>",
+		Flags:     markVersionFlags("yank"),
+		Action: func(c *cli.Context) error {
+			return KpmMarkVersion(c, kpmcli, oci.VERSION_STATUS_YANKED)
+		},
+	}
+}
+
+func markVersionFlags(action string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  FLAG_MESSAGE,
+			Usage: fmt.Sprintf("the reason of the %s shown to the consumers", action),
+		},
+		&cli.StringFlag{
+			Name:  FLAG_REPLACEMENT,
+			Usage: "the version suggested to use instead",
+		},
+		&cli.BoolFlag{
+			Name:  FLAG_UNDO,
+			Usage: fmt.Sprintf("undo the %s and mark the version as active again", action),
+		},
+	}
+}
+
+func KpmMarkVersion(c *cli.Context, kpmcli *client.KpmClient, status string) error {
+	if c.NArg() != 1 {
+		return reporter.NewErrorEvent(reporter.InvalidCmd, fmt.Errorf("a package reference with the version is required, e.g. 'oci://ghcr.io/kcl-lang/k8s?tag=1.28'"))
+	}
+
+	source, err := kpmcli.ParsePkgRef(c.Args().First())
+	if err != nil {
+		return err
+	}
+	if source.Oci == nil {
+		return reporter.NewErrorEvent(reporter.InvalidCmd, fmt.Errorf("only the versions published in the oci registry can be marked"))
+	}
+
+	versionStatus := &oci.VersionStatus{
+		Status:      status,
+		Message:     c.String(FLAG_MESSAGE),
+		Replacement: c.String(FLAG_REPLACEMENT),
+	}
+	if c.Bool(FLAG_UNDO) {
+		versionStatus = &oci.VersionStatus{Status: oci.VERSION_STATUS_ACTIVE}
+	}
+
+	return kpmcli.MarkOciVersion(source.Oci, versionStatus)
+}
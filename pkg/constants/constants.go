@@ -27,6 +27,7 @@ const (
 
 	KCL_MOD                              = "kcl.mod"
 	KCL_MOD_LOCK                         = "kcl.mod.lock"
+	KCL_WORK                             = "kcl.work"
 	KCL_YAML                             = "kcl.yaml"
 	OCI_SEPARATOR                        = ":"
 	KCL_PKG_TAR                          = "*.tar"
@@ -36,10 +37,33 @@ const (
 	DEFAULT_KCL_OCI_MANIFEST_VERSION     = "org.kcllang.package.version"
 	DEFAULT_KCL_OCI_MANIFEST_DESCRIPTION = "org.kcllang.package.description"
 	DEFAULT_KCL_OCI_MANIFEST_SUM         = "org.kcllang.package.sum"
+	DEFAULT_KCL_OCI_MANIFEST_SUM_H1      = "org.kcllang.package.sum.h1"
+	DEFAULT_KCL_OCI_MANIFEST_KEYWORDS    = "org.kcllang.package.keywords"
+	DEFAULT_KCL_OCI_MANIFEST_README      = "org.kcllang.package.readme"
+	DEFAULT_KCL_OCI_MANIFEST_DEPS        = "org.kcllang.package.dependencies"
 	DEFAULT_CREATE_OCI_MANIFEST_TIME     = "org.opencontainers.image.created"
 	URL_PATH_SEPARATOR                   = "/"
 	LATEST                               = "latest"
 
+	// The pre-defined annotations of the oci image spec for the metadata of the package.
+	OCI_MANIFEST_TITLE         = "org.opencontainers.image.title"
+	OCI_MANIFEST_VERSION       = "org.opencontainers.image.version"
+	OCI_MANIFEST_DESCRIPTION   = "org.opencontainers.image.description"
+	OCI_MANIFEST_LICENSES      = "org.opencontainers.image.licenses"
+	OCI_MANIFEST_AUTHORS       = "org.opencontainers.image.authors"
+	OCI_MANIFEST_URL           = "org.opencontainers.image.url"
+	OCI_MANIFEST_SOURCE        = "org.opencontainers.image.source"
+	OCI_MANIFEST_DOCUMENTATION = "org.opencontainers.image.documentation"
+
+	// The config blob of the kcl package holding kcl.mod and kcl.mod.lock.
+	DEFAULT_KCL_OCI_CONFIG_MEDIA_TYPE = "application/vnd.kcl.package.config.v1+json"
+
+	// The artifact referring to a published version to mark it as deprecated or yanked.
+	DEFAULT_KCL_OCI_STATUS_ARTIFACT_TYPE = "application/vnd.kcl.package.status.v1"
+	DEFAULT_KCL_OCI_STATUS_ANNOTATION    = "org.kcllang.package.status"
+	DEFAULT_KCL_OCI_STATUS_MESSAGE       = "org.kcllang.package.status.message"
+	DEFAULT_KCL_OCI_STATUS_REPLACEMENT   = "org.kcllang.package.status.replacement"
+
 	// The pattern of the external package argument.
 	EXTERNAL_PKGS_ARG_PATTERN = "%s=%s"
 
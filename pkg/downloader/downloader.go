@@ -251,7 +251,8 @@ func (d *OciDownloader) Download(opts DownloadOptions) error {
 
 	ociCli.PullOciOptions.Platform = d.Platform
 
-	if len(ociSource.Tag) == 0 {
+	// The oci source pinned by the digest is downloaded by the digest, the latest tag is not required.
+	if len(ociSource.Tag) == 0 && !ociSource.IsPinned() {
 		tagSelected, err := ociCli.TheLatestTag()
 		if err != nil {
 			return err
@@ -282,13 +283,13 @@ func (d *OciDownloader) Download(opts DownloadOptions) error {
 				if err != nil && errors.Is(err, utils.PkgArchiveNotFound) {
 					reporter.ReportMsgTo(
 						fmt.Sprintf(
-							"downloading '%s:%s' from '%s/%s:%s'",
-							ociSource.Repo, ociSource.Tag, ociSource.Reg, ociSource.Repo, ociSource.Tag,
+							"downloading '%s' from '%s'",
+							ociSource.displayRef(ociSource.Repo), ociSource.displayRef(ociSource.Reg+"/"+ociSource.Repo),
 						),
 						opts.LogWriter,
 					)
 
-					err = ociCli.Pull(cacheFullPath, ociSource.Tag)
+					err = pullOci(ociCli, ociSource, cacheFullPath)
 					if err != nil {
 						return err
 					}
@@ -313,13 +314,13 @@ func (d *OciDownloader) Download(opts DownloadOptions) error {
 	} else {
 		reporter.ReportMsgTo(
 			fmt.Sprintf(
-				"downloading '%s:%s' from '%s/%s:%s'",
-				ociSource.Repo, ociSource.Tag, ociSource.Reg, ociSource.Repo, ociSource.Tag,
+				"downloading '%s' from '%s'",
+				ociSource.displayRef(ociSource.Repo), ociSource.displayRef(ociSource.Reg+"/"+ociSource.Repo),
 			),
 			opts.LogWriter,
 		)
 
-		err = ociCli.Pull(localPath, ociSource.Tag)
+		err = pullOci(ociCli, ociSource, localPath)
 		if err != nil {
 			return err
 		}
@@ -349,14 +350,30 @@ func (d *OciDownloader) Download(opts DownloadOptions) error {
 	return err
 }
 
+// pullOci pulls the oci source to the local path by the digest if the source is pinned by the digest,
+// otherwise by the tag, and records the digest of the manifest pulled.
+func pullOci(ociCli *oci.OciClient, ociSource *Oci, localPath string) error {
+	if ociSource.IsPinned() {
+		return ociCli.PullByDigest(localPath, ociSource.Digest)
+	}
+	var err error
+	ociSource.Digest, err = ociCli.PullWithDigest(localPath, ociSource.Tag)
+	return err
+}
+
 func (d *GitDownloader) Download(opts DownloadOptions) error {
 	gitSource := opts.Source.Git
 	if gitSource == nil {
 		return errors.New("git source is nil")
 	}
+	// The branch pinned in kcl.mod.lock is checked out at the commit it is resolved to.
+	commit, branch := gitSource.Commit, gitSource.Branch
+	if len(gitSource.Branch) != 0 && len(gitSource.ResolvedCommit) != 0 {
+		commit, branch = gitSource.ResolvedCommit, ""
+	}
 	cloneOpts := []git.CloneOption{
-		git.WithCommit(gitSource.Commit),
-		git.WithBranch(gitSource.Branch),
+		git.WithCommit(commit),
+		git.WithBranch(branch),
 		git.WithTag(gitSource.Tag),
 	}
 
@@ -399,7 +416,7 @@ func (d *GitDownloader) Download(opts DownloadOptions) error {
 				}
 				// After cloning the bare repository,
 				// Clone the repository from the cache path to the local path.
-				_, err = git.CloneWithOpts(
+				repo, err := git.CloneWithOpts(
 					append(
 						cloneOpts,
 						git.WithRepoURL(cacheFullPath),
@@ -409,6 +426,13 @@ func (d *GitDownloader) Download(opts DownloadOptions) error {
 				if err != nil {
 					return err
 				}
+				// The branch is recorded with the commit it is resolved to.
+				if len(gitSource.Branch) != 0 {
+					gitSource.ResolvedCommit, err = git.HeadCommit(repo)
+					if err != nil {
+						return err
+					}
+				}
 			}
 		}
 	} else {
@@ -423,6 +447,9 @@ func (d *GitDownloader) Download(opts DownloadOptions) error {
 
 		if len(opts.Source.Git.Branch) != 0 {
 			msg = fmt.Sprintf("with branch '%s'", opts.Source.Git.Branch)
+			if len(opts.Source.Git.ResolvedCommit) != 0 {
+				msg = fmt.Sprintf("with branch '%s' at commit '%s'", opts.Source.Git.Branch, opts.Source.Git.ResolvedCommit)
+			}
 		}
 
 		reporter.ReportMsgTo(
@@ -430,22 +457,24 @@ func (d *GitDownloader) Download(opts DownloadOptions) error {
 			opts.LogWriter,
 		)
 		// download the package from the git repo
-		gitSource := opts.Source.Git
-		if gitSource == nil {
-			return errors.New("git source is nil")
-		}
-
-		_, err := git.CloneWithOpts(
-			git.WithCommit(gitSource.Commit),
-			git.WithBranch(gitSource.Branch),
-			git.WithTag(gitSource.Tag),
-			git.WithRepoURL(gitSource.Url),
-			git.WithLocalPath(opts.LocalPath),
+		repo, err := git.CloneWithOpts(
+			append(
+				cloneOpts,
+				git.WithRepoURL(gitSource.Url),
+				git.WithLocalPath(opts.LocalPath),
+			)...,
 		)
 
 		if err != nil {
 			return err
 		}
+		// The branch is recorded with the commit it is resolved to.
+		if len(gitSource.Branch) != 0 {
+			gitSource.ResolvedCommit, err = git.HeadCommit(repo)
+			if err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/hashicorp/go-version"
+	"github.com/opencontainers/go-digest"
 	"kcl-lang.io/kpm/pkg/constants"
 	"kcl-lang.io/kpm/pkg/opt"
 	"kcl-lang.io/kpm/pkg/settings"
@@ -50,6 +51,59 @@ type Oci struct {
 	Reg  string `toml:"reg,omitempty"`
 	Repo string `toml:"repo,omitempty"`
 	Tag  string `toml:"oci_tag,omitempty"`
+	// The digest of the manifest pulled, recorded in kcl.mod.lock.
+	// If the source is pinned by the digest, the content pulled is verified against the digest.
+	Digest string `toml:"oci_digest,omitempty"`
+	// Pinned is true if the source is pinned by the digest in kcl.mod or in the source url,
+	// e.g. 'oci://ghcr.io/kcl-lang/helloworld@sha256:...' or 'oci_digest' in kcl.mod,
+	// rather than the digest resolved from the tag.
+	// It is not recorded in kcl.mod.lock, which only records the digest.
+	Pinned bool `toml:"-"`
+}
+
+// PinDigest pins the oci source to the digest of the manifest, e.g. 'sha256:...'.
+func (oci *Oci) PinDigest(dgst string) error {
+	if _, err := digest.Parse(dgst); err != nil {
+		return fmt.Errorf("invalid oci digest '%s': %w", dgst, err)
+	}
+	if oci.Pinned && oci.Digest != dgst {
+		return fmt.Errorf("the oci source is pinned to both '%s' and '%s'", oci.Digest, dgst)
+	}
+	oci.Digest = dgst
+	oci.Pinned = true
+	return nil
+}
+
+// IsPinned returns true if the oci source is pinned by the digest.
+// The source with only the digest, e.g. loaded from kcl.mod.lock without the tag, can only be pinned by the digest.
+func (oci *Oci) IsPinned() bool {
+	return oci != nil && len(oci.Digest) != 0 && (oci.Pinned || len(oci.Tag) == 0)
+}
+
+// Reference returns the reference to pull the oci source,
+// which is the digest if the source is pinned by the digest, otherwise the tag.
+func (oci *Oci) Reference() string {
+	if oci.IsPinned() {
+		return oci.Digest
+	}
+	return oci.Tag
+}
+
+// GetPinnedReference returns the reference the package of the oci source is stored under,
+// which is the digest in the format of 'sha256-<hex>' if the source is pinned by the digest, otherwise the tag.
+func (oci *Oci) GetPinnedReference() string {
+	if oci.IsPinned() {
+		return strings.ReplaceAll(oci.Digest, ":", "-")
+	}
+	return oci.Tag
+}
+
+// displayRef returns the oci source in the format of '<repo>:<tag>' or '<repo>@<digest>' in the messages.
+func (oci *Oci) displayRef(repo string) string {
+	if oci.IsPinned() {
+		return fmt.Sprintf("%s@%s", repo, oci.Digest)
+	}
+	return fmt.Sprintf("%s:%s", repo, oci.Tag)
 }
 
 // Git is the package source from git registry.
@@ -60,6 +114,8 @@ type Git struct {
 	Tag     string `toml:"git_tag,omitempty"`
 	Version string `toml:"version,omitempty"`
 	Package string `toml:"package,omitempty"`
+	// The commit the branch is resolved to, recorded in kcl.mod.lock.
+	ResolvedCommit string `toml:"resolved_commit,omitempty"`
 }
 
 func NewSourceFromStr(sourceStr string) (*Source, error) {
@@ -268,7 +324,7 @@ func (oci *Oci) ToFilePath() (string, error) {
 		Path: oci.Repo,
 	}
 
-	return filepath.Join(constants.OciScheme, ociUrl.Host, ociUrl.Path, oci.Tag), nil
+	return filepath.Join(constants.OciScheme, ociUrl.Host, ociUrl.Path, oci.GetPinnedReference()), nil
 }
 
 func (local *Local) ToFilePath() (string, error) {
@@ -354,6 +410,9 @@ func (oci *Oci) ToString() (string, error) {
 		Host:   oci.Reg,
 		Path:   oci.Repo,
 	}
+	if oci.IsPinned() {
+		ociUrl.Path = fmt.Sprintf("%s@%s", oci.Repo, oci.Digest)
+	}
 	q := ociUrl.Query()
 	if oci.Tag != "" {
 		q.Set(constants.Tag, oci.Tag)
@@ -410,7 +469,9 @@ func (source *Source) FromString(sourceStr string) error {
 		source.Git.FromString(sourceUrl.String())
 	} else if sourceUrl.Scheme == constants.OciScheme {
 		source.Oci = &Oci{}
-		source.Oci.FromString(sourceUrl.String())
+		if err := source.Oci.FromString(sourceUrl.String()); err != nil {
+			return err
+		}
 	} else if sourceUrl.Scheme == constants.DefaultOciScheme {
 		source.ModSpec = &ModSpec{}
 		source.ModSpec.FromString(sourceUrl.String())
@@ -465,6 +526,13 @@ func (oci *Oci) FromString(ociStr string) error {
 	oci.Reg = u.Host
 	oci.Repo = strings.TrimPrefix(u.Path, "/")
 	oci.Tag = u.Query().Get(constants.Tag)
+	// The oci source pinned by the digest, e.g. 'oci://ghcr.io/kcl-lang/helloworld@sha256:...'.
+	if repo, dgst, ok := strings.Cut(oci.Repo, "@"); ok {
+		oci.Repo = repo
+		if err := oci.PinDigest(dgst); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -506,6 +574,21 @@ func (ps *ModSpec) FromString(registryStr string) error {
 	return nil
 }
 
+// GetPinnedReference returns the reference the git source is checked out at.
+// The branch is referred to by the commit it is resolved to, if the commit is recorded in kcl.mod.lock.
+func (git *Git) GetPinnedReference() string {
+	if len(git.Tag) != 0 {
+		return git.Tag
+	}
+	if len(git.Commit) != 0 {
+		return git.Commit
+	}
+	if len(git.Branch) != 0 && len(git.ResolvedCommit) != 0 {
+		return git.ResolvedCommit
+	}
+	return git.Branch
+}
+
 // GetValidGitReference will get the valid git reference from git source.
 // Only one of branch, tag or commit is allowed.
 func (git *Git) GetValidGitReference() (string, error) {
@@ -609,12 +692,8 @@ func (g *Git) Hash() (string, error) {
 	packageFilename := filepath.Base(gitURL)
 	filenamePattern := "%s_%s"
 
-	if g.Tag != "" {
-		packageFilename = fmt.Sprintf(filenamePattern, packageFilename, g.Tag)
-	} else if g.Commit != "" {
-		packageFilename = fmt.Sprintf(filenamePattern, packageFilename, g.Commit)
-	} else if g.Branch != "" {
-		packageFilename = fmt.Sprintf(filenamePattern, packageFilename, g.Branch)
+	if ref := g.GetPinnedReference(); ref != "" {
+		packageFilename = fmt.Sprintf(filenamePattern, packageFilename, ref)
 	}
 
 	hash, err := utils.ShortHash(filepath.Dir(gitURL))
@@ -627,10 +706,10 @@ func (g *Git) Hash() (string, error) {
 
 func (o *Oci) Hash() (string, error) {
 	var packageFilename string
-	if o.Tag == "" {
+	if ref := o.GetPinnedReference(); ref == "" {
 		packageFilename = filepath.Base(o.Repo)
 	} else {
-		packageFilename = fmt.Sprintf("%s_%s", filepath.Base(o.Repo), o.Tag)
+		packageFilename = fmt.Sprintf("%s_%s", filepath.Base(o.Repo), ref)
 	}
 
 	hash, err := utils.ShortHash(utils.JoinPath(o.Reg, filepath.Dir(o.Repo)))
@@ -650,8 +729,8 @@ func (s *Source) LocalPath() string {
 	// this section should be replaced with the new storage structure instead of the cache path according to the <Cache Path>/<Package Name>.
 	//  https://github.com/kcl-lang/kpm/issues/384
 	var path string
-	if s.Oci != nil && len(s.Oci.Tag) != 0 {
-		path = fmt.Sprintf("%s_%s", filepath.Base(s.Oci.Repo), s.Oci.Tag)
+	if s.Oci != nil && len(s.Oci.GetPinnedReference()) != 0 {
+		path = fmt.Sprintf("%s_%s", filepath.Base(s.Oci.Repo), s.Oci.GetPinnedReference())
 	}
 
 	if s.Git != nil && len(s.Git.Tag) != 0 {
@@ -0,0 +1,68 @@
+package downloader
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+const testOciDigest = "sha256:3b2e0cbd1c4e2d8e6b2f1f6d4b2e9f5c1a7d8e3f4a5b6c7d8e9f0a1b2c3d4e5f"
+
+func TestOciSourceWithDigest(t *testing.T) {
+	source, err := NewSourceFromStr("oci://ghcr.io/kcl-lang/helloworld@" + testOciDigest)
+	assert.NilError(t, err)
+	assert.Equal(t, source.Oci.Reg, "ghcr.io")
+	assert.Equal(t, source.Oci.Repo, "kcl-lang/helloworld")
+	assert.Equal(t, source.Oci.Tag, "")
+	assert.Equal(t, source.Oci.Digest, testOciDigest)
+	assert.Equal(t, source.Oci.IsPinned(), true)
+	assert.Equal(t, source.Oci.Reference(), testOciDigest)
+	assert.Equal(t, source.Oci.GetPinnedReference(), "sha256-3b2e0cbd1c4e2d8e6b2f1f6d4b2e9f5c1a7d8e3f4a5b6c7d8e9f0a1b2c3d4e5f")
+
+	sourceStr, err := source.ToString()
+	assert.NilError(t, err)
+	assert.Equal(t, sourceStr, "oci://ghcr.io/kcl-lang/helloworld@"+testOciDigest)
+
+	hash, err := source.Hash()
+	assert.NilError(t, err)
+	assert.Assert(t, len(hash) != 0)
+	assert.Equal(t, source.LocalPath(), "helloworld_sha256-3b2e0cbd1c4e2d8e6b2f1f6d4b2e9f5c1a7d8e3f4a5b6c7d8e9f0a1b2c3d4e5f")
+
+	_, err = NewSourceFromStr("oci://ghcr.io/kcl-lang/helloworld@sha256:invalid")
+	assert.ErrorContains(t, err, "invalid oci digest")
+
+	// The digest resolved from the tag is not a pinned digest.
+	resolved := Oci{Reg: "ghcr.io", Repo: "kcl-lang/helloworld", Tag: "0.1.0", Digest: testOciDigest}
+	assert.Equal(t, resolved.IsPinned(), false)
+	assert.Equal(t, resolved.Reference(), "0.1.0")
+	assert.Equal(t, resolved.GetPinnedReference(), "0.1.0")
+}
+
+func TestOciSourceWithDigestToml(t *testing.T) {
+	source := Source{}
+	err := source.UnmarshalModTOML(map[string]interface{}{
+		"oci":        "oci://ghcr.io/kcl-lang/helloworld",
+		"tag":        "0.1.0",
+		"oci_digest": testOciDigest,
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, source.Oci.Tag, "0.1.0")
+	assert.Equal(t, source.Oci.IsPinned(), true)
+	assert.Equal(t, source.Oci.Reference(), testOciDigest)
+	assert.Equal(t, source.MarshalTOML(), `{ oci = "oci://ghcr.io/kcl-lang/helloworld", tag = "0.1.0", oci_digest = "`+testOciDigest+`" }`)
+
+	resolved := Source{Oci: &Oci{Reg: "ghcr.io", Repo: "kcl-lang/helloworld", Tag: "0.1.0", Digest: testOciDigest}}
+	assert.Equal(t, resolved.MarshalTOML(), `{ oci = "oci://ghcr.io/kcl-lang/helloworld", tag = "0.1.0" }`)
+
+	conflicted := Source{}
+	err = conflicted.UnmarshalModTOML(map[string]interface{}{
+		"oci":        "oci://ghcr.io/kcl-lang/helloworld@" + testOciDigest,
+		"oci_digest": "sha256:0000000000000000000000000000000000000000000000000000000000000000",
+	})
+	assert.ErrorContains(t, err, "is pinned to both")
+
+	pinned := source.WithVersion("0.1.1")
+	assert.Equal(t, pinned.Oci.Tag, "0.1.1")
+	assert.Equal(t, pinned.Oci.IsPinned(), false)
+	assert.Equal(t, source.Oci.IsPinned(), true)
+}
@@ -67,6 +67,27 @@ func (source *Source) MarshalTOML() string {
 	return sb.String()
 }
 
+// MarshalAliasedTOML will marshal the source of a dependency depended on under an alias,
+// the name of the package is written as 'package', e.g. '{ package = "k8s", version = "1.27" }'.
+func (source *Source) MarshalAliasedTOML() string {
+	var fields []string
+	if source.Oci != nil && len(source.Oci.Reg) != 0 && len(source.Oci.Repo) != 0 {
+		fields = append(fields, source.Oci.MarshalTOML())
+	}
+	if source.Local != nil && len(source.Local.Path) != 0 {
+		fields = append(fields, source.Local.MarshalTOML())
+	}
+	if source.ModSpec != nil {
+		if len(source.ModSpec.Name) != 0 {
+			fields = append(fields, fmt.Sprintf(GIT_PACKAGE, source.ModSpec.Name))
+		}
+		if len(source.ModSpec.Version) != 0 {
+			fields = append(fields, fmt.Sprintf(VERSION_PATTERN, source.ModSpec.Version))
+		}
+	}
+	return fmt.Sprintf(SOURCE_PATTERN, strings.Join(fields, SEPARATOR))
+}
+
 const GIT_URL_PATTERN = "git = \"%s\""
 const TAG_PATTERN = "tag = \"%s\""
 const GIT_COMMIT_PATTERN = "commit = \"%s\""
@@ -108,6 +129,8 @@ func (git *Git) MarshalTOML() string {
 }
 
 const OCI_URL_PATTERN = "oci = \"%s\""
+const OCI_DIGEST_PATTERN = "oci_digest = \"%s\""
+const OCI_DIGEST_FLAG = "oci_digest"
 
 func (oci *Oci) MarshalTOML() string {
 	var sb strings.Builder
@@ -117,6 +140,11 @@ func (oci *Oci) MarshalTOML() string {
 			sb.WriteString(SEPARATOR)
 			sb.WriteString(fmt.Sprintf(TAG_PATTERN, oci.Tag))
 		}
+		// Only the digest pinned is written into kcl.mod, the digest resolved from the tag is recorded in kcl.mod.lock.
+		if oci.IsPinned() {
+			sb.WriteString(SEPARATOR)
+			sb.WriteString(fmt.Sprintf(OCI_DIGEST_PATTERN, oci.Digest))
+		}
 	} else if len(oci.Reg) == 0 && len(oci.Repo) == 0 && len(oci.Tag) != 0 {
 		sb.WriteString(fmt.Sprintf(`"%s"`, oci.Tag))
 	}
@@ -168,6 +196,15 @@ func (source *Source) UnmarshalModTOML(data interface{}) error {
 			}
 			source.ModSpec = &pSpec
 		}
+
+		// The package depended on under an alias, e.g. 'k8s_old = { package = "k8s", version = "1.27" }'.
+		// For the git source, 'package' is the sub-package in the git repo.
+		if v, ok := meta[GIT_PACKAGE_FLAG].(string); ok && source.Git == nil {
+			if source.ModSpec == nil {
+				source.ModSpec = &ModSpec{}
+			}
+			source.ModSpec.Name = v
+		}
 	}
 
 	_, ok = data.(string)
@@ -239,6 +276,12 @@ func (oci *Oci) UnmarshalModTOML(data interface{}) error {
 		if v, ok := meta[TAG_FLAG].(string); ok {
 			oci.Tag = v
 		}
+
+		if v, ok := meta[OCI_DIGEST_FLAG].(string); ok {
+			if err := oci.PinDigest(v); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
@@ -0,0 +1,209 @@
+package downloader
+
+import (
+	"fmt"
+	"io"
+	"slices"
+
+	"kcl-lang.io/kpm/pkg/git"
+	"kcl-lang.io/kpm/pkg/oci"
+	"kcl-lang.io/kpm/pkg/reporter"
+	"kcl-lang.io/kpm/pkg/semver"
+	"kcl-lang.io/kpm/pkg/utils"
+	remoteauth "oras.land/oras-go/v2/registry/remote/auth"
+)
+
+// VersionRange returns the version range of the source,
+// returns empty string if the source is pinned to an exact version.
+func (source *Source) VersionRange() string {
+	if source == nil {
+		return ""
+	}
+	if source.ModSpec != nil && semver.IsVersionRange(source.ModSpec.Version) {
+		return source.ModSpec.Version
+	}
+	if source.Oci != nil && semver.IsVersionRange(source.Oci.Tag) {
+		return source.Oci.Tag
+	}
+	if source.Git != nil && semver.IsVersionRange(source.Git.Tag) {
+		return source.Git.Tag
+	}
+	return ""
+}
+
+// IsVersionRange returns true if the source is required by a version range rather than an exact version.
+func (source *Source) IsVersionRange() bool {
+	return source.VersionRange() != ""
+}
+
+// WithExactVersion returns a copy of the source with the version range replaced by the exact version.
+func (source *Source) WithExactVersion(version string) Source {
+	exact := Source{}
+	if source.ModSpec != nil {
+		modSpec := *source.ModSpec
+		if semver.IsVersionRange(modSpec.Version) {
+			modSpec.Version = version
+		}
+		exact.ModSpec = &modSpec
+	}
+	if source.Oci != nil {
+		ociSource := *source.Oci
+		if semver.IsVersionRange(ociSource.Tag) {
+			ociSource.Tag = version
+		}
+		exact.Oci = &ociSource
+	}
+	if source.Git != nil {
+		gitSource := *source.Git
+		if semver.IsVersionRange(gitSource.Tag) {
+			gitSource.Tag = version
+		}
+		exact.Git = &gitSource
+	}
+	if source.Local != nil {
+		local := *source.Local
+		exact.Local = &local
+	}
+	return exact
+}
+
+// ExactVersion returns the exact version of the source,
+// returns empty string if the source is required by a version range.
+func (source *Source) ExactVersion() string {
+	if source.IsVersionRange() {
+		return ""
+	}
+	if source.ModSpec != nil && source.ModSpec.Version != "" {
+		return source.ModSpec.Version
+	}
+	if source.Oci != nil {
+		return source.Oci.Tag
+	}
+	if source.Git != nil {
+		return source.Git.Tag
+	}
+	return ""
+}
+
+// WithVersion returns a copy of the source pinned to the version,
+// the branch or the commit of the git source and the digest of the oci source are replaced by the tag of the version.
+func (source *Source) WithVersion(version string) Source {
+	pinned := source.WithExactVersion(version)
+	if pinned.ModSpec != nil {
+		pinned.ModSpec.Version = version
+	}
+	if pinned.Oci != nil {
+		pinned.Oci.Tag = version
+		pinned.Oci.Digest = ""
+		pinned.Oci.Pinned = false
+	}
+	if pinned.Git != nil {
+		pinned.Git.Tag = version
+		pinned.Git.Branch = ""
+		pinned.Git.Commit = ""
+	}
+	return pinned
+}
+
+// ResolveVersionRange will select the highest version matching the version range of the source
+// from the tags in the oci registry or the remote git repository,
+// and return a copy of the source with the exact version.
+// If the source is not required by a version range, the source is returned as it is.
+func ResolveVersionRange(source Source, options ...Option) (*Source, error) {
+	versionRange := source.VersionRange()
+	if versionRange == "" {
+		return &source, nil
+	}
+
+	opts := NewDownloadOptions(options...)
+
+	var tags []string
+	var err error
+	// The versions yanked by the publisher are only marked in the oci registry.
+	var isYanked func(version string) bool
+	if source.Oci != nil {
+		var cred *remoteauth.Credential
+		if opts.credsClient != nil {
+			cred, err = opts.credsClient.Credential(source.Oci.Reg)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			cred = &remoteauth.Credential{}
+		}
+
+		ociCli, err := oci.NewOciClientWithOpts(
+			oci.WithCredential(cred),
+			oci.WithRepoPath(utils.JoinPath(source.Oci.Reg, source.Oci.Repo)),
+			oci.WithSettings(&opts.Settings),
+			oci.WithInsecureSkipTLSverify(opts.InsecureSkipTLSverify),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		tags, err = ociCli.Tags()
+		if err != nil {
+			return nil, err
+		}
+		isYanked = func(version string) bool {
+			return yankedVersion(ociCli, version, opts.LogWriter)
+		}
+	} else if source.Git != nil {
+		tags, err = git.GetAllRemoteTags(source.Git.Url)
+		if err != nil {
+			return nil, reporter.NewErrorEvent(
+				reporter.FailedGetPackageVersions,
+				err,
+				fmt.Sprintf("failed to get the tags of '%s'", source.Git.Url),
+			)
+		}
+	} else {
+		return nil, fmt.Errorf("version range '%s' is only supported for oci and git sources", versionRange)
+	}
+
+	version, err := latestVersionNotYanked(tags, versionRange, isYanked)
+	if err != nil {
+		return nil, err
+	}
+
+	reporter.ReportMsgTo(
+		fmt.Sprintf("the version '%s' is selected for '%s'", version, versionRange),
+		opts.LogWriter,
+	)
+
+	exact := source.WithExactVersion(version)
+	return &exact, nil
+}
+
+// latestVersionNotYanked will select the highest version matching the version range from the tags,
+// the versions yanked by the publisher are skipped.
+func latestVersionNotYanked(tags []string, versionRange string, isYanked func(version string) bool) (string, error) {
+	for {
+		version, err := semver.LatestVersionInRange(tags, versionRange)
+		if err != nil {
+			return "", err
+		}
+		if isYanked == nil || !isYanked(version) {
+			return version, nil
+		}
+		tags = slices.DeleteFunc(slices.Clone(tags), func(tag string) bool {
+			return tag == version
+		})
+	}
+}
+
+// yankedVersion returns true if the version in the oci registry is yanked by the publisher.
+// The status of the version is advisory, so the version is not skipped if the status is not available.
+func yankedVersion(ociCli *oci.OciClient, version string, logWriter io.Writer) bool {
+	status, err := ociCli.FetchVersionStatus(version)
+	if err != nil {
+		reporter.ReportMsgTo(fmt.Sprintf("warning: failed to get the status of the version '%s': %v", version, err), logWriter)
+		return false
+	}
+	if status.IsYanked() {
+		reporter.ReportMsgTo(fmt.Sprintf("the version '%s' is skipped, it is %s", version, status.Describe()), logWriter)
+		return true
+	}
+	return false
+}
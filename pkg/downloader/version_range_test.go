@@ -0,0 +1,94 @@
+package downloader
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestSourceWithExactVersion(t *testing.T) {
+	source := Source{
+		ModSpec: &ModSpec{
+			Name:    "k8s",
+			Version: "^1.28",
+		},
+		Oci: &Oci{
+			Reg:  "ghcr.io",
+			Repo: "kcl-lang/k8s",
+			Tag:  "^1.28",
+		},
+	}
+	assert.Equal(t, source.IsVersionRange(), true)
+	assert.Equal(t, source.VersionRange(), "^1.28")
+	assert.Equal(t, source.ExactVersion(), "")
+
+	exact := source.WithExactVersion("1.30")
+	assert.Equal(t, exact.IsVersionRange(), false)
+	assert.Equal(t, exact.ExactVersion(), "1.30")
+	assert.Equal(t, exact.ModSpec.Version, "1.30")
+	assert.Equal(t, exact.Oci.Tag, "1.30")
+	// The version range in the original source is not changed.
+	assert.Equal(t, source.ModSpec.Version, "^1.28")
+	assert.Equal(t, source.Oci.Tag, "^1.28")
+
+	gitSource := Source{
+		Git: &Git{
+			Url: "https://github.com/kcl-lang/flask-demo-kcl-manifests.git",
+			Tag: "~0.1",
+		},
+	}
+	assert.Equal(t, gitSource.VersionRange(), "~0.1")
+	assert.Equal(t, gitSource.WithExactVersion("v0.1.3").Git.Tag, "v0.1.3")
+}
+
+func TestSourceWithVersion(t *testing.T) {
+	source := Source{
+		ModSpec: &ModSpec{
+			Name:    "helloworld",
+			Version: "0.1.0",
+		},
+		Oci: &Oci{
+			Reg:  "ghcr.io",
+			Repo: "kcl-lang/helloworld",
+			Tag:  "0.1.0",
+		},
+	}
+	pinned := source.WithVersion("0.1.1")
+	assert.Equal(t, pinned.ModSpec.Version, "0.1.1")
+	assert.Equal(t, pinned.Oci.Tag, "0.1.1")
+	assert.Equal(t, source.ModSpec.Version, "0.1.0")
+	assert.Equal(t, source.Oci.Tag, "0.1.0")
+
+	gitSource := Source{
+		Git: &Git{
+			Url:    "https://github.com/kcl-lang/flask-demo-kcl-manifests.git",
+			Branch: "main",
+		},
+	}
+	pinned = gitSource.WithVersion("v0.1.0")
+	assert.Equal(t, pinned.Git.Tag, "v0.1.0")
+	assert.Equal(t, pinned.Git.Branch, "")
+	assert.Equal(t, gitSource.Git.Branch, "main")
+}
+
+func TestLatestVersionNotYanked(t *testing.T) {
+	tags := []string{"1.28.0", "1.28.1", "1.28.2", "1.29.0"}
+	yanked := map[string]bool{"1.28.2": true}
+	isYanked := func(version string) bool {
+		return yanked[version]
+	}
+
+	version, err := latestVersionNotYanked(tags, "^1.28", nil)
+	assert.NilError(t, err)
+	assert.Equal(t, version, "1.29.0")
+	version, err = latestVersionNotYanked(tags, "~1.28", isYanked)
+	assert.NilError(t, err)
+	assert.Equal(t, version, "1.28.1")
+	// The tags are not changed by skipping the versions yanked.
+	assert.Equal(t, len(tags), 4)
+
+	yanked["1.28.0"] = true
+	yanked["1.28.1"] = true
+	_, err = latestVersionNotYanked(tags, "~1.28", isYanked)
+	assert.ErrorContains(t, err, "no version matches")
+}
@@ -24,6 +24,12 @@ var InvalidAddOptionsInvalidOciRef = errors.New("invalid 'kpm add' argument, you
 var InvalidAddOptionsInvalidOciReg = errors.New("invalid 'kpm add' argument, you must provide a Reg for the package.")
 var InvalidAddOptionsInvalidOciRepo = errors.New("invalid 'kpm add' argument, you must provide a Repo for the package.")
 
+// Invalid 'kpm remove'
+var InvalidRemoveOptions = errors.New("invalid 'kpm remove' argument, you must provide the names of the dependencies to be removed.")
+
+// Invalid 'kpm why'
+var InvalidWhyOptions = errors.New("invalid 'kpm why' argument, you must provide the name of exactly one dependency.")
+
 // Invalid 'kpm update'
 var MultipleSources = errors.New("multiple sources found, there must be a single source.")
 
@@ -7,12 +7,15 @@ import (
 	"io"
 	"net/http"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/hashicorp/go-getter"
 	giturl "github.com/kubescape/go-git-url"
 )
@@ -303,6 +306,28 @@ func GetAllGithubReleases(url string) ([]string, error) {
 	return releaseTags, nil
 }
 
+// GetAllRemoteTags will list all the tags of the remote git repository without cloning it.
+func GetAllRemoteTags(url string) ([]string, error) {
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: "origin",
+		URLs: []string{url},
+	})
+
+	refs, err := remote.List(&git.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []string
+	for _, ref := range refs {
+		if ref.Name().IsTag() {
+			tags = append(tags, ref.Name().Short())
+		}
+	}
+
+	return tags, nil
+}
+
 // IsGitBareRepo checks if a directory is a bare git repository
 func IsGitBareRepo(dir string) bool {
 	cmd := exec.Command("git", "-C", dir, "rev-parse", "--is-bare-repository")
@@ -312,3 +337,96 @@ func IsGitBareRepo(dir string) bool {
 	}
 	return strings.TrimSpace(string(output)) == "true"
 }
+
+// HeadCommit returns the hash of the commit checked out in the git repository.
+func HeadCommit(repo *git.Repository) (string, error) {
+	if repo == nil {
+		return "", errors.New("git repository is nil")
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", err
+	}
+	return head.Hash().String(), nil
+}
+
+// SourceOf returns the url of the remote 'origin' and the commit checked out
+// of the git repository containing the directory 'dir'.
+// The url is empty if the repository has no remote 'origin'.
+func SourceOf(dir string) (string, string, error) {
+	repo, err := git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return "", "", err
+	}
+	commit, err := HeadCommit(repo)
+	if err != nil {
+		return "", "", err
+	}
+	remote, err := repo.Remote(git.DefaultRemoteName)
+	if err != nil {
+		if errors.Is(err, git.ErrRemoteNotFound) {
+			return "", commit, nil
+		}
+		return "", "", err
+	}
+	if urls := remote.Config().URLs; len(urls) != 0 {
+		return urls[0], commit, nil
+	}
+	return "", commit, nil
+}
+
+// IsDirty returns true if the files in the directory 'dir' of the git repository
+// are modified or untracked, compared to the commit checked out.
+// The untracked package tars, e.g. the tar generated by 'kpm push', are ignored.
+func IsDirty(dir string) (bool, error) {
+	repo, err := git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return false, err
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return false, err
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		return false, err
+	}
+
+	prefix, err := relPathInWorktree(worktree.Filesystem.Root(), dir)
+	if err != nil {
+		return false, err
+	}
+	for path, fileStatus := range status {
+		if prefix != "." && path != prefix && !strings.HasPrefix(path, prefix+"/") {
+			continue
+		}
+		if fileStatus.Worktree == git.Untracked && strings.HasSuffix(path, ".tar") {
+			continue
+		}
+		if fileStatus.Staging != git.Unmodified || fileStatus.Worktree != git.Unmodified {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// relPathInWorktree returns the slash path of 'dir' relative to the root of the worktree.
+func relPathInWorktree(root, dir string) (string, error) {
+	root, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return "", err
+	}
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	dir, err = filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(root, dir)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
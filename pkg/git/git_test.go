@@ -6,8 +6,12 @@ import (
 	"os/exec"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"gotest.tools/v3/assert"
 )
 
@@ -231,3 +235,113 @@ func TestCheckoutFromBare(t *testing.T) {
 	_, err = repo.CommitObject(plumbing.NewHash(commitSHA))
 	assert.NilError(t, err, "Expected commit to exist in the repository")
 }
+
+func TestHeadCommit(t *testing.T) {
+	repoPath := t.TempDir()
+	repo, err := git.PlainInit(repoPath, false)
+	assert.NilError(t, err)
+
+	err = os.WriteFile(filepath.Join(repoPath, "main.k"), []byte("a = 1"), 0644)
+	assert.NilError(t, err)
+	worktree, err := repo.Worktree()
+	assert.NilError(t, err)
+	_, err = worktree.Add("main.k")
+	assert.NilError(t, err)
+	hash, err := worktree.Commit("init", &git.CommitOptions{
+		Author: &object.Signature{Name: "kpm", Email: "kpm@kcl-lang.io", When: time.Now()},
+	})
+	assert.NilError(t, err)
+
+	commit, err := HeadCommit(repo)
+	assert.NilError(t, err)
+	assert.Equal(t, commit, hash.String())
+
+	_, err = HeadCommit(nil)
+	assert.Error(t, err, "git repository is nil")
+}
+
+func TestSourceOf(t *testing.T) {
+	repoPath := t.TempDir()
+	repo, err := git.PlainInit(repoPath, false)
+	assert.NilError(t, err)
+
+	subDir := filepath.Join(repoPath, "sub")
+	err = os.MkdirAll(subDir, 0755)
+	assert.NilError(t, err)
+	err = os.WriteFile(filepath.Join(subDir, "main.k"), []byte("a = 1"), 0644)
+	assert.NilError(t, err)
+	worktree, err := repo.Worktree()
+	assert.NilError(t, err)
+	_, err = worktree.Add("sub/main.k")
+	assert.NilError(t, err)
+	hash, err := worktree.Commit("init", &git.CommitOptions{
+		Author: &object.Signature{Name: "kpm", Email: "kpm@kcl-lang.io", When: time.Now()},
+	})
+	assert.NilError(t, err)
+
+	// The repository without the remote 'origin'.
+	url, commit, err := SourceOf(subDir)
+	assert.NilError(t, err)
+	assert.Equal(t, url, "")
+	assert.Equal(t, commit, hash.String())
+
+	_, err = repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{"https://github.com/kcl-lang/modules"}})
+	assert.NilError(t, err)
+	url, commit, err = SourceOf(subDir)
+	assert.NilError(t, err)
+	assert.Equal(t, url, "https://github.com/kcl-lang/modules")
+	assert.Equal(t, commit, hash.String())
+
+	_, _, err = SourceOf(t.TempDir())
+	assert.Error(t, err, "repository does not exist")
+}
+
+func TestIsDirty(t *testing.T) {
+	repoPath := t.TempDir()
+	repo, err := git.PlainInit(repoPath, false)
+	assert.NilError(t, err)
+
+	subDir := filepath.Join(repoPath, "sub")
+	otherDir := filepath.Join(repoPath, "other")
+	assert.NilError(t, os.MkdirAll(subDir, 0755))
+	assert.NilError(t, os.MkdirAll(otherDir, 0755))
+	assert.NilError(t, os.WriteFile(filepath.Join(subDir, "main.k"), []byte("a = 1"), 0644))
+	worktree, err := repo.Worktree()
+	assert.NilError(t, err)
+	_, err = worktree.Add("sub/main.k")
+	assert.NilError(t, err)
+	_, err = worktree.Commit("init", &git.CommitOptions{
+		Author: &object.Signature{Name: "kpm", Email: "kpm@kcl-lang.io", When: time.Now()},
+	})
+	assert.NilError(t, err)
+
+	dirty, err := IsDirty(subDir)
+	assert.NilError(t, err)
+	assert.Equal(t, dirty, false)
+
+	// The package tar and the files out of the directory are ignored.
+	assert.NilError(t, os.WriteFile(filepath.Join(subDir, "sub_0.0.1.tar"), []byte("tar"), 0644))
+	assert.NilError(t, os.WriteFile(filepath.Join(otherDir, "main.k"), []byte("b = 1"), 0644))
+	dirty, err = IsDirty(subDir)
+	assert.NilError(t, err)
+	assert.Equal(t, dirty, false)
+	dirty, err = IsDirty(repoPath)
+	assert.NilError(t, err)
+	assert.Equal(t, dirty, true)
+
+	// The untracked file in the directory.
+	assert.NilError(t, os.WriteFile(filepath.Join(subDir, "new.k"), []byte("c = 1"), 0644))
+	dirty, err = IsDirty(subDir)
+	assert.NilError(t, err)
+	assert.Equal(t, dirty, true)
+	assert.NilError(t, os.Remove(filepath.Join(subDir, "new.k")))
+
+	// The modified file in the directory.
+	assert.NilError(t, os.WriteFile(filepath.Join(subDir, "main.k"), []byte("a = 2"), 0644))
+	dirty, err = IsDirty(subDir)
+	assert.NilError(t, err)
+	assert.Equal(t, dirty, true)
+
+	_, err = IsDirty(t.TempDir())
+	assert.Error(t, err, "repository does not exist")
+}
@@ -0,0 +1,111 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/opencontainers/go-digest"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// OciRegistry is an in-memory oci registry serving the manifests and the blobs added to it,
+// to read the packages published in the different layouts without starting a docker registry.
+type OciRegistry struct {
+	server *httptest.Server
+	// manifests are indexed by '<repo>:<tag>' and '<repo>@<digest>'.
+	manifests map[string][]byte
+	// blobs are indexed by '<repo>@<digest>'.
+	blobs map[string][]byte
+}
+
+// NewOciRegistry starts an in-memory oci registry, which should be closed by 'Close'.
+func NewOciRegistry() *OciRegistry {
+	registry := &OciRegistry{
+		manifests: map[string][]byte{},
+		blobs:     map[string][]byte{},
+	}
+	registry.server = httptest.NewServer(http.HandlerFunc(registry.serve))
+	return registry
+}
+
+// Host returns the host of the registry, e.g. 'localhost:5001', which is accessed by the plain http by default.
+func (r *OciRegistry) Host() string {
+	u, _ := url.Parse(r.server.URL)
+	return "localhost:" + u.Port()
+}
+
+// Close shuts down the registry.
+func (r *OciRegistry) Close() {
+	r.server.Close()
+}
+
+// AddPackage adds the version 'tag' of the package in 'repo' with the config blob in 'configMediaType'
+// and the package tar in the layer, and returns the content of the manifest added.
+// The layer is titled '<name>_<tag>.tar' to be pulled into a file as the package pushed by kpm.
+func (r *OciRegistry) AddPackage(repo, tag, configMediaType string, config, tar []byte, annotations map[string]string) ([]byte, error) {
+	layer := r.addBlob(repo, "application/vnd.oci.image.layer.v1.tar", tar)
+	layer.Annotations = map[string]string{v1.AnnotationTitle: fmt.Sprintf("%s_%s.tar", path.Base(repo), tag)}
+	manifest := v1.Manifest{
+		MediaType:   v1.MediaTypeImageManifest,
+		Config:      r.addBlob(repo, configMediaType, config),
+		Layers:      []v1.Descriptor{layer},
+		Annotations: annotations,
+	}
+	manifest.SchemaVersion = 2
+	content, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+	r.manifests[repo+":"+tag] = content
+	r.manifests[repo+"@"+digest.FromBytes(content).String()] = content
+	return content, nil
+}
+
+func (r *OciRegistry) addBlob(repo, mediaType string, content []byte) v1.Descriptor {
+	desc := v1.Descriptor{
+		MediaType: mediaType,
+		Digest:    digest.FromBytes(content),
+		Size:      int64(len(content)),
+	}
+	r.blobs[repo+"@"+desc.Digest.String()] = content
+	return desc
+}
+
+// serve serves the apis to get the manifests and the blobs, '/v2/<repo>/manifests/<reference>' and '/v2/<repo>/blobs/<digest>'.
+func (r *OciRegistry) serve(w http.ResponseWriter, req *http.Request) {
+	path := strings.TrimPrefix(req.URL.Path, "/v2/")
+	if path == "" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	var content []byte
+	var ok bool
+	mediaType := "application/octet-stream"
+	if repo, ref, found := strings.Cut(path, "/manifests/"); found {
+		if _, err := digest.Parse(ref); err == nil {
+			content, ok = r.manifests[repo+"@"+ref]
+		} else {
+			content, ok = r.manifests[repo+":"+ref]
+		}
+		mediaType = v1.MediaTypeImageManifest
+	} else if repo, ref, found := strings.Cut(path, "/blobs/"); found {
+		content, ok = r.blobs[repo+"@"+ref]
+	}
+	if !ok {
+		http.Error(w, fmt.Sprintf(`{"errors":[{"code":"NOT_FOUND","message":"'%s' is not found"}]}`, path), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", mediaType)
+	w.Header().Set("Docker-Content-Digest", digest.FromBytes(content).String())
+	w.Header().Set("Content-Length", fmt.Sprint(len(content)))
+	if req.Method != http.MethodHead {
+		_, _ = w.Write(content)
+	}
+}
@@ -116,7 +116,8 @@ func (r ReqsGraph) Upgrade(m module.Version) (module.Version, error) {
 			Deps: mpp,
 		}
 		lockDeps := pkg.Dependencies{
-			Deps: orderedmap.NewOrderedMap[string, pkg.Dependency](),
+			Deps:     orderedmap.NewOrderedMap[string, pkg.Dependency](),
+			Replaces: r.KpmPkg.ModFile.Replaces,
 		}
 		_, err = r.KpmClient.DownloadDeps(&deps, &lockDeps, r.Graph, r.KpmPkg.HomePath, module.Version{})
 		if err != nil {
@@ -180,7 +181,8 @@ func (r ReqsGraph) Previous(m module.Version) (module.Version, error) {
 			Deps: mppDeps,
 		}
 		lockDeps := pkg.Dependencies{
-			Deps: orderedmap.NewOrderedMap[string, pkg.Dependency](),
+			Deps:     orderedmap.NewOrderedMap[string, pkg.Dependency](),
+			Replaces: r.KpmPkg.ModFile.Replaces,
 		}
 		_, err = r.KpmClient.DownloadDeps(&deps, &lockDeps, r.Graph, r.KpmPkg.HomePath, module.Version{})
 		if err != nil {
@@ -203,31 +205,52 @@ func (r ReqsGraph) Required(m module.Version) ([]module.Version, error) {
 }
 
 // UpdateBuildList decides whether to upgrade or downgrade based on modulesToUpgrade and modulesToDowngrade.
-// if modulesToUpgrade is empty, upgrade all dependencies. if modulesToUpgrade is not empty, upgrade the dependencies.
-// if modulesToDowngrade is not empty, downgrade the dependencies.
-// if modulesToUpgrade and modulesToDowngrade are both empty, first apply upgrade operation and
-// then downgrade the build list returned from previous operation.
+// if modulesToUpgrade and modulesToDowngrade are both empty, upgrade all dependencies.
+// if modulesToUpgrade is not empty, upgrade the dependencies.
+// if modulesToDowngrade is not empty, downgrade the dependencies in the build list
+// returned from the upgrade operation, or in the current build list if there is nothing to upgrade.
 func UpdateBuildList(target module.Version, modulesToUpgrade []module.Version, modulesToDowngrade []module.Version, reqs *ReqsGraph) ([]module.Version, error) {
 	var (
 		UpdBuildLists []module.Version
 		err           error
 	)
 
-	if len(modulesToUpgrade) == 0 {
-		UpdBuildLists, err = mvs.UpgradeAll(target, reqs)
-	} else {
-		UpdBuildLists, err = mvs.Upgrade(target, reqs, modulesToUpgrade...)
+	if len(modulesToUpgrade) == 0 && len(modulesToDowngrade) == 0 {
+		return mvs.UpgradeAll(target, reqs)
 	}
-	if err != nil {
-		return []module.Version{}, err
+
+	if len(modulesToUpgrade) != 0 {
+		UpdBuildLists, err = mvs.Upgrade(target, reqs, modulesToUpgrade...)
+		if err != nil {
+			return []module.Version{}, err
+		}
 	}
 
 	if len(modulesToDowngrade) != 0 {
-		UpdBuildLists, err = mvs.Downgrade(target, reqs, modulesToDowngrade...)
-	}
-	if err != nil {
-		return []module.Version{}, err
+		var downgradeReqs mvs.DowngradeReqs = reqs
+		if len(UpdBuildLists) != 0 {
+			downgradeReqs = upgradedReqs{reqs, target, UpdBuildLists[1:]}
+		}
+		UpdBuildLists, err = mvs.Downgrade(target, downgradeReqs, modulesToDowngrade...)
+		if err != nil {
+			return []module.Version{}, err
+		}
 	}
 
 	return UpdBuildLists, nil
 }
+
+// upgradedReqs replaces the requirements of the target with the upgraded build list,
+// so that the downgrade operation is applied on the result of the upgrade operation.
+type upgradedReqs struct {
+	*ReqsGraph
+	target module.Version
+	list   []module.Version
+}
+
+func (r upgradedReqs) Required(m module.Version) ([]module.Version, error) {
+	if m == r.target {
+		return r.list, nil
+	}
+	return r.ReqsGraph.Required(m)
+}
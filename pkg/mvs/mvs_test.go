@@ -236,6 +236,43 @@ func testDowngrade(t *testing.T) {
 	assert.Equal(t, downgrade, expectedReqs)
 }
 
+func testUpdateBuildList(t *testing.T) {
+	pkg_path := getTestDir("test_with_external_deps")
+	assert.Equal(t, utils.DirExists(filepath.Join(pkg_path, "kcl.mod")), true)
+	kpmcli, err := client.NewKpmClient()
+	assert.Equal(t, err, nil)
+	kclPkg, err := kpmcli.LoadPkgFromPath(pkg_path)
+	assert.Equal(t, err, nil)
+
+	_, depGraph, err := kpmcli.InitGraphAndDownloadDeps(kclPkg)
+	assert.Equal(t, err, nil)
+
+	reqs := ReqsGraph{
+		depGraph,
+		kpmcli,
+		kclPkg,
+	}
+
+	target := module.Version{Path: kclPkg.GetPkgName(), Version: kclPkg.GetPkgVersion()}
+	upgradeList := []module.Version{
+		{Path: "helloworld", Version: "0.1.1"},
+	}
+	downgradeList := []module.Version{
+		{Path: "k8s", Version: "1.17"},
+	}
+	buildList, err := UpdateBuildList(target, upgradeList, downgradeList, &reqs)
+	assert.Equal(t, err, nil)
+
+	expectedReqs := []module.Version{
+		{Path: "test_with_external_deps", Version: "0.0.1"},
+		{Path: "argo-cd-order", Version: "0.1.2"},
+		{Path: "helloworld", Version: "0.1.1"},
+		{Path: "json_merge_patch", Version: "0.1.0"},
+		{Path: "k8s", Version: "1.17"},
+	}
+	assert.Equal(t, buildList, expectedReqs)
+}
+
 func TestMvsWithGloablLock(t *testing.T) {
 	test.RunTestWithGlobalLock(t, "TestMax", testMax)
 	test.RunTestWithGlobalLock(t, "TestRequired", testRequired)
@@ -245,4 +282,5 @@ func TestMvsWithGloablLock(t *testing.T) {
 	test.RunTestWithGlobalLock(t, "TestPrevious", testPrevious)
 	test.RunTestWithGlobalLock(t, "TestUpgradePreviousOfLocalDependency", testUpgradePreviousOfLocalDependency)
 	test.RunTestWithGlobalLock(t, "TestDowngrade", testDowngrade)
+	test.RunTestWithGlobalLock(t, "TestUpdateBuildList", testUpdateBuildList)
 }
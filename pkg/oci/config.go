@@ -0,0 +1,67 @@
+package oci
+
+import (
+	"encoding/json"
+	"fmt"
+
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+
+	"kcl-lang.io/kpm/pkg/constants"
+	"kcl-lang.io/kpm/pkg/reporter"
+)
+
+// PackageConfig is the config blob of the kcl package in the oci registry.
+// It holds kcl.mod and kcl.mod.lock of the package, so that the metadata and the dependencies
+// of the package can be read by fetching the small config blob instead of the package tar.
+type PackageConfig struct {
+	// ModFile is the content of kcl.mod.
+	ModFile string `json:"kcl.mod"`
+	// ModLockFile is the content of kcl.mod.lock, it is empty if the package has no kcl.mod.lock.
+	ModLockFile string `json:"kcl.mod.lock,omitempty"`
+}
+
+// ParsePackageConfig parses the content of the config blob of the kcl package.
+func ParsePackageConfig(content []byte) (*PackageConfig, error) {
+	var config PackageConfig
+	if err := json.Unmarshal(content, &config); err != nil {
+		return nil, fmt.Errorf("invalid kcl package config: %w", err)
+	}
+	return &config, nil
+}
+
+// FetchPackageConfig will return the config blob of the version 'tag' without downloading the package tar.
+// It returns nil if the version is published in the layout without the config blob,
+// and the metadata in the annotations of the manifest is used instead.
+func (ociClient *OciClient) FetchPackageConfig(tag string) (*PackageConfig, error) {
+	desc, manifestContent, err := oras.FetchBytes(*ociClient.ctx, ociClient.repo, tag, oras.DefaultFetchBytesOptions)
+	if err != nil {
+		return nil, reporter.NewErrorEvent(
+			reporter.FailedGetPkg,
+			err,
+			fmt.Sprintf("failed to find the version '%s' of '%s'", tag, ociClient.repoRef()),
+		)
+	}
+	if desc.MediaType != v1.MediaTypeImageManifest {
+		return nil, nil
+	}
+
+	var manifest v1.Manifest
+	if err := json.Unmarshal(manifestContent, &manifest); err != nil {
+		return nil, reporter.NewErrorEvent(reporter.FailedGetPkg, err, fmt.Sprintf("failed to parse the manifest of '%s:%s'", ociClient.repoRef(), tag))
+	}
+	if manifest.Config.MediaType != constants.DEFAULT_KCL_OCI_CONFIG_MEDIA_TYPE {
+		return nil, nil
+	}
+
+	configContent, err := content.FetchAll(*ociClient.ctx, ociClient.repo, manifest.Config)
+	if err != nil {
+		return nil, reporter.NewErrorEvent(reporter.FailedGetPkg, err, fmt.Sprintf("failed to fetch the config of '%s:%s'", ociClient.repoRef(), tag))
+	}
+	config, err := ParsePackageConfig(configContent)
+	if err != nil {
+		return nil, reporter.NewErrorEvent(reporter.FailedGetPkg, err, fmt.Sprintf("failed to parse the config of '%s:%s'", ociClient.repoRef(), tag))
+	}
+	return config, nil
+}
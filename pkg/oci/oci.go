@@ -17,12 +17,14 @@ import (
 
 	"github.com/containers/image/v5/docker"
 	"github.com/containers/image/v5/types"
+	"github.com/opencontainers/go-digest"
 	v1 "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/thoas/go-funk"
 	"oras.land/oras-go/pkg/auth"
 	dockerauth "oras.land/oras-go/pkg/auth/docker"
 	remoteauth "oras.land/oras-go/v2/registry/remote/auth"
 
+	"kcl-lang.io/kpm/pkg/constants"
 	"kcl-lang.io/kpm/pkg/opt"
 	"kcl-lang.io/kpm/pkg/reporter"
 	"kcl-lang.io/kpm/pkg/semver"
@@ -175,9 +177,31 @@ func NewOciClientWithOpts(opts ...OciClientOption) (*OciClient, error) {
 		}
 	}
 
+	ctx := context.Background()
+	client.repo.Client = newAuthClient(client.repo.Reference.Host(), client.cred, client.insecureSkipTLSverify)
+
+	// If the plain http is not specified
+	if client.isPlainHttp == nil {
+		client.repo.PlainHTTP = defaultPlainHttp(client.repo.Reference.String(), client.settings)
+	}
+
+	client.ctx = &ctx
+	client.PullOciOptions = &PullOciOptions{
+		CopyOpts: &oras.CopyOptions{
+			CopyGraphOptions: oras.CopyGraphOptions{
+				MaxMetadataBytes: DEFAULT_LIMIT_STORE_SIZE, // default is 64 MiB
+			},
+		},
+	}
+
+	return client, nil
+}
+
+// newAuthClient creates the client to access the oci registry 'hostName' with the credential.
+func newAuthClient(hostName string, cred *remoteauth.Credential, insecureSkipTLSverify bool) *remoteauth.Client {
 	customTransport := &http.Transport{
 		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: client.insecureSkipTLSverify,
+			InsecureSkipVerify: insecureSkipTLSverify,
 		},
 	}
 
@@ -185,43 +209,56 @@ func NewOciClientWithOpts(opts ...OciClientOption) (*OciClient, error) {
 		Transport: customTransport,
 	}
 
-	ctx := context.Background()
-	client.repo.Client = &remoteauth.Client{
+	return &remoteauth.Client{
 		Client:     customClient,
 		Cache:      remoteauth.DefaultCache,
-		Credential: remoteauth.StaticCredential(client.repo.Reference.Host(), *client.cred),
+		Credential: remoteauth.StaticCredential(hostName, *cred),
 	}
+}
 
-	// If the plain http is not specified
-	if client.isPlainHttp == nil {
-		// Set the default value of the plain http
-		registry := client.repo.Reference.String()
-		host, _, _ := net.SplitHostPort(registry)
-		if host == "localhost" || registry == "localhost" {
-			// not specified, defaults to plain http for localhost
-			client.repo.PlainHTTP = true
-		}
-
-		// If the plain http is specified in the settings file
-		// Override the default value of the plain http
-		if client.settings != nil {
-			isPlainHttp, force := client.settings.ForceOciPlainHttp()
-			if force {
-				client.repo.PlainHTTP = isPlainHttp
-			}
+// defaultPlainHttp returns whether the plain http is used to access the oci registry by default.
+func defaultPlainHttp(registry string, settings *settings.Settings) bool {
+	var plainHttp bool
+	// Set the default value of the plain http
+	host, _, _ := net.SplitHostPort(registry)
+	if host == "localhost" || registry == "localhost" {
+		// not specified, defaults to plain http for localhost
+		plainHttp = true
+	}
+
+	// If the plain http is specified in the settings file
+	// Override the default value of the plain http
+	if settings != nil {
+		isPlainHttp, force := settings.ForceOciPlainHttp()
+		if force {
+			plainHttp = isPlainHttp
 		}
 	}
+	return plainHttp
+}
 
-	client.ctx = &ctx
-	client.PullOciOptions = &PullOciOptions{
-		CopyOpts: &oras.CopyOptions{
-			CopyGraphOptions: oras.CopyGraphOptions{
-				MaxMetadataBytes: DEFAULT_LIMIT_STORE_SIZE, // default is 64 MiB
-			},
-		},
+// Repositories will return all the repos in the oci registry 'hostName' through the '_catalog' api.
+func Repositories(hostName string, cred *remoteauth.Credential, settings *settings.Settings, insecureSkipTLSverify bool) ([]string, error) {
+	registry, err := remote.NewRegistry(hostName)
+	if err != nil {
+		return nil, fmt.Errorf("invalid registry '%s': %w", hostName, err)
 	}
+	registry.Client = newAuthClient(hostName, cred, insecureSkipTLSverify)
+	registry.PlainHTTP = defaultPlainHttp(hostName, settings)
 
-	return client, nil
+	var allRepos []string
+	err = registry.Repositories(context.Background(), "", func(repos []string) error {
+		allRepos = append(allRepos, repos...)
+		return nil
+	})
+	if err != nil {
+		return nil, reporter.NewErrorEvent(
+			reporter.FailedGetPkg,
+			err,
+			fmt.Sprintf("failed to list the repos in '%s'", hostName),
+		)
+	}
+	return allRepos, nil
 }
 
 // NewOciClient will new an OciClient.
@@ -250,24 +287,57 @@ func NewOciClient(regName, repoName string, settings *settings.Settings) (*OciCl
 const DEFAULT_LIMIT_STORE_SIZE = 64 * 1024 * 1024
 
 // Pull will pull the oci artifacts from oci registry to local path.
+// The 'tag' can also be the digest of the manifest, e.g. 'sha256:...', and the content pulled is verified against it.
 func (ociClient *OciClient) Pull(localPath, tag string) error {
+	if _, err := digest.Parse(tag); err == nil {
+		return ociClient.PullByDigest(localPath, tag)
+	}
+	_, err := ociClient.PullWithDigest(localPath, tag)
+	return err
+}
+
+// PullWithDigest will pull the oci artifacts from oci registry to local path,
+// and return the digest of the manifest pulled, e.g. 'sha256:...'.
+func (ociClient *OciClient) PullWithDigest(localPath, tag string) (string, error) {
 	// Create a file store
 	fs, err := file.NewWithFallbackLimit(localPath, DEFAULT_LIMIT_STORE_SIZE)
 	if err != nil {
-		return reporter.NewErrorEvent(reporter.FailedCreateStorePath, err, "Failed to create store path ", localPath)
+		return "", reporter.NewErrorEvent(reporter.FailedCreateStorePath, err, "Failed to create store path ", localPath)
 	}
 	defer fs.Close()
+	// The package is pulled by the digest verified, if the signature is required by the signature policy.
+	srcRef, err := ociClient.verifyBeforePull(tag)
+	if err != nil {
+		return "", err
+	}
 	copyOpts := ociClient.PullOciOptions.CopyOpts
 	copyOpts.FindSuccessors = ociClient.PullOciOptions.Successors
-	_, err = oras.Copy(*ociClient.ctx, ociClient.repo, tag, fs, tag, *copyOpts)
+	desc, err := oras.Copy(*ociClient.ctx, ociClient.repo, srcRef, fs, tag, *copyOpts)
 	if err != nil {
-		return reporter.NewErrorEvent(
+		return "", reporter.NewErrorEvent(
 			reporter.FailedGetPkg,
 			err,
 			fmt.Sprintf("failed to get package with '%s' from '%s'", tag, ociClient.repo.Reference.String()),
 		)
 	}
 
+	return desc.Digest.String(), nil
+}
+
+// PullByDigest will pull the package pinned by the digest of the manifest to the local path,
+// and make sure the content pulled is the content of the digest.
+func (ociClient *OciClient) PullByDigest(localPath, dgst string) error {
+	pulled, err := ociClient.PullWithDigest(localPath, dgst)
+	if err != nil {
+		return err
+	}
+	if pulled != dgst {
+		return reporter.NewErrorEvent(
+			reporter.FailedGetPkg,
+			fmt.Errorf("the digest of the package pulled is '%s', but '%s' is expected", pulled, dgst),
+			fmt.Sprintf("failed to get package with '%s' from '%s'", dgst, ociClient.repo.Reference.String()),
+		)
+	}
 	return nil
 }
 
@@ -277,7 +347,8 @@ func (ociClient *OciClient) TheLatestTag() (string, error) {
 
 	err := ociClient.repo.Tags(*ociClient.ctx, "", func(tags []string) error {
 		var err error
-		tagSelected, err = semver.LatestVersion(tags)
+		// The tags of the referrers tag schema, e.g. the status of the versions, are not versions.
+		tagSelected, err = semver.LatestVersion(semver.FilterValidVersions(tags))
 		if err != nil {
 			return err
 		}
@@ -296,6 +367,26 @@ func (ociClient *OciClient) TheLatestTag() (string, error) {
 	return tagSelected, nil
 }
 
+// Tags will return all the tags of the repo.
+func (ociClient *OciClient) Tags() ([]string, error) {
+	var allTags []string
+
+	err := ociClient.repo.Tags(*ociClient.ctx, "", func(tags []string) error {
+		allTags = append(allTags, tags...)
+		return nil
+	})
+
+	if err != nil {
+		return nil, reporter.NewErrorEvent(
+			reporter.FailedGetPackageVersions,
+			err,
+			fmt.Sprintf("failed to get the tags of '%s'", ociClient.repo.Reference.String()),
+		)
+	}
+
+	return allTags, nil
+}
+
 // RepoIsNotExist will check if the error is caused by the repo not found.
 func RepoIsNotExist(err error) bool {
 	errRes, ok := err.(*errcode.ErrorResponse)
@@ -371,6 +462,15 @@ func (ociClient *OciClient) PushWithOciManifest(localPath, tag string, opts *opt
 		ManifestAnnotations: opts.Annotations,
 		Layers:              fileDescriptors,
 	}
+	// The config blob holding kcl.mod and kcl.mod.lock is pushed with the tar,
+	// so that the metadata of the package can be read without downloading the tar.
+	if len(opts.Config) != 0 {
+		configDescriptor, err := oras.PushBytes(*ociClient.ctx, fs, constants.DEFAULT_KCL_OCI_CONFIG_MEDIA_TYPE, opts.Config)
+		if err != nil {
+			return reporter.NewErrorEvent(reporter.FailedPush, err, fmt.Sprintf("failed to add the config of package in '%s'", localPath))
+		}
+		packOpts.ConfigDescriptor = &configDescriptor
+	}
 	manifestDescriptor, err := oras.PackManifest(*ociClient.ctx, fs, oras.PackManifestVersion1_1_RC4, DEFAULT_OCI_ARTIFACT_TYPE, packOpts)
 
 	if err != nil {
@@ -1,12 +1,17 @@
 package oci
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
 
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/stretchr/testify/assert"
+	remoteauth "oras.land/oras-go/v2/registry/remote/auth"
 
+	"kcl-lang.io/kpm/pkg/constants"
+	"kcl-lang.io/kpm/pkg/mock"
 	"kcl-lang.io/kpm/pkg/settings"
 	"kcl-lang.io/kpm/pkg/utils"
 )
@@ -76,3 +81,87 @@ func TestPull(t *testing.T) {
 		}
 	}
 }
+
+func TestVersionStatusAnnotations(t *testing.T) {
+	status := &VersionStatus{
+		Status:      VERSION_STATUS_DEPRECATED,
+		Message:     "the schema is broken",
+		Replacement: "0.1.3",
+	}
+	assert.Equal(t, status.Describe(), "deprecated: the schema is broken, use '0.1.3' instead")
+	assert.Equal(t, status.IsDeprecated(), true)
+	assert.Equal(t, status.IsYanked(), false)
+
+	annotations := status.Annotations()
+	annotations["org.opencontainers.image.created"] = "2024-01-01T00:00:00Z"
+	got := VersionStatusFromAnnotations(annotations)
+	assert.Equal(t, got.Status, VERSION_STATUS_DEPRECATED)
+	assert.Equal(t, got.Message, "the schema is broken")
+	assert.Equal(t, got.Replacement, "0.1.3")
+	assert.Equal(t, got.Created, "2024-01-01T00:00:00Z")
+
+	yanked := &VersionStatus{Status: VERSION_STATUS_YANKED}
+	assert.Equal(t, yanked.Describe(), "yanked")
+	assert.Equal(t, VersionStatusFromAnnotations(yanked.Annotations()).IsYanked(), true)
+
+	assert.Nil(t, VersionStatusFromAnnotations(map[string]string{}))
+	var none *VersionStatus
+	assert.Equal(t, none.IsYanked(), false)
+
+	// The statuses pushed in the same second are ordered by the fractional seconds.
+	earlier := &VersionStatus{Status: VERSION_STATUS_YANKED, Created: "2024-01-01T00:00:00.1Z"}
+	later := &VersionStatus{Status: VERSION_STATUS_ACTIVE, Created: "2024-01-01T00:00:00.12Z"}
+	assert.Equal(t, later.NewerThan(earlier), true)
+	assert.Equal(t, earlier.NewerThan(later), false)
+	assert.Equal(t, earlier.NewerThan(nil), true)
+	assert.Equal(t, VersionStatusFromAnnotations(later.Annotations()).Created, later.Created)
+}
+
+func TestVerifyBeforePullWithSignaturePolicy(t *testing.T) {
+	conf := settings.DefaultKpmConf()
+	conf.SignaturePolicies = []settings.SignaturePolicy{
+		{Scope: "localhost:1/signed", PublicKeys: []string{filepath.Join(t.TempDir(), "not_exist.pub")}},
+	}
+	kpmSettings := &settings.Settings{Conf: conf}
+
+	// The repo out of the scope of the signature policies is pulled by the tag without verification.
+	ociClient, err := NewOciClientWithOpts(WithCredential(&remoteauth.Credential{}), WithRepoPath("localhost:1/unsigned/helloworld"), WithSettings(kpmSettings))
+	assert.Nil(t, err)
+	ref, err := ociClient.verifyBeforePull("0.1.0")
+	assert.Nil(t, err)
+	assert.Equal(t, ref, "0.1.0")
+
+	// The repo in the scope requires the trusted keys.
+	ociClient, err = NewOciClientWithOpts(WithCredential(&remoteauth.Credential{}), WithRepoPath("localhost:1/signed/helloworld"), WithSettings(kpmSettings))
+	assert.Nil(t, err)
+	_, err = ociClient.verifyBeforePull("0.1.0")
+	assert.ErrorContains(t, err, "failed to load the trusted key for 'localhost:1/signed'")
+}
+
+func TestFetchPackageConfig(t *testing.T) {
+	registry := mock.NewOciRegistry()
+	defer registry.Close()
+
+	configContent, err := json.Marshal(PackageConfig{ModFile: "[package]\nname = \"helloworld\"\n"})
+	assert.Nil(t, err)
+	_, err = registry.AddPackage("test/helloworld", "0.2.0", constants.DEFAULT_KCL_OCI_CONFIG_MEDIA_TYPE, configContent, []byte("tar"), nil)
+	assert.Nil(t, err)
+	// The version published in the layout without the config blob.
+	_, err = registry.AddPackage("test/helloworld", "0.1.0", v1.MediaTypeImageConfig, []byte("{}"), []byte("tar"), nil)
+	assert.Nil(t, err)
+
+	ociClient, err := NewOciClientWithOpts(WithCredential(&remoteauth.Credential{}), WithRepoPath(registry.Host()+"/test/helloworld"))
+	assert.Nil(t, err)
+
+	config, err := ociClient.FetchPackageConfig("0.2.0")
+	assert.Nil(t, err)
+	assert.Equal(t, "[package]\nname = \"helloworld\"\n", config.ModFile)
+	assert.Equal(t, "", config.ModLockFile)
+
+	config, err = ociClient.FetchPackageConfig("0.1.0")
+	assert.Nil(t, err)
+	assert.Nil(t, config)
+
+	_, err = ociClient.FetchPackageConfig("0.0.1")
+	assert.ErrorContains(t, err, "failed to find the version '0.0.1'")
+}
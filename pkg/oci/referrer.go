@@ -0,0 +1,94 @@
+package oci
+
+import (
+	"encoding/json"
+	"fmt"
+
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+
+	"kcl-lang.io/kpm/pkg/reporter"
+)
+
+// PushReferrer will push the content as an artifact referring to the manifest of the version 'tag',
+// e.g. the SBOM or the provenance of the package.
+// It returns the descriptor of the manifest of the artifact pushed.
+func (ociClient *OciClient) PushReferrer(tag, artifactType, mediaType string, content []byte, annotations map[string]string) (v1.Descriptor, error) {
+	desc, err := ociClient.repo.Resolve(*ociClient.ctx, tag)
+	if err != nil {
+		return v1.Descriptor{}, reporter.NewErrorEvent(
+			reporter.FailedPush,
+			err,
+			fmt.Sprintf("failed to find the version '%s' of '%s'", tag, ociClient.repoRef()),
+		)
+	}
+
+	contentDesc, err := oras.PushBytes(*ociClient.ctx, ociClient.repo, mediaType, content)
+	if err != nil {
+		return v1.Descriptor{}, reporter.NewErrorEvent(reporter.FailedPush, err, fmt.Sprintf("failed to push '%s' to '%s'", artifactType, ociClient.repoRef()))
+	}
+
+	referrerDesc, err := oras.PackManifest(
+		*ociClient.ctx,
+		ociClient.repo,
+		oras.PackManifestVersion1_1,
+		artifactType,
+		oras.PackManifestOptions{
+			Subject:             &desc,
+			Layers:              []v1.Descriptor{contentDesc},
+			ManifestAnnotations: annotations,
+		},
+	)
+	if err != nil {
+		return v1.Descriptor{}, reporter.NewErrorEvent(reporter.FailedPush, err, fmt.Sprintf("failed to push '%s' to '%s'", artifactType, ociClient.repoRef()))
+	}
+
+	reporter.ReportMsgTo(fmt.Sprintf("attached [registry] %s to %s:%s", artifactType, ociClient.repoRef(), tag), ociClient.logWriter)
+	reporter.ReportMsgTo(fmt.Sprintf("digest: %s", referrerDesc.Digest), ociClient.logWriter)
+	return referrerDesc, nil
+}
+
+// FetchReferrerContents will return the contents of the first layers of the artifacts
+// in the type 'artifactType' referring to the manifest of the version 'tag'.
+func (ociClient *OciClient) FetchReferrerContents(tag, artifactType string) ([][]byte, error) {
+	desc, err := ociClient.repo.Resolve(*ociClient.ctx, tag)
+	if err != nil {
+		return nil, reporter.NewErrorEvent(
+			reporter.FailedGetPkg,
+			err,
+			fmt.Sprintf("failed to find the version '%s' of '%s'", tag, ociClient.repoRef()),
+		)
+	}
+
+	var contents [][]byte
+	err = ociClient.repo.Referrers(*ociClient.ctx, desc, artifactType, func(referrers []v1.Descriptor) error {
+		for _, referrer := range referrers {
+			manifestContent, err := content.FetchAll(*ociClient.ctx, ociClient.repo, referrer)
+			if err != nil {
+				return err
+			}
+			var manifest v1.Manifest
+			if err := json.Unmarshal(manifestContent, &manifest); err != nil {
+				return err
+			}
+			if len(manifest.Layers) == 0 {
+				continue
+			}
+			layerContent, err := content.FetchAll(*ociClient.ctx, ociClient.repo, manifest.Layers[0])
+			if err != nil {
+				return err
+			}
+			contents = append(contents, layerContent)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, reporter.NewErrorEvent(
+			reporter.FailedGetPkg,
+			err,
+			fmt.Sprintf("failed to get '%s' of '%s:%s'", artifactType, ociClient.repoRef(), tag),
+		)
+	}
+	return contents, nil
+}
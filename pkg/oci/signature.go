@@ -0,0 +1,185 @@
+package oci
+
+import (
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+
+	"kcl-lang.io/kpm/pkg/reporter"
+	"kcl-lang.io/kpm/pkg/signature"
+)
+
+// repoRef returns the oci repo without the tag, e.g. 'ghcr.io/kcl-lang/helloworld'.
+func (ociClient *OciClient) repoRef() string {
+	return fmt.Sprintf("%s/%s", ociClient.repo.Reference.Registry, ociClient.repo.Reference.Repository)
+}
+
+// PushSignature will sign the manifest of the version 'tag' by the private key,
+// and push the signature as an artifact referring to the manifest.
+func (ociClient *OciClient) PushSignature(tag string, signer crypto.Signer) error {
+	desc, err := ociClient.repo.Resolve(*ociClient.ctx, tag)
+	if err != nil {
+		return reporter.NewErrorEvent(
+			reporter.FailedSign,
+			err,
+			fmt.Sprintf("failed to find the version '%s' of '%s'", tag, ociClient.repoRef()),
+		)
+	}
+
+	payload, err := signature.NewPayload(ociClient.repoRef(), desc.Digest.String())
+	if err != nil {
+		return reporter.NewErrorEvent(reporter.FailedSign, err)
+	}
+	sig, err := signature.Sign(signer, payload)
+	if err != nil {
+		return reporter.NewErrorEvent(reporter.FailedSign, err)
+	}
+
+	payloadDesc, err := oras.PushBytes(*ociClient.ctx, ociClient.repo, signature.SIMPLE_SIGNING_MEDIA_TYPE, payload)
+	if err != nil {
+		return reporter.NewErrorEvent(reporter.FailedSign, err, fmt.Sprintf("failed to push the signature of '%s:%s'", ociClient.repoRef(), tag))
+	}
+	payloadDesc.Annotations = map[string]string{
+		signature.SIGNATURE_ANNOTATION: base64.StdEncoding.EncodeToString(sig),
+	}
+
+	sigDesc, err := oras.PackManifest(
+		*ociClient.ctx,
+		ociClient.repo,
+		oras.PackManifestVersion1_1,
+		signature.SIGNATURE_ARTIFACT_TYPE,
+		oras.PackManifestOptions{
+			Subject: &desc,
+			Layers:  []v1.Descriptor{payloadDesc},
+		},
+	)
+	if err != nil {
+		return reporter.NewErrorEvent(reporter.FailedSign, err, fmt.Sprintf("failed to push the signature of '%s:%s'", ociClient.repoRef(), tag))
+	}
+
+	reporter.ReportMsgTo(fmt.Sprintf("signed [registry] %s:%s", ociClient.repoRef(), tag), ociClient.logWriter)
+	reporter.ReportMsgTo(fmt.Sprintf("digest: %s", sigDesc.Digest), ociClient.logWriter)
+	return nil
+}
+
+// VerifySignature checks whether the manifest 'desc' is signed by one of the public keys.
+// The signatures are the artifacts referring to the manifest pushed by 'PushSignature' or cosign.
+func (ociClient *OciClient) VerifySignature(desc v1.Descriptor, publicKeys []crypto.PublicKey) error {
+	verified := false
+	var lastErr error
+	err := ociClient.repo.Referrers(*ociClient.ctx, desc, signature.SIGNATURE_ARTIFACT_TYPE, func(referrers []v1.Descriptor) error {
+		for _, referrer := range referrers {
+			if verified {
+				return nil
+			}
+			manifestContent, err := content.FetchAll(*ociClient.ctx, ociClient.repo, referrer)
+			if err != nil {
+				return err
+			}
+			var manifest v1.Manifest
+			if err := json.Unmarshal(manifestContent, &manifest); err != nil {
+				return err
+			}
+			for _, layer := range manifest.Layers {
+				if err := ociClient.verifySignatureLayer(layer, desc, publicKeys); err != nil {
+					lastErr = err
+					continue
+				}
+				verified = true
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return reporter.NewErrorEvent(
+			reporter.FailedVerifySignature,
+			err,
+			fmt.Sprintf("failed to get the signatures of '%s@%s'", ociClient.repoRef(), desc.Digest),
+		)
+	}
+
+	if !verified {
+		if lastErr == nil {
+			lastErr = errors.New("no signature is found")
+		}
+		return reporter.NewErrorEvent(
+			reporter.FailedVerifySignature,
+			lastErr,
+			fmt.Sprintf("'%s@%s' is not signed by the trusted keys", ociClient.repoRef(), desc.Digest),
+		)
+	}
+	return nil
+}
+
+// verifySignatureLayer verifies the simple signing payload in the layer and the signature in the annotation of the layer.
+func (ociClient *OciClient) verifySignatureLayer(layer v1.Descriptor, desc v1.Descriptor, publicKeys []crypto.PublicKey) error {
+	if layer.MediaType != signature.SIMPLE_SIGNING_MEDIA_TYPE {
+		return fmt.Errorf("unsupported signature media type '%s'", layer.MediaType)
+	}
+	sig, err := base64.StdEncoding.DecodeString(layer.Annotations[signature.SIGNATURE_ANNOTATION])
+	if err != nil || len(sig) == 0 {
+		return errors.New("invalid signature in the annotation")
+	}
+	payload, err := content.FetchAll(*ociClient.ctx, ociClient.repo, layer)
+	if err != nil {
+		return err
+	}
+	if err := signature.VerifyPayload(payload, ociClient.repoRef(), desc.Digest.String()); err != nil {
+		return err
+	}
+	for _, publicKey := range publicKeys {
+		if err = signature.Verify(publicKey, payload, sig); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// verifyBeforePull resolves the version 'tag' and verifies the signature of it,
+// if the repo requires the packages signed by the signature policy in 'kpm.json'.
+// It returns the reference to pull, which is the digest verified if the signature is required.
+func (ociClient *OciClient) verifyBeforePull(tag string) (string, error) {
+	if ociClient.settings == nil {
+		return tag, nil
+	}
+	policy := ociClient.settings.SignaturePolicyFor(ociClient.repo.Reference.Registry, ociClient.repo.Reference.Repository)
+	if policy == nil {
+		return tag, nil
+	}
+
+	var publicKeys []crypto.PublicKey
+	for _, keyPath := range policy.PublicKeys {
+		publicKey, err := signature.LoadPublicKey(keyPath)
+		if err != nil {
+			return "", reporter.NewErrorEvent(reporter.FailedVerifySignature, err, fmt.Sprintf("failed to load the trusted key for '%s'", policy.Scope))
+		}
+		publicKeys = append(publicKeys, publicKey)
+	}
+	if len(publicKeys) == 0 {
+		return "", reporter.NewErrorEvent(
+			reporter.FailedVerifySignature,
+			fmt.Errorf("no trusted key is set for '%s' in the signature policy", policy.Scope),
+		)
+	}
+
+	desc, err := ociClient.repo.Resolve(*ociClient.ctx, tag)
+	if err != nil {
+		return "", reporter.NewErrorEvent(
+			reporter.FailedGetPkg,
+			err,
+			fmt.Sprintf("failed to find the version '%s' of '%s'", tag, ociClient.repoRef()),
+		)
+	}
+	if err := ociClient.VerifySignature(desc, publicKeys); err != nil {
+		return "", err
+	}
+	reporter.ReportMsgTo(fmt.Sprintf("verified the signature of '%s:%s'", ociClient.repoRef(), tag), ociClient.logWriter)
+	return desc.Digest.String(), nil
+}
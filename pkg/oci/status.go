@@ -0,0 +1,182 @@
+package oci
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+
+	"kcl-lang.io/kpm/pkg/constants"
+	"kcl-lang.io/kpm/pkg/reporter"
+)
+
+// The status of a version published in the oci registry.
+const (
+	VERSION_STATUS_ACTIVE     = "active"
+	VERSION_STATUS_DEPRECATED = "deprecated"
+	VERSION_STATUS_YANKED     = "yanked"
+)
+
+// VersionStatus is the status of a version marked by the publisher.
+// It is pushed as an artifact referring to the manifest of the version,
+// so the version published is not changed and the latest status pushed takes effect.
+type VersionStatus struct {
+	Status string `json:"status"`
+	// Message tells the consumers why the version is deprecated or yanked.
+	Message string `json:"message,omitempty"`
+	// Replacement is the version suggested to use instead.
+	Replacement string `json:"replacement,omitempty"`
+	// Created is the time the status is pushed in RFC 3339 with the nanoseconds,
+	// so that the statuses pushed in the same second are still ordered.
+	Created string `json:"created,omitempty"`
+}
+
+// createdTime returns the time the status is pushed, or the zero time if it is not a valid time.
+func (s *VersionStatus) createdTime() time.Time {
+	created, err := time.Parse(time.RFC3339Nano, s.Created)
+	if err != nil {
+		return time.Time{}
+	}
+	return created
+}
+
+// NewerThan returns true if the status is pushed not earlier than the status 'other'.
+func (s *VersionStatus) NewerThan(other *VersionStatus) bool {
+	return other == nil || !s.createdTime().Before(other.createdTime())
+}
+
+// IsDeprecated returns true if the version is deprecated.
+func (s *VersionStatus) IsDeprecated() bool {
+	return s != nil && s.Status == VERSION_STATUS_DEPRECATED
+}
+
+// IsYanked returns true if the version is yanked.
+func (s *VersionStatus) IsYanked() bool {
+	return s != nil && s.Status == VERSION_STATUS_YANKED
+}
+
+// Describe returns the status with the message and the replacement, e.g. "deprecated: broken schema, use '0.1.3' instead".
+func (s *VersionStatus) Describe() string {
+	desc := s.Status
+	if len(s.Message) != 0 {
+		desc = fmt.Sprintf("%s: %s", desc, s.Message)
+	}
+	if len(s.Replacement) != 0 {
+		desc = fmt.Sprintf("%s, use '%s' instead", desc, s.Replacement)
+	}
+	return desc
+}
+
+// Annotations returns the annotations of the artifact of the status.
+func (s *VersionStatus) Annotations() map[string]string {
+	annotations := map[string]string{
+		constants.DEFAULT_KCL_OCI_STATUS_ANNOTATION: s.Status,
+	}
+	if len(s.Message) != 0 {
+		annotations[constants.DEFAULT_KCL_OCI_STATUS_MESSAGE] = s.Message
+	}
+	if len(s.Replacement) != 0 {
+		annotations[constants.DEFAULT_KCL_OCI_STATUS_REPLACEMENT] = s.Replacement
+	}
+	if len(s.Created) != 0 {
+		annotations[v1.AnnotationCreated] = s.Created
+	}
+	return annotations
+}
+
+// VersionStatusFromAnnotations returns the status from the annotations of the artifact of the status.
+// It returns nil if the annotations are not the annotations of a status.
+func VersionStatusFromAnnotations(annotations map[string]string) *VersionStatus {
+	status, ok := annotations[constants.DEFAULT_KCL_OCI_STATUS_ANNOTATION]
+	if !ok {
+		return nil
+	}
+	return &VersionStatus{
+		Status:      status,
+		Message:     annotations[constants.DEFAULT_KCL_OCI_STATUS_MESSAGE],
+		Replacement: annotations[constants.DEFAULT_KCL_OCI_STATUS_REPLACEMENT],
+		Created:     annotations[v1.AnnotationCreated],
+	}
+}
+
+// PushVersionStatus will push the status as an artifact referring to the manifest of the version 'tag'.
+// The registry without the referrers api is supported by the referrers tag schema.
+func (ociClient *OciClient) PushVersionStatus(tag string, status *VersionStatus) error {
+	desc, err := ociClient.repo.Resolve(*ociClient.ctx, tag)
+	if err != nil {
+		return reporter.NewErrorEvent(
+			reporter.FailedMarkPkgVersion,
+			err,
+			fmt.Sprintf("failed to find the version '%s' of '%s'", tag, ociClient.repo.Reference.String()),
+		)
+	}
+
+	// The created time is written by kpm, the one written by oras is only precise to the second.
+	pushed := *status
+	pushed.Created = time.Now().UTC().Format(time.RFC3339Nano)
+	statusDesc, err := oras.PackManifest(
+		*ociClient.ctx,
+		ociClient.repo,
+		oras.PackManifestVersion1_1,
+		constants.DEFAULT_KCL_OCI_STATUS_ARTIFACT_TYPE,
+		oras.PackManifestOptions{
+			Subject:             &desc,
+			ManifestAnnotations: pushed.Annotations(),
+		},
+	)
+	if err != nil {
+		return reporter.NewErrorEvent(
+			reporter.FailedMarkPkgVersion,
+			err,
+			fmt.Sprintf("failed to mark the version '%s' of '%s' as %s", tag, ociClient.repo.Reference.String(), status.Status),
+		)
+	}
+
+	reporter.ReportMsgTo(fmt.Sprintf("marked [registry] %s:%s as %s", ociClient.repo.Reference, tag, status.Status), ociClient.logWriter)
+	reporter.ReportMsgTo(fmt.Sprintf("digest: %s", statusDesc.Digest), ociClient.logWriter)
+	return nil
+}
+
+// FetchVersionStatus will return the latest status pushed for the version 'tag'.
+// It returns nil if the version has never been marked.
+func (ociClient *OciClient) FetchVersionStatus(tag string) (*VersionStatus, error) {
+	desc, err := ociClient.repo.Resolve(*ociClient.ctx, tag)
+	if err != nil {
+		return nil, reporter.NewErrorEvent(
+			reporter.FailedGetPkg,
+			err,
+			fmt.Sprintf("failed to find the version '%s' of '%s'", tag, ociClient.repo.Reference.String()),
+		)
+	}
+
+	var latest *VersionStatus
+	err = ociClient.repo.Referrers(*ociClient.ctx, desc, constants.DEFAULT_KCL_OCI_STATUS_ARTIFACT_TYPE, func(referrers []v1.Descriptor) error {
+		for _, referrer := range referrers {
+			manifestContent, err := content.FetchAll(*ociClient.ctx, ociClient.repo, referrer)
+			if err != nil {
+				return err
+			}
+			var manifest v1.Manifest
+			if err := json.Unmarshal(manifestContent, &manifest); err != nil {
+				return err
+			}
+			status := VersionStatusFromAnnotations(manifest.Annotations)
+			if status != nil && status.NewerThan(latest) {
+				latest = status
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, reporter.NewErrorEvent(
+			reporter.FailedGetPkg,
+			err,
+			fmt.Sprintf("failed to get the status of the version '%s' of '%s'", tag, ociClient.repo.Reference.String()),
+		)
+	}
+
+	return latest, nil
+}
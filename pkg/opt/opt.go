@@ -416,6 +416,9 @@ type OciOptions struct {
 	// Annotations denotes the additional annotation map for the OCI manifest.
 	// +optional
 	Annotations map[string]string
+	// Config denotes the content of the config blob of the kcl package for the OCI manifest.
+	// +optional
+	Config []byte
 	// InsecureSkipTLSverify denotes whether to skip the verification of the certificate.
 	// +optional
 	InsecureSkipTLSverify bool
@@ -496,6 +499,9 @@ func (oci *OciOptions) SanitizePathWithSuffix(pathPrefix string) string {
 
 type OciManifestOptions struct {
 	Annotations map[string]string
+	// Config is the content of the config blob of the kcl package.
+	// The empty config is pushed if it is empty, which is the layout before the config blob is introduced.
+	Config []byte
 }
 
 // OciFetchOptions is the input options of the api to fetch oci manifest.
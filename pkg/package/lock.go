@@ -0,0 +1,185 @@
+// Copyright 2024 The KCL Authors. All rights reserved.
+//
+// kcl.mod.lock records the dependencies resolved for the package with the provenance of them,
+// so that the same dependency tree can be rebuilt later.
+//
+// The lock file is versioned. The lock file without the version is the lock file version 1,
+// it is migrated to the current version automatically when it is loaded and written again.
+//
+// The dependency on a git branch is pinned to the commit recorded as 'resolved_commit',
+// the same commit is checked out until the dependency is updated by 'kpm update'.
+//
+//	version = 2
+//
+//	[dependencies]
+//	  [dependencies.helloworld]
+//	    name = "helloworld"
+//	    full_name = "helloworld_0.1.2"
+//	    version = "0.1.2"
+//	    sum = "PN0OMEV9M8VGFn1CtA/T3bcgZmMJmOo+RkBrLKIWYeQ="
+//	    dependents = ["my_pkg"]
+//	    reg = "ghcr.io"
+//	    repo = "kcl-lang/helloworld"
+//	    oci_tag = "0.1.2"
+//	    oci_digest = "sha256:..."
+package pkg
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"kcl-lang.io/kpm/pkg/utils"
+)
+
+// LOCK_FILE_VERSION is the version of kcl.mod.lock written by kpm.
+const LOCK_FILE_VERSION = 2
+
+// LockFile is the content of kcl.mod.lock.
+type LockFile struct {
+	Version int                   `toml:"version,omitempty"`
+	Deps    map[string]Dependency `toml:"dependencies,omitempty"`
+}
+
+// checkLockFileVersion checks whether the version of kcl.mod.lock is supported.
+func checkLockFileVersion(version int) error {
+	if version > LOCK_FILE_VERSION {
+		return fmt.Errorf(
+			"the version %d of kcl.mod.lock is not supported, the latest supported version is %d, please upgrade kpm",
+			version, LOCK_FILE_VERSION,
+		)
+	}
+	return nil
+}
+
+// AddDependents adds the names of the packages requiring the dependency.
+// The dependents are deduplicated and sorted.
+func (dep *Dependency) AddDependents(dependents ...string) {
+	seen := make(map[string]bool)
+	var merged []string
+	for _, dependent := range append(append([]string{}, dep.Dependents...), dependents...) {
+		if len(dependent) == 0 || seen[dependent] {
+			continue
+		}
+		seen[dependent] = true
+		merged = append(merged, dependent)
+	}
+	sort.Strings(merged)
+	dep.Dependents = merged
+}
+
+// KeepProvenance keeps the provenance of the dependency recorded in kcl.mod.lock,
+// if the dependency is resolved from the same source without the provenance, e.g. from the package cache.
+func (dep *Dependency) KeepProvenance(lockedDep *Dependency) {
+	if lockedDep == nil || dep.Version != lockedDep.Version {
+		return
+	}
+	if dep.Source.Oci != nil && lockedDep.Source.Oci != nil &&
+		len(dep.Source.Oci.Digest) == 0 && len(lockedDep.Source.Oci.Digest) != 0 &&
+		dep.Source.Oci.Reg == lockedDep.Source.Oci.Reg &&
+		dep.Source.Oci.Repo == lockedDep.Source.Oci.Repo &&
+		dep.Source.Oci.Tag == lockedDep.Source.Oci.Tag {
+		oci := *dep.Source.Oci
+		oci.Digest = lockedDep.Source.Oci.Digest
+		dep.Source.Oci = &oci
+	}
+	dep.PinGitBranch(lockedDep)
+}
+
+// PinGitBranch pins the dependency on a git branch to the commit the branch is resolved to in kcl.mod.lock,
+// so that the same commit is checked out until the dependency is updated.
+func (dep *Dependency) PinGitBranch(lockedDep *Dependency) {
+	if lockedDep == nil || dep.Source.Git == nil || lockedDep.Source.Git == nil {
+		return
+	}
+	if len(dep.Source.Git.Branch) != 0 &&
+		len(dep.Source.Git.ResolvedCommit) == 0 && len(lockedDep.Source.Git.ResolvedCommit) != 0 &&
+		dep.Source.Git.Url == lockedDep.Source.Git.Url &&
+		dep.Source.Git.Branch == lockedDep.Source.Git.Branch &&
+		dep.Source.Git.Package == lockedDep.Source.Git.Package {
+		git := *dep.Source.Git
+		git.ResolvedCommit = lockedDep.Source.Git.ResolvedCommit
+		dep.Source.Git = &git
+	}
+}
+
+// IsUnpinnedGitBranch returns true if the dependency is on a git branch not resolved to a commit yet.
+func (dep *Dependency) IsUnpinnedGitBranch() bool {
+	return dep.Source.Git != nil && len(dep.Source.Git.Branch) != 0 && len(dep.Source.Git.ResolvedCommit) == 0
+}
+
+// UnpinGitBranches clears the commits the git branches are pinned to,
+// so that the latest commits of the branches are resolved again, e.g. by 'kpm update'.
+// Only the dependencies in 'names' are unpinned if any, otherwise all the git branches are unpinned.
+func (deps *Dependencies) UnpinGitBranches(names ...string) {
+	for _, name := range deps.Deps.Keys() {
+		if len(names) != 0 && !slices.Contains(names, name) {
+			continue
+		}
+		dep, ok := deps.Deps.Get(name)
+		if !ok || dep.Source.Git == nil || len(dep.Source.Git.ResolvedCommit) == 0 {
+			continue
+		}
+		git := *dep.Source.Git
+		git.ResolvedCommit = ""
+		dep.Source.Git = &git
+		deps.Deps.Set(name, dep)
+	}
+}
+
+// MigrateSum migrates the checksum computed by the algorithm before 'h1:' to the 'h1:' checksum,
+// if the content under 'localPath' is verified by the checksum.
+func (dep *Dependency) MigrateSum(localPath string) {
+	if len(dep.Sum) == 0 || utils.IsH1Sum(dep.Sum) || !utils.CheckPackageSum(dep.Sum, localPath) {
+		return
+	}
+	if sum, err := utils.HashDirH1(localPath); err == nil {
+		dep.Sum = sum
+	}
+}
+
+// sumChanged returns true if the checksum of the dependency resolved is different from the one locked.
+// The checksums computed by different algorithms are not comparable,
+// so the checksum locked is verified against the content of the dependency resolved.
+func sumChanged(lockedDep, newDep *Dependency) bool {
+	if len(newDep.Sum) == 0 || lockedDep.Sum == newDep.Sum {
+		return false
+	}
+	if utils.SameSumAlgorithm(lockedDep.Sum, newDep.Sum) || len(newDep.LocalFullPath) == 0 {
+		return true
+	}
+	return !utils.CheckPackageSum(lockedDep.Sum, newDep.LocalFullPath)
+}
+
+// withoutProvenance returns a copy of the dependency without the provenance recorded in kcl.mod.lock.
+func (dep Dependency) withoutProvenance() Dependency {
+	dep.Dependents = nil
+	if dep.Source.Oci != nil {
+		oci := *dep.Source.Oci
+		oci.Digest = ""
+		dep.Source.Oci = &oci
+	}
+	if dep.Source.Git != nil {
+		git := *dep.Source.Git
+		git.ResolvedCommit = ""
+		dep.Source.Git = &git
+	}
+	return dep
+}
+
+// diffProvenance returns the differences of the provenance between the dependency locked and the dependency resolved.
+// The provenance missing on either side is not a difference, e.g. the lock file version 1 without the provenance.
+func diffProvenance(name string, lockedDep, newDep *Dependency) []string {
+	var diffs []string
+	if lockedDep.Source.Oci != nil && newDep.Source.Oci != nil &&
+		len(lockedDep.Source.Oci.Digest) != 0 && len(newDep.Source.Oci.Digest) != 0 &&
+		lockedDep.Source.Oci.Digest != newDep.Source.Oci.Digest {
+		diffs = append(diffs, fmt.Sprintf("~ %s: digest %s -> %s", name, lockedDep.Source.Oci.Digest, newDep.Source.Oci.Digest))
+	}
+	if lockedDep.Source.Git != nil && newDep.Source.Git != nil &&
+		len(lockedDep.Source.Git.ResolvedCommit) != 0 && len(newDep.Source.Git.ResolvedCommit) != 0 &&
+		lockedDep.Source.Git.ResolvedCommit != newDep.Source.Git.ResolvedCommit {
+		diffs = append(diffs, fmt.Sprintf("~ %s: commit %s -> %s", name, lockedDep.Source.Git.ResolvedCommit, newDep.Source.Git.ResolvedCommit))
+	}
+	return diffs
+}
@@ -2,6 +2,7 @@
 package pkg
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -39,6 +40,20 @@ type Package struct {
 	Version string `toml:"version,omitempty"`
 	// Description denotes the description of the package.
 	Description string `toml:"description,omitempty"` // kcl package description
+	// License denotes the license of the package in the SPDX license expression, e.g. 'Apache-2.0 OR MIT'.
+	License string `toml:"license,omitempty"`
+	// Authors denotes the people or organizations responsible for the package.
+	Authors []string `toml:"authors,omitempty"`
+	// Homepage denotes the url of the homepage of the package.
+	Homepage string `toml:"homepage,omitempty"`
+	// Repository denotes the url of the source repository of the package.
+	Repository string `toml:"repository,omitempty"`
+	// Documentation denotes the url of the documentation of the package.
+	Documentation string `toml:"documentation,omitempty"`
+	// Keywords denotes the keywords to search the package.
+	Keywords []string `toml:"keywords,omitempty"`
+	// Readme denotes the path of the readme file in the package.
+	Readme string `toml:"readme,omitempty"`
 	// Exclude denote the files to include when publishing.
 	Include []string `toml:"include,omitempty"`
 	// Exclude denote the files to exclude when publishing.
@@ -55,6 +70,9 @@ type ModFile struct {
 	VendorMode bool     `toml:"-"`
 	Profiles   *Profile `toml:"profile"`
 	Dependencies
+	// The dependencies only required by the package itself, e.g. for running tests and examples.
+	// They are left out when the package is consumed as a dependency or packaged.
+	DevDependencies Dependencies `toml:"-"`
 }
 
 // Profile is the profile section of 'kcl.mod'.
@@ -136,6 +154,37 @@ func (modFile *ModFile) FillDependenciesInfo() error {
 	return nil
 }
 
+// DepsWithDevDeps returns the dependencies in kcl.mod including the dev-dependencies,
+// which are resolved only when the package itself is built.
+func (modFile *ModFile) DepsWithDevDeps() *Dependencies {
+	if modFile.DevDependencies.Deps == nil || modFile.DevDependencies.Deps.Len() == 0 {
+		return &modFile.Dependencies
+	}
+
+	deps := Dependencies{
+		Deps:     orderedmap.NewOrderedMap[string, Dependency](),
+		Replaces: modFile.Replaces,
+	}
+	for _, k := range modFile.Deps.Keys() {
+		v, _ := modFile.Deps.Get(k)
+		deps.Deps.Set(k, v)
+	}
+	for _, k := range modFile.DevDependencies.Deps.Keys() {
+		v, _ := modFile.DevDependencies.Deps.Get(k)
+		deps.Deps.Set(k, v)
+	}
+	return &deps
+}
+
+// IsDevDep returns true if the dependency is only in the dev-dependencies of kcl.mod.
+func (modFile *ModFile) IsDevDep(name string) bool {
+	if modFile.DevDependencies.Deps == nil {
+		return false
+	}
+	_, ok := modFile.DevDependencies.Deps.Get(name)
+	return ok
+}
+
 // GetEntries will get the entry kcl files from kcl.mod.
 func (modFile *ModFile) GetEntries() []string {
 	if modFile.Profiles == nil {
@@ -147,6 +196,9 @@ func (modFile *ModFile) GetEntries() []string {
 // 'Dependencies' is dependencies section of 'kcl.mod'.
 type Dependencies struct {
 	Deps *orderedmap.OrderedMap[string, Dependency] `json:"packages" toml:"dependencies,omitempty"`
+	// Replaces is the [replace] section of 'kcl.mod' of the package being built.
+	// It is shared with the dependencies in 'kcl.mod.lock' to redirect the indirect dependencies.
+	Replaces Replaces `json:"-" toml:"-"`
 }
 
 // ToDepMetadata will transform the dependencies into metadata.
@@ -189,15 +241,37 @@ func (deps *Dependencies) CheckForLocalDeps() bool {
 	return false
 }
 
+// HasRelativeLocalDeps returns true if any dependency is from a local path relative to the package,
+// which can only be found from the home path of the package.
+func (deps *Dependencies) HasRelativeLocalDeps() bool {
+	if deps.Deps == nil {
+		return false
+	}
+	for _, depKeys := range deps.Deps.Keys() {
+		dep, _ := deps.Deps.Get(depKeys)
+		if dep.Source.Local != nil && !filepath.IsAbs(dep.Source.Local.Path) {
+			return true
+		}
+	}
+	return false
+}
+
 type Dependency struct {
 	Name     string `json:"name" toml:"name,omitempty"`
 	FullName string `json:"-" toml:"full_name,omitempty"`
 	Version  string `json:"-" toml:"version,omitempty"`
 	Sum      string `json:"-" toml:"sum,omitempty"`
+	// The replacement the dependency is resolved from by the [replace] section in kcl.mod.
+	Replace string `json:"-" toml:"replace,omitempty"`
+	// The names of the packages requiring the dependency, recorded in kcl.mod.lock.
+	Dependents []string `json:"-" toml:"dependents,omitempty"`
 	// The actual local path of the package.
 	// In vendor mode is "current_kcl_package/vendor"
 	// In non-vendor mode is "$KCL_PKG_PATH"
-	LocalFullPath     string `json:"manifest_path" toml:"-"`
+	LocalFullPath string `json:"manifest_path" toml:"-"`
+	// FromWorkspace is true if the dependency is resolved to the local copy of a workspace member
+	// without the sum locked from the registry, it is kept out of kcl.mod.lock.
+	FromWorkspace     bool `json:"-" toml:"-"`
 	downloader.Source `json:"-"`
 }
 
@@ -228,6 +302,41 @@ func (d *Dependency) GetAliasName() string {
 	return strings.ReplaceAll(d.Name, "-", "_")
 }
 
+// IsAliased returns true if the package is depended on under a name different from the package name,
+// e.g. 'k8s_old = { package = "k8s", version = "1.27" }'.
+func (d *Dependency) IsAliased() bool {
+	return d.Source.Git == nil && d.Source.ModSpec != nil &&
+		len(d.Source.ModSpec.Name) != 0 && d.Source.ModSpec.Name != d.Name
+}
+
+// PkgName returns the name of the package the dependency depends on.
+// It is different from the name of the dependency if the package is depended on under an alias.
+func (d *Dependency) PkgName() string {
+	if d.Source.Git == nil && d.Source.ModSpec != nil && len(d.Source.ModSpec.Name) != 0 {
+		return d.Source.ModSpec.Name
+	}
+	// The full name in kcl.mod.lock is generated from the package, e.g. 'k8s_1.27'.
+	if name, ok := strings.CutSuffix(d.FullName, "_"+d.Version); ok && len(name) != 0 && len(d.Version) != 0 {
+		return name
+	}
+	return d.Name
+}
+
+// WithAlias returns a copy of the dependency depended on under the name 'alias'.
+// The package name is kept in the spec of the dependency, so that the package can be found in the registry.
+func (d *Dependency) WithAlias(alias string) Dependency {
+	aliasedDep := *d
+	if d.Source.Git == nil && d.Source.ModSpec != nil {
+		modSpec := *d.Source.ModSpec
+		if len(modSpec.Name) == 0 {
+			modSpec.Name = d.Name
+		}
+		aliasedDep.Source.ModSpec = &modSpec
+	}
+	aliasedDep.Name = alias
+	return aliasedDep
+}
+
 func (d Dependency) Equals(other Dependency) bool {
 	var sameVersion = true
 	if len(d.Version) != 0 && len(other.Version) != 0 {
@@ -247,7 +356,9 @@ func (d Dependency) Equals(other Dependency) bool {
 	if d.Source.Oci != nil && other.Source.Oci != nil {
 		sameOciSrc = d.Source.Oci.Reg == other.Source.Oci.Reg &&
 			d.Source.Oci.Repo == other.Source.Oci.Repo &&
-			d.Source.Oci.Tag == other.Source.Oci.Tag
+			d.Source.Oci.Tag == other.Source.Oci.Tag &&
+			d.Source.Oci.IsPinned() == other.Source.Oci.IsPinned() &&
+			(!d.Source.Oci.IsPinned() || d.Source.Oci.Digest == other.Source.Oci.Digest)
 	}
 
 	return sameNameAndVersion && sameGitSrc && sameOciSrc
@@ -269,20 +380,14 @@ func (d *Dependency) GenPathSuffix() string {
 	var storePkgName string
 	name := d.Name
 	if d.Source.Oci != nil {
-		storePkgName = fmt.Sprintf(PKG_NAME_PATTERN, name, d.Source.Oci.Tag)
+		storePkgName = fmt.Sprintf(PKG_NAME_PATTERN, name, d.Source.Oci.GetPinnedReference())
 	} else if d.Source.Git != nil {
 		// TODO: new local dependency structure will replace this
 		// issue: https://github.com/kcl-lang/kpm/issues/384
 		if d.Source.Git.GetPackage() != "" {
 			name = strings.Split(d.FullName, "_")[0]
 		}
-		if len(d.Source.Git.Tag) != 0 {
-			storePkgName = fmt.Sprintf(PKG_NAME_PATTERN, name, d.Source.Git.Tag)
-		} else if len(d.Source.Git.Commit) != 0 {
-			storePkgName = fmt.Sprintf(PKG_NAME_PATTERN, name, d.Source.Git.Commit)
-		} else {
-			storePkgName = fmt.Sprintf(PKG_NAME_PATTERN, name, d.Source.Git.Branch)
-		}
+		storePkgName = fmt.Sprintf(PKG_NAME_PATTERN, name, d.Source.Git.GetPinnedReference())
 	} else {
 		storePkgName = fmt.Sprintf(PKG_NAME_PATTERN, d.Name, d.Version)
 	}
@@ -306,7 +411,7 @@ func (dep *Dependency) FillDepInfo(homepath string) error {
 		}
 
 		if dep.Source.Oci.Repo == "" {
-			urlpath := utils.JoinPath(settings.DefaultOciRepo(), dep.Name)
+			urlpath := utils.JoinPath(settings.DefaultOciRepo(), dep.PkgName())
 			dep.Source.Oci.Repo = urlpath
 		}
 	}
@@ -325,19 +430,35 @@ func (dep *Dependency) GenDepFullName() string {
 	name := dep.Name
 	if dep.Source.Git != nil {
 		name := utils.ParseRepoNameFromGitUrl(dep.Source.Git.Url)
-		if len(dep.Source.Git.Tag) != 0 {
-			dep.FullName = fmt.Sprintf(PKG_NAME_PATTERN, name, dep.Source.Git.Tag)
-		} else if len(dep.Source.Git.Commit) != 0 {
-			dep.FullName = fmt.Sprintf(PKG_NAME_PATTERN, name, dep.Source.Git.Commit)
-		} else {
-			dep.FullName = fmt.Sprintf(PKG_NAME_PATTERN, name, dep.Source.Git.Branch)
-		}
+		dep.FullName = fmt.Sprintf(PKG_NAME_PATTERN, name, dep.Source.Git.GetPinnedReference())
 	} else {
 		dep.FullName = fmt.Sprintf(PKG_NAME_PATTERN, name, dep.Version)
 	}
 	return dep.FullName
 }
 
+// WithExactVersion returns a copy of the dependency required by a version range
+// with the version range replaced by the exact version.
+func (dep *Dependency) WithExactVersion(version string) Dependency {
+	exactDep := *dep
+	exactDep.Source = dep.Source.WithExactVersion(version)
+	exactDep.Version = version
+	exactDep.FullName = exactDep.GenDepFullName()
+	return exactDep
+}
+
+// WithVersion returns a copy of the dependency pinned to the version.
+// The checksum and the local path of the dependency are cleared, because they belong to the old version.
+func (dep *Dependency) WithVersion(version string) Dependency {
+	pinnedDep := *dep
+	pinnedDep.Source = dep.Source.WithVersion(version)
+	pinnedDep.Version = version
+	pinnedDep.Sum = ""
+	pinnedDep.LocalFullPath = ""
+	pinnedDep.FullName = pinnedDep.GenDepFullName()
+	return pinnedDep
+}
+
 // GetDownloadPath will get the download path of a dependency.
 func (dep *Dependency) GetDownloadPath() string {
 	if dep.Source.Git != nil {
@@ -415,6 +536,96 @@ func LoadLockDeps(homePath string) (*Dependencies, error) {
 	return deps, nil
 }
 
+// ParseModFile parses the content of kcl.mod and kcl.mod.lock,
+// e.g. the content in the config blob of the package published in the oci registry.
+// The default oci registry in the settings is filled into the dependencies, as 'LoadModFile' does.
+func ParseModFile(modContent, lockContent string) (*ModFile, *Dependencies, error) {
+	modFile := new(ModFile)
+	if err := toml.Unmarshal([]byte(modContent), modFile); err != nil {
+		return nil, nil, reporter.NewErrorEvent(reporter.FailedLoadKclMod, err, "failed to parse kcl.mod")
+	}
+	if err := fillDepsInfoWithSettings(&modFile.Dependencies, settings.GetSettings()); err != nil {
+		return nil, nil, err
+	}
+	if err := fillDepsInfoWithSettings(&modFile.DevDependencies, settings.GetSettings()); err != nil {
+		return nil, nil, err
+	}
+
+	deps := new(Dependencies)
+	deps.Deps = orderedmap.NewOrderedMap[string, Dependency]()
+	if len(lockContent) != 0 {
+		if err := deps.UnmarshalLockTOML(lockContent); err != nil {
+			return nil, nil, err
+		}
+	}
+	return modFile, deps, nil
+}
+
+// DiffLockDeps returns the differences between the dependencies locked in kcl.mod.lock and the new dependencies.
+// Each difference is a line starting with '+' for the added dependency,
+// '-' for the removed dependency and '~' for the changed dependency.
+func DiffLockDeps(lockedDeps, newDeps *Dependencies) []string {
+	return diffLockDeps(lockedDeps, newDeps, true)
+}
+
+// diffLockDeps returns the differences between the dependencies locked in kcl.mod.lock and the new dependencies,
+// the sums are compared only if 'checkSum' is true.
+func diffLockDeps(lockedDeps, newDeps *Dependencies, checkSum bool) []string {
+	var diffs []string
+	for _, name := range newDeps.Deps.Keys() {
+		newDep, _ := newDeps.Deps.Get(name)
+		if newDep.FromWorkspace {
+			continue
+		}
+		lockedDep, ok := lockedDeps.Deps.Get(name)
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("+ %s %s (missing from kcl.mod.lock)", name, newDep.Version))
+			continue
+		}
+		if lockedDep.Version != newDep.Version {
+			diffs = append(diffs, fmt.Sprintf("~ %s: version %s -> %s", name, lockedDep.Version, newDep.Version))
+		}
+		if checkSum && sumChanged(&lockedDep, &newDep) {
+			diffs = append(diffs, fmt.Sprintf("~ %s: sum %s -> %s", name, lockedDep.Sum, newDep.Sum))
+		}
+		if lockedEntry, newEntry := lockEntryWithoutVersion(lockedDep), lockEntryWithoutVersion(newDep); lockedEntry != newEntry {
+			diffs = append(diffs, fmt.Sprintf("~ %s: source %s -> %s", name, strings.TrimSpace(lockedEntry), strings.TrimSpace(newEntry)))
+		}
+		diffs = append(diffs, diffProvenance(name, &lockedDep, &newDep)...)
+	}
+	for _, name := range lockedDeps.Deps.Keys() {
+		if newDep, ok := newDeps.Deps.Get(name); !ok || newDep.FromWorkspace {
+			lockedDep, _ := lockedDeps.Deps.Get(name)
+			diffs = append(diffs, fmt.Sprintf("- %s %s (no longer required)", name, lockedDep.Version))
+		}
+	}
+	return diffs
+}
+
+// lockEntryWithoutVersion returns the entry of the dependency in kcl.mod.lock
+// without the fields of the version, the checksum and the provenance, which are compared separately.
+func lockEntryWithoutVersion(dep Dependency) string {
+	dep = dep.withoutProvenance()
+	dep.FullName = ""
+	dep.Version = ""
+	dep.Sum = ""
+	buf := new(bytes.Buffer)
+	if err := toml.NewEncoder(buf).Encode(&dep); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// NewLockFileChangedError returns the error reported under the locked mode
+// when kcl.mod.lock would be changed, the differences are listed in the error.
+func NewLockFileChangedError(diffs []string) error {
+	return reporter.NewErrorEvent(
+		reporter.LockFileChanged,
+		fmt.Errorf("kcl.mod.lock needs to be updated, but the locked mode is enabled:\n  %s", strings.Join(diffs, "\n  ")),
+		"run the command without '--locked' to update kcl.mod.lock",
+	)
+}
+
 // Write the contents of 'ModFile' to 'kcl.mod' file
 func (mfile *ModFile) StoreModFile() error {
 	fullPath := filepath.Join(mfile.HomePath, MOD_FILE)
@@ -528,10 +739,19 @@ func ParseOpt(opt *opt.RegistryOptions) (*Dependency, error) {
 			Repo: opt.Oci.Repo,
 			Tag:  opt.Oci.Tag,
 		}
+		name := opt.Oci.Ref
+		// The oci url pinned by the digest, e.g. 'oci://ghcr.io/kcl-lang/helloworld@sha256:...'.
+		if repo, dgst, ok := strings.Cut(opt.Oci.Repo, "@"); ok {
+			ociSource.Repo = repo
+			if err := ociSource.PinDigest(dgst); err != nil {
+				return nil, err
+			}
+			name = filepath.Base(repo)
+		}
 
 		return &Dependency{
-			Name:     opt.Oci.Ref,
-			FullName: opt.Oci.Ref + "_" + opt.Oci.Tag,
+			Name:     name,
+			FullName: name + "_" + ociSource.GetPinnedReference(),
 			Source: downloader.Source{
 				Oci: &ociSource,
 			},
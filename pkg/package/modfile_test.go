@@ -5,8 +5,10 @@ import (
 	"path/filepath"
 	"testing"
 
+	orderedmap "github.com/elliotchance/orderedmap/v2"
 	"github.com/stretchr/testify/assert"
 
+	"kcl-lang.io/kpm/pkg/downloader"
 	"kcl-lang.io/kpm/pkg/opt"
 	"kcl-lang.io/kpm/pkg/runner"
 	"kcl-lang.io/kpm/pkg/utils"
@@ -26,6 +28,24 @@ func TestModFileWithDesc(t *testing.T) {
 	assert.Equal(t, err, nil)
 }
 
+func TestModFileWithMetadata(t *testing.T) {
+	testPath := getTestDir("test_mod_with_metadata")
+	modFile, err := LoadModFile(testPath)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, modFile.Pkg.License, "Apache-2.0 OR MIT")
+	assert.Equal(t, modFile.Pkg.Authors, []string{"KCL Authors <kcl@kcl-lang.io>", "kpm maintainers"})
+	assert.Equal(t, modFile.Pkg.Homepage, "https://kcl-lang.io")
+	assert.Equal(t, modFile.Pkg.Repository, "https://github.com/kcl-lang/kpm")
+	assert.Equal(t, modFile.Pkg.Documentation, "https://kcl-lang.io/docs")
+	assert.Equal(t, modFile.Pkg.Keywords, []string{"kcl", "kubernetes"})
+	assert.Equal(t, modFile.Pkg.Readme, "README.md")
+
+	// The metadata is kept when kcl.mod is written.
+	expect, err := os.ReadFile(filepath.Join(testPath, MOD_FILE))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, utils.RmNewline(modFile.Pkg.MarshalTOML()), utils.RmNewline(string(expect)))
+}
+
 func TestDepEquals(t *testing.T) {
 	d := Dependency{
 		Name:    "test",
@@ -223,6 +243,21 @@ func TestLoadLockDeps(t *testing.T) {
 	assert.Equal(t, err, nil)
 }
 
+func TestParseModFileWithLock(t *testing.T) {
+	lockContent, err := os.ReadFile(filepath.Join(getTestDir("load_lock_file"), "kcl.mod.lock"))
+	assert.Equal(t, err, nil)
+
+	_, deps, err := ParseModFile("[package]\nname = \"test\"\n", string(lockContent))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, deps.Deps.Len(), 2)
+	dep, ok := deps.Deps.Get("oci_name")
+	assert.Equal(t, ok, true)
+	assert.Equal(t, dep.Source.Oci.Tag, "test_oci_tag")
+
+	_, _, err = ParseModFile("invalid toml [", "")
+	assert.NotEqual(t, err, nil)
+}
+
 func TestStoreModFile(t *testing.T) {
 	testPath := getTestDir("store_mod_file")
 	mfile := ModFile{
@@ -263,3 +298,81 @@ func TestGenSource(t *testing.T) {
 	assert.Equal(t, src.Oci.Repo, "kcl-lang/k8s")
 	assert.Equal(t, src.Oci.Tag, "1.24")
 }
+
+func TestPinGitBranch(t *testing.T) {
+	newDep := func(resolvedCommit string) Dependency {
+		return Dependency{
+			Name: "catalog",
+			Source: downloader.Source{
+				Git: &downloader.Git{
+					Url:            "https://github.com/kcl-lang/catalog.git",
+					Branch:         "main",
+					ResolvedCommit: resolvedCommit,
+				},
+			},
+		}
+	}
+
+	dep := newDep("")
+	assert.Equal(t, dep.IsUnpinnedGitBranch(), true)
+	assert.Equal(t, dep.GenPathSuffix(), "catalog_main")
+	assert.Equal(t, dep.GenDepFullName(), "catalog_main")
+
+	lockedDep := newDep("1fe7ea1")
+	dep.PinGitBranch(&lockedDep)
+	assert.Equal(t, dep.IsUnpinnedGitBranch(), false)
+	assert.Equal(t, dep.Source.Git.ResolvedCommit, "1fe7ea1")
+	assert.Equal(t, dep.GenPathSuffix(), "catalog_1fe7ea1")
+	assert.Equal(t, dep.GenDepFullName(), "catalog_1fe7ea1")
+
+	// The dependency on another branch is not pinned to the commit locked.
+	otherDep := newDep("")
+	otherDep.Source.Git.Branch = "dev"
+	otherDep.PinGitBranch(&lockedDep)
+	assert.Equal(t, otherDep.IsUnpinnedGitBranch(), true)
+
+	deps := Dependencies{
+		Deps: orderedmap.NewOrderedMap[string, Dependency](),
+	}
+	deps.Deps.Set(lockedDep.Name, lockedDep)
+	deps.UnpinGitBranches()
+	unpinnedDep, _ := deps.Deps.Get(lockedDep.Name)
+	assert.Equal(t, unpinnedDep.IsUnpinnedGitBranch(), true)
+	// The dependency pinned is not changed by unpinning the copy of it.
+	assert.Equal(t, lockedDep.Source.Git.ResolvedCommit, "1fe7ea1")
+
+	// Only the dependencies specified are unpinned.
+	otherLockedDep := newDep("2ab8fb2")
+	otherLockedDep.Name = "other"
+	deps.Deps.Set(lockedDep.Name, lockedDep)
+	deps.Deps.Set(otherLockedDep.Name, otherLockedDep)
+	deps.UnpinGitBranches(otherLockedDep.Name)
+	unpinnedDep, _ = deps.Deps.Get(lockedDep.Name)
+	assert.Equal(t, unpinnedDep.Source.Git.ResolvedCommit, "1fe7ea1")
+	unpinnedDep, _ = deps.Deps.Get(otherLockedDep.Name)
+	assert.Equal(t, unpinnedDep.IsUnpinnedGitBranch(), true)
+}
+
+func TestMigrateSum(t *testing.T) {
+	testFullDir := filepath.Join(getTestDir("test_check"), "test_full_name")
+	lockedDep := Dependency{
+		Name: "test",
+		Sum:  "okQqHgQaR1il7vOPuZPPVostthK5nUJkZAZVgXMqU3Q=",
+	}
+
+	dep := lockedDep
+	dep.MigrateSum(testFullDir)
+	assert.Equal(t, dep.Sum, "h1:hJfEnJo+Lhu0d4ftj7k40CTM8poLXpGaZqSpkYwQlM4=")
+	// The sums computed by different algorithms of the same content are not changed.
+	dep.LocalFullPath = testFullDir
+	assert.Equal(t, sumChanged(&lockedDep, &dep), false)
+
+	// The sum not verified is not migrated.
+	modifiedDep := Dependency{
+		Name: "test",
+		Sum:  "sdfsldk",
+	}
+	modifiedDep.MigrateSum(testFullDir)
+	assert.Equal(t, modifiedDep.Sum, "sdfsldk")
+	assert.Equal(t, sumChanged(&modifiedDep, &dep), true)
+}
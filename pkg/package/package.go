@@ -1,6 +1,8 @@
 package pkg
 
 import (
+	"encoding/json"
+	goerrors "errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -12,6 +14,7 @@ import (
 	"kcl-lang.io/kpm/pkg/constants"
 	"kcl-lang.io/kpm/pkg/downloader"
 	errors "kcl-lang.io/kpm/pkg/errors"
+	"kcl-lang.io/kpm/pkg/oci"
 	"kcl-lang.io/kpm/pkg/opt"
 	"kcl-lang.io/kpm/pkg/reporter"
 	"kcl-lang.io/kpm/pkg/settings"
@@ -33,9 +36,13 @@ type KclPkg struct {
 	Dependencies
 	// The flag 'NoSumCheck' is true if the checksum of the current kcl package is not checked.
 	NoSumCheck bool
+	// The flag 'Locked' is true if kcl.mod.lock of the current kcl package must not be changed.
+	Locked bool
 	// A snapshot of the dependencies in kcl.mod
 	// readonly and user can't modify it.
 	depUI DependenciesUI
+	// A snapshot of the dev-dependencies in kcl.mod.
+	devDepUI DependenciesUI
 }
 
 func (p *KclPkg) BackupDepUI(name string, dep *Dependency) {
@@ -89,23 +96,8 @@ func LoadKclPkgWithOpts(options ...LoadOption) (*KclPkg, error) {
 	}
 
 	// Save snapshot of the dependencies in kcl.mod.
-	depsUI := DependenciesUI{
-		Deps: make(map[string]Dependency),
-	}
-
-	for _, name := range modFile.Deps.Keys() {
-		dep, ok := modFile.Deps.Get(name)
-		if !ok {
-			return nil, fmt.Errorf("could not load 'kcl.mod' in '%s'\n%w", pkgPath, err)
-		}
-		depSnap := Dependency{}
-		err := copier.Copy(&depSnap, &dep)
-		if err != nil {
-			fmt.Printf("failed to copy dependency: %v\n", err)
-			continue
-		}
-		depsUI.Deps[name] = depSnap
-	}
+	depsUI := snapshotDeps(&modFile.Dependencies)
+	devDepsUI := snapshotDeps(&modFile.DevDependencies)
 
 	// pre-process the package.
 	// 1. Transform the local path to the absolute path.
@@ -113,18 +105,31 @@ func LoadKclPkgWithOpts(options ...LoadOption) (*KclPkg, error) {
 	if err != nil {
 		return nil, fmt.Errorf("could not load 'kcl.mod' in '%s'\n%w", pkgPath, err)
 	}
+	err = convertDepsLocalPathToAbsPath(&modFile.DevDependencies, pkgPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not load 'kcl.mod' in '%s'\n%w", pkgPath, err)
+	}
+	err = convertReplacesLocalPathToAbsPath(modFile.Replaces, pkgPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not load 'kcl.mod' in '%s'\n%w", pkgPath, err)
+	}
 	// 2. Fill the default oci registry, the default oci registry is in the settings.
 	err = fillDepsInfoWithSettings(&modFile.Dependencies, opts.Settings)
 	if err != nil {
 		return nil, fmt.Errorf("could not load 'kcl.mod' in '%s'\n%w", pkgPath, err)
 	}
+	err = fillDepsInfoWithSettings(&modFile.DevDependencies, opts.Settings)
+	if err != nil {
+		return nil, fmt.Errorf("could not load 'kcl.mod' in '%s'\n%w", pkgPath, err)
+	}
 	// 3. Sync the dependencies information in kcl.mod.lock with the dependencies in kcl.mod.
+	modDeps := modFile.DepsWithDevDeps()
 	for _, name := range deps.Deps.Keys() {
 		lockDep, ok := deps.Deps.Get(name)
 		if !ok {
 			return nil, fmt.Errorf("could not load 'kcl.mod' in '%s'\n%w", pkgPath, err)
 		}
-		if modDep, ok := modFile.Dependencies.Deps.Get(name); ok {
+		if modDep, ok := modDeps.Deps.Get(name); ok {
 			lockDep.Source = modDep.Source
 			lockDep.LocalFullPath = modDep.LocalFullPath
 		} else {
@@ -132,12 +137,12 @@ func LoadKclPkgWithOpts(options ...LoadOption) (*KclPkg, error) {
 			if lockDep.Source.IsNilSource() {
 				lockDep.Source = downloader.Source{
 					ModSpec: &downloader.ModSpec{
-						Name:    lockDep.Name,
+						Name:    lockDep.PkgName(),
 						Version: lockDep.Version,
 					},
 					Oci: &downloader.Oci{
 						Reg:  opts.Settings.DefaultOciRegistry(),
-						Repo: utils.JoinPath(opts.Settings.DefaultOciRepo(), lockDep.Name),
+						Repo: utils.JoinPath(opts.Settings.DefaultOciRepo(), lockDep.PkgName()),
 						Tag:  lockDep.Version,
 					},
 				}
@@ -145,12 +150,15 @@ func LoadKclPkgWithOpts(options ...LoadOption) (*KclPkg, error) {
 		}
 		deps.Deps.Set(name, lockDep)
 	}
+	// 4. Share the replacements in kcl.mod with the dependencies in kcl.mod.lock.
+	deps.Replaces = modFile.Replaces
 
 	return &KclPkg{
 		ModFile:      *modFile,
 		HomePath:     pkgPath,
 		Dependencies: *deps,
 		depUI:        depsUI,
+		devDepUI:     devDepsUI,
 	}, nil
 }
 
@@ -179,15 +187,52 @@ func LoadAndFillModFileWithOpts(options ...LoadOption) (*ModFile, error) {
 	if err != nil {
 		return nil, fmt.Errorf("could not load 'kcl.mod' in '%s'\n%w", path, err)
 	}
+	err = convertDepsLocalPathToAbsPath(&modFile.DevDependencies, path)
+	if err != nil {
+		return nil, fmt.Errorf("could not load 'kcl.mod' in '%s'\n%w", path, err)
+	}
+	err = convertReplacesLocalPathToAbsPath(modFile.Replaces, path)
+	if err != nil {
+		return nil, fmt.Errorf("could not load 'kcl.mod' in '%s'\n%w", path, err)
+	}
 	// 2. Fill the default oci registry, the default oci registry is in the settings.
 	err = fillDepsInfoWithSettings(&modFile.Dependencies, opts.Settings)
 	if err != nil {
 		return nil, fmt.Errorf("could not load 'kcl.mod' in '%s'\n%w", path, err)
 	}
+	err = fillDepsInfoWithSettings(&modFile.DevDependencies, opts.Settings)
+	if err != nil {
+		return nil, fmt.Errorf("could not load 'kcl.mod' in '%s'\n%w", path, err)
+	}
 
 	return modFile, nil
 }
 
+// `snapshotDeps` will save a snapshot of the dependencies as they are in kcl.mod.
+func snapshotDeps(deps *Dependencies) DependenciesUI {
+	depsUI := DependenciesUI{
+		Deps: make(map[string]Dependency),
+	}
+	if deps.Deps == nil {
+		return depsUI
+	}
+
+	for _, name := range deps.Deps.Keys() {
+		dep, ok := deps.Deps.Get(name)
+		if !ok {
+			break
+		}
+		depSnap := Dependency{}
+		err := copier.Copy(&depSnap, &dep)
+		if err != nil {
+			fmt.Printf("failed to copy dependency: %v\n", err)
+			continue
+		}
+		depsUI.Deps[name] = depSnap
+	}
+	return depsUI
+}
+
 // `convertDepsLocalPathToAbsPath` will transform the local path to the absolute path from `rootPath` in dependencies.
 func convertDepsLocalPathToAbsPath(deps *Dependencies, rootPath string) error {
 	for _, name := range deps.Deps.Keys() {
@@ -229,7 +274,7 @@ func fillDepsInfoWithSettings(deps *Dependencies, settings *settings.Settings) e
 			}
 
 			if len(dep.Source.Oci.Repo) == 0 {
-				urlpath := utils.JoinPath(settings.DefaultOciRepo(), dep.Name)
+				urlpath := utils.JoinPath(settings.DefaultOciRepo(), dep.PkgName())
 				dep.Source.Oci.Repo = urlpath
 			}
 		}
@@ -253,14 +298,126 @@ func (kclPkg *KclPkg) GenOciManifestFromPkg() (map[string]string, error) {
 	res[constants.DEFAULT_KCL_OCI_MANIFEST_NAME] = kclPkg.GetPkgName()
 	res[constants.DEFAULT_KCL_OCI_MANIFEST_VERSION] = kclPkg.GetPkgVersion()
 	res[constants.DEFAULT_KCL_OCI_MANIFEST_DESCRIPTION] = kclPkg.GetPkgDescription()
+	// The checksum computed by the algorithm before 'h1:' is still written as the checksum of the package,
+	// because the clients before 'h1:' verify the package downloaded against it.
+	legacySum, err := kclPkg.GenLegacyCheckSum()
+	if err != nil {
+		return nil, err
+	}
+	res[constants.DEFAULT_KCL_OCI_MANIFEST_SUM] = legacySum
 	sum, err := kclPkg.GenCheckSum()
 	if err != nil {
 		return nil, err
 	}
-	res[constants.DEFAULT_KCL_OCI_MANIFEST_SUM] = sum
+	res[constants.DEFAULT_KCL_OCI_MANIFEST_SUM_H1] = sum
+
+	// The metadata of the package is also written as the pre-defined annotations of the oci image spec,
+	// the keywords and the readme without the pre-defined annotations are written as the kcl annotations.
+	metadata := kclPkg.ModFile.Pkg
+	annotations := map[string]string{
+		constants.OCI_MANIFEST_TITLE:                metadata.Name,
+		constants.OCI_MANIFEST_VERSION:              metadata.Version,
+		constants.OCI_MANIFEST_DESCRIPTION:          metadata.Description,
+		constants.OCI_MANIFEST_LICENSES:             metadata.License,
+		constants.OCI_MANIFEST_AUTHORS:              strings.Join(metadata.Authors, ", "),
+		constants.OCI_MANIFEST_URL:                  metadata.Homepage,
+		constants.OCI_MANIFEST_SOURCE:               metadata.Repository,
+		constants.OCI_MANIFEST_DOCUMENTATION:        metadata.Documentation,
+		constants.DEFAULT_KCL_OCI_MANIFEST_KEYWORDS: strings.Join(metadata.Keywords, ","),
+		constants.DEFAULT_KCL_OCI_MANIFEST_README:   metadata.Readme,
+	}
+	for key, value := range annotations {
+		if len(value) != 0 {
+			res[key] = value
+		}
+	}
+
+	// The direct dependencies are written so that they can be shown without downloading the package.
+	if deps := kclPkg.GetPkgDeps(); len(deps) != 0 {
+		res[constants.DEFAULT_KCL_OCI_MANIFEST_DEPS] = strings.Join(deps, ",")
+	}
 	return res, nil
 }
 
+// GenOciConfigFromPkg will generate the content of the oci config blob from the kcl package,
+// which holds kcl.mod and kcl.mod.lock of the package.
+// If the package has been packaged into the default tar path, kcl.mod and kcl.mod.lock are read from the tar,
+// which are the same as the files of the package downloaded, e.g. without the dev-dependencies.
+func (kclPkg *KclPkg) GenOciConfigFromPkg() ([]byte, error) {
+	readFile := func(name string) ([]byte, error) {
+		return os.ReadFile(filepath.Join(kclPkg.HomePath, name))
+	}
+	if tarPath := kclPkg.DefaultTarPath(); utils.DirExists(tarPath) {
+		readFile = func(name string) ([]byte, error) {
+			return utils.ReadFileFromTar(tarPath, name)
+		}
+	}
+
+	modContent, err := readFile(constants.KCL_MOD)
+	if err != nil {
+		return nil, reporter.NewErrorEvent(reporter.FailedLoadKclMod, err, fmt.Sprintf("failed to load '%s'", kclPkg.HomePath))
+	}
+	config := oci.PackageConfig{ModFile: string(modContent)}
+
+	lockContent, err := readFile(constants.KCL_MOD_LOCK)
+	if err != nil && !goerrors.Is(err, os.ErrNotExist) {
+		return nil, reporter.NewErrorEvent(reporter.FailedLoadKclModLock, err, fmt.Sprintf("failed to load '%s'", kclPkg.HomePath))
+	}
+	config.ModLockFile = string(lockContent)
+
+	return json.Marshal(config)
+}
+
+// ReleaseModFiles returns the content of kcl.mod and kcl.mod.lock of the package released to the consumers,
+// which are marshaled from the package without the dev-dependencies.
+// The dependencies in kcl.mod keep the format written by the user,
+// and the content of kcl.mod.lock is nil if the package has no kcl.mod.lock.
+func (kclPkg *KclPkg) ReleaseModFiles() ([]byte, []byte, error) {
+	modFile := kclPkg.ModFile
+	modFile.DevDependencies = Dependencies{}
+	modFile.Dependencies = Dependencies{
+		Deps:     orderedmap.NewOrderedMap[string, Dependency](),
+		Replaces: kclPkg.ModFile.Replaces,
+	}
+	if kclPkg.ModFile.Deps != nil {
+		for _, name := range kclPkg.ModFile.Deps.Keys() {
+			dep, _ := kclPkg.ModFile.Deps.Get(name)
+			modFile.Deps.Set(name, dep)
+		}
+	}
+	err := restoreDepsFromSnapshot(&modFile.Dependencies, kclPkg.depUI)
+	if err != nil {
+		return nil, nil, err
+	}
+	modContent := []byte(modFile.MarshalTOML())
+
+	if !utils.DirExists(kclPkg.GetLockFilePath()) {
+		return modContent, nil, nil
+	}
+	lockContent, err := kclPkg.Dependencies.MarshalLockTOML()
+	if err != nil {
+		return nil, nil, err
+	}
+	return modContent, []byte(lockContent), nil
+}
+
+// GetPkgDeps returns the direct dependencies in kcl.mod in the format of '<name>@<version>'.
+func (kclPkg *KclPkg) GetPkgDeps() []string {
+	deps := []string{}
+	if kclPkg.ModFile.Deps == nil {
+		return deps
+	}
+	for _, name := range kclPkg.ModFile.Deps.Keys() {
+		dep, _ := kclPkg.ModFile.Deps.Get(name)
+		if len(dep.Version) == 0 {
+			deps = append(deps, dep.Name)
+		} else {
+			deps = append(deps, dep.Name+"@"+dep.Version)
+		}
+	}
+	return deps
+}
+
 func (p *KclPkg) GetDepsMetadata() (*DependenciesUI, error) {
 	return p.Dependencies.ToDepMetadata()
 }
@@ -386,32 +543,32 @@ func (kclPkg *KclPkg) LocalVendorPath() string {
 func (kclPkg *KclPkg) UpdateModAndLockFile() error {
 
 	// Load kcl.mod SnapShot.
-	depSnapShot := kclPkg.depUI
-
-	for _, name := range kclPkg.ModFile.Deps.Keys() {
-		modDep, ok := kclPkg.ModFile.Deps.Get(name)
-		if !ok {
-			return fmt.Errorf("failed to get dependency %s", name)
-		}
+	err := restoreDepsFromSnapshot(&kclPkg.ModFile.Dependencies, kclPkg.depUI)
+	if err != nil {
+		return err
+	}
+	err = restoreDepsFromSnapshot(&kclPkg.ModFile.DevDependencies, kclPkg.devDepUI)
+	if err != nil {
+		return err
+	}
 
-		if existDep, ok := depSnapShot.Deps[name]; ok {
-			if existDep.Source.SpecOnly() {
-				existDep.Source.ModSpec = modDep.ModSpec
-			} else {
-				existDep.Source = modDep.Source
-			}
-			kclPkg.ModFile.Deps.Set(name, existDep)
+	// Under the locked mode, check kcl.mod.lock first to avoid changing kcl.mod only.
+	// The versions and the sources are still checked under '--no_sum_check', only the sums are not compared.
+	if kclPkg.Locked {
+		err := kclPkg.LockDepsVersion()
+		if err != nil {
+			return err
 		}
 	}
 
 	// Generate file kcl.mod.
-	err := kclPkg.ModFile.StoreModFile()
+	err = kclPkg.ModFile.StoreModFile()
 	if err != nil {
 		return err
 	}
 
 	// Generate file kcl.mod.lock.
-	if !kclPkg.NoSumCheck {
+	if !kclPkg.NoSumCheck && !kclPkg.Locked {
 		err := kclPkg.LockDepsVersion()
 		if err != nil {
 			return err
@@ -421,9 +578,47 @@ func (kclPkg *KclPkg) UpdateModAndLockFile() error {
 	return nil
 }
 
+// restoreDepsFromSnapshot restores the format of the dependencies in kcl.mod from the snapshot,
+// e.g. the dependency 'helloworld = "0.1.2"' is not expanded to the oci source.
+func restoreDepsFromSnapshot(deps *Dependencies, depSnapShot DependenciesUI) error {
+	if deps.Deps == nil {
+		return nil
+	}
+
+	for _, name := range deps.Deps.Keys() {
+		modDep, ok := deps.Deps.Get(name)
+		if !ok {
+			return fmt.Errorf("failed to get dependency %s", name)
+		}
+
+		if existDep, ok := depSnapShot.Deps[name]; ok {
+			if existDep.Source.SpecOnly() {
+				existDep.Source.ModSpec = modDep.ModSpec
+			} else {
+				existDep.Source = modDep.Source
+			}
+			deps.Deps.Set(name, existDep)
+		}
+	}
+	return nil
+}
+
 // LockDepsVersion locks the dependencies of the current kcl package into kcl.mod.lock.
+// Under the locked mode, kcl.mod.lock is not rewritten,
+// and an error with the differences is returned if the dependencies are different from kcl.mod.lock.
 func (kclPkg *KclPkg) LockDepsVersion() error {
 	fullPath := filepath.Join(kclPkg.HomePath, MOD_LOCK_FILE)
+	if kclPkg.Locked {
+		lockedDeps, err := LoadLockDeps(kclPkg.HomePath)
+		if err != nil {
+			return err
+		}
+		if diffs := diffLockDeps(lockedDeps, &kclPkg.Dependencies, !kclPkg.NoSumCheck); len(diffs) != 0 {
+			return NewLockFileChangedError(diffs)
+		}
+		return nil
+	}
+
 	lockToml, err := kclPkg.Dependencies.MarshalLockTOML()
 	if err != nil {
 		return err
@@ -440,17 +635,7 @@ func (kclPkg *KclPkg) CreateDefaultMain() error {
 
 // check sum for a Dependency.
 func check(dep Dependency, newDepPath string) bool {
-	if dep.Sum == "" {
-		return false
-	}
-
-	sum, err := utils.HashDir(newDepPath)
-
-	if err != nil {
-		return false
-	}
-
-	return dep.Sum == sum
+	return utils.CheckPackageSum(dep.Sum, newDepPath)
 }
 
 const TAR_SUFFIX = ".tar"
@@ -529,6 +714,41 @@ func (KclPkg *KclPkg) GetPkgExclude() []string {
 }
 
 // GenCheckSum generates the checksum of the current kcl package.
+// If the package has been packaged into the default tar path, the checksum is computed from the files in the tar,
+// which are the same as the files of the package downloaded.
 func (KclPkg *KclPkg) GenCheckSum() (string, error) {
-	return utils.HashDir(KclPkg.HomePath)
+	if tarPath := KclPkg.DefaultTarPath(); utils.DirExists(tarPath) {
+		return utils.HashTarH1(tarPath)
+	}
+	return utils.HashDirH1(KclPkg.HomePath)
+}
+
+// GenLegacyCheckSum generates the checksum of the current kcl package by the algorithm before 'h1:'.
+// If the package has been packaged into the default tar path, the checksum is computed from the files in the tar.
+func (KclPkg *KclPkg) GenLegacyCheckSum() (string, error) {
+	tarPath := KclPkg.DefaultTarPath()
+	if !utils.DirExists(tarPath) {
+		return utils.HashDir(KclPkg.HomePath)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "kcl_pkg")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmpDir)
+	err = utils.UnTarDir(tarPath, tmpDir)
+	if err != nil {
+		return "", err
+	}
+	return utils.HashDir(tmpDir)
+}
+
+// SumFromManifest returns the checksum of the package from the annotations of the oci manifest.
+// The 'h1:' checksum is preferred, the checksum before 'h1:' is returned for the packages pushed before it.
+func SumFromManifest(annotations map[string]string) (string, bool) {
+	if sum, ok := annotations[constants.DEFAULT_KCL_OCI_MANIFEST_SUM_H1]; ok {
+		return sum, true
+	}
+	sum, ok := annotations[constants.DEFAULT_KCL_OCI_MANIFEST_SUM]
+	return sum, ok
 }
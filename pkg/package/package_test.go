@@ -6,7 +6,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"kcl-lang.io/kpm/pkg/constants"
 	"kcl-lang.io/kpm/pkg/env"
+	"kcl-lang.io/kpm/pkg/oci"
 	"kcl-lang.io/kpm/pkg/opt"
 	"kcl-lang.io/kpm/pkg/reporter"
 	"kcl-lang.io/kpm/pkg/runner"
@@ -70,6 +72,78 @@ func TestCheck(t *testing.T) {
 	assert.Equal(t, check(dep, testFullDir), true)
 }
 
+func TestGenOciManifestFromPkgWithMetadata(t *testing.T) {
+	kclPkg, err := LoadKclPkg(getTestDir("test_mod_with_metadata"))
+	assert.Equal(t, err, nil)
+
+	manifest, err := kclPkg.GenOciManifestFromPkg()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, manifest[constants.DEFAULT_KCL_OCI_MANIFEST_NAME], "test_mod_with_metadata")
+	assert.Equal(t, manifest[constants.OCI_MANIFEST_TITLE], "test_mod_with_metadata")
+	assert.Equal(t, manifest[constants.OCI_MANIFEST_VERSION], "0.0.1")
+	assert.Equal(t, manifest[constants.OCI_MANIFEST_LICENSES], "Apache-2.0 OR MIT")
+	assert.Equal(t, manifest[constants.OCI_MANIFEST_AUTHORS], "KCL Authors <kcl@kcl-lang.io>, kpm maintainers")
+	assert.Equal(t, manifest[constants.OCI_MANIFEST_URL], "https://kcl-lang.io")
+	assert.Equal(t, manifest[constants.OCI_MANIFEST_SOURCE], "https://github.com/kcl-lang/kpm")
+	assert.Equal(t, manifest[constants.OCI_MANIFEST_DOCUMENTATION], "https://kcl-lang.io/docs")
+	assert.Equal(t, manifest[constants.DEFAULT_KCL_OCI_MANIFEST_KEYWORDS], "kcl,kubernetes")
+	assert.Equal(t, manifest[constants.DEFAULT_KCL_OCI_MANIFEST_README], "README.md")
+
+	// Both the checksum before 'h1:' and the 'h1:' checksum are written,
+	// the clients before 'h1:' still verify the package against the checksum before 'h1:'.
+	legacySum, err := utils.HashDir(getTestDir("test_mod_with_metadata"))
+	assert.Equal(t, err, nil)
+	sum, err := utils.HashDirH1(getTestDir("test_mod_with_metadata"))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, manifest[constants.DEFAULT_KCL_OCI_MANIFEST_SUM], legacySum)
+	assert.Equal(t, manifest[constants.DEFAULT_KCL_OCI_MANIFEST_SUM_H1], sum)
+	gotSum, ok := SumFromManifest(manifest)
+	assert.Equal(t, ok, true)
+	assert.Equal(t, gotSum, sum)
+	delete(manifest, constants.DEFAULT_KCL_OCI_MANIFEST_SUM_H1)
+	gotSum, ok = SumFromManifest(manifest)
+	assert.Equal(t, ok, true)
+	assert.Equal(t, gotSum, legacySum)
+
+	// The metadata not set is not written into the annotations.
+	kclPkg.ModFile.Pkg.License = ""
+	manifest, err = kclPkg.GenOciManifestFromPkg()
+	assert.Equal(t, err, nil)
+	_, ok = manifest[constants.OCI_MANIFEST_LICENSES]
+	assert.Equal(t, ok, false)
+	_, ok = manifest[constants.DEFAULT_KCL_OCI_MANIFEST_DEPS]
+	assert.Equal(t, ok, false)
+
+	// The direct dependencies are written into the annotations.
+	kclPkg.ModFile.Deps.Set("helloworld", Dependency{Name: "helloworld", Version: "0.1.2"})
+	kclPkg.ModFile.Deps.Set("local_dep", Dependency{Name: "local_dep"})
+	manifest, err = kclPkg.GenOciManifestFromPkg()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, manifest[constants.DEFAULT_KCL_OCI_MANIFEST_DEPS], "helloworld@0.1.2,local_dep")
+}
+
+func TestGenOciConfigFromPkg(t *testing.T) {
+	kclPkg, err := LoadKclPkg(getTestDir("test_mod_with_metadata"))
+	assert.Equal(t, err, nil)
+
+	content, err := kclPkg.GenOciConfigFromPkg()
+	assert.Equal(t, err, nil)
+	config, err := oci.ParsePackageConfig(content)
+	assert.Equal(t, err, nil)
+
+	modContent, err := os.ReadFile(filepath.Join(getTestDir("test_mod_with_metadata"), "kcl.mod"))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, config.ModFile, string(modContent))
+	// The package without kcl.mod.lock has no lock in the config.
+	assert.Equal(t, config.ModLockFile, "")
+
+	modFile, deps, err := ParseModFile(config.ModFile, config.ModLockFile)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, modFile.Pkg.Name, "test_mod_with_metadata")
+	assert.Equal(t, modFile.Pkg.License, "Apache-2.0 OR MIT")
+	assert.Equal(t, deps.Deps.Len(), 0)
+}
+
 func TestGetPkgName(t *testing.T) {
 	kclPkg := KclPkg{
 		ModFile: ModFile{
@@ -155,3 +229,19 @@ func TestLoadPkgFromLock(t *testing.T) {
 	assert.Equal(t, kpkg.Dependencies.Deps.GetOrDefault("helloworld", TestPkgDependency).Source.Oci.Repo, "kcl-lang/helloworld")
 	assert.Equal(t, kpkg.Dependencies.Deps.GetOrDefault("helloworld", TestPkgDependency).Source.Oci.Tag, "0.1.2")
 }
+
+func TestFindWorkspaceRoot(t *testing.T) {
+	root := t.TempDir()
+	pkgPath := filepath.Join(root, "pkgs", "a")
+	assert.NoError(t, os.MkdirAll(pkgPath, 0755))
+
+	// The directory named 'kcl.work' is not a workspace.
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "pkgs", constants.KCL_WORK), 0755))
+	_, ok := FindWorkspaceRoot(pkgPath)
+	assert.False(t, ok)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(root, constants.KCL_WORK), []byte("[workspace]\n"), 0644))
+	got, ok := FindWorkspaceRoot(pkgPath)
+	assert.True(t, ok)
+	assert.Equal(t, root, got)
+}
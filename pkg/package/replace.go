@@ -0,0 +1,167 @@
+// Copyright 2024 The KCL Authors. All rights reserved.
+//
+// The [replace] section in kcl.mod redirects a dependency to another source,
+// e.g. a local path, a fork on git or a mirror oci repo.
+// The replacements are only honored in the package being built,
+// and they apply to the indirect dependencies as well.
+//
+// In kcl.mod, the replacement looks like:
+//
+// [replace]
+// helloworld = { path = "../helloworld" }
+// "k8s@1.28" = { oci = "oci://ghcr.io/kcl-lang-mirror/k8s", tag = "1.28" }
+//
+// In kcl.mod.lock, the original dependency is recorded with the replacement:
+//
+// [dependencies.helloworld]
+// name = "helloworld"
+// full_name = "helloworld_0.1.2"
+// version = "0.1.2"
+// replace = "../helloworld"
+package pkg
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"kcl-lang.io/kpm/pkg/downloader"
+)
+
+// REPLACE_VERSION_SEP separates the name and the version of the dependency in the key of a replacement.
+const REPLACE_VERSION_SEP = "@"
+
+// Replace is a replacement in the [replace] section of kcl.mod.
+// It redirects the dependency 'Name' at the version 'Version' to 'Source'.
+// If 'Version' is empty, all the versions of the dependency are redirected.
+type Replace struct {
+	Name    string
+	Version string
+	// The absolute path of the replacement from the local path.
+	LocalFullPath string
+	downloader.Source
+}
+
+// Replaces is the [replace] section of kcl.mod.
+type Replaces []Replace
+
+// Key returns the key of the replacement in kcl.mod.
+func (r *Replace) Key() string {
+	if len(r.Version) == 0 {
+		return r.Name
+	}
+	return r.Name + REPLACE_VERSION_SEP + r.Version
+}
+
+// String returns the replacement source in the url format, e.g. 'oci://ghcr.io/kcl-lang-mirror/k8s?tag=1.28'.
+func (r *Replace) String() string {
+	source := downloader.Source{
+		Git:   r.Git,
+		Oci:   r.Oci,
+		Local: r.Local,
+	}
+	sourceStr, err := source.ToString()
+	if err != nil {
+		return ""
+	}
+	return sourceStr
+}
+
+// ParseReplaceKey parses the key of a replacement in kcl.mod into the name and the version.
+func ParseReplaceKey(key string) (string, string, error) {
+	name, version, _ := strings.Cut(key, REPLACE_VERSION_SEP)
+	if len(name) == 0 {
+		return "", "", fmt.Errorf("invalid replacement '%s', expected '<name>' or '<name>@<version>'", key)
+	}
+	return name, version, nil
+}
+
+// Find returns the replacement for the dependency 'name' at the version 'version'.
+// The replacement for the version takes precedence over the replacement for all the versions.
+func (replaces Replaces) Find(name, version string) (*Replace, bool) {
+	var found *Replace
+	for i := range replaces {
+		r := &replaces[i]
+		if r.Name != name {
+			continue
+		}
+		if len(r.Version) == 0 {
+			found = r
+		} else if r.Version == version {
+			return r, true
+		}
+	}
+	return found, found != nil
+}
+
+// ReplaceDep returns a copy of the dependency fetched from the replacement if the dependency is redirected.
+// The version of the dependency is kept. If the tag of the replacement from git or oci is not specified,
+// the version of the dependency is used as the tag.
+func (replaces Replaces) ReplaceDep(dep *Dependency) (*Dependency, bool) {
+	r, ok := replaces.Find(dep.Name, dep.Version)
+	if !ok {
+		return nil, false
+	}
+
+	replacedDep := *dep
+	replacedDep.Sum = ""
+	replacedDep.LocalFullPath = ""
+	replacedDep.Source = downloader.Source{}
+	if r.Git != nil {
+		git := *r.Git
+		if len(git.Tag) == 0 && len(git.Commit) == 0 && len(git.Branch) == 0 {
+			git.Tag = dep.Version
+		}
+		replacedDep.Source.Git = &git
+	} else if r.Oci != nil {
+		oci := *r.Oci
+		if len(oci.Tag) == 0 {
+			oci.Tag = dep.Version
+		}
+		replacedDep.Source.Oci = &oci
+	} else if r.Local != nil {
+		replacedDep.Source.Local = &downloader.Local{
+			Path: r.LocalFullPath,
+		}
+		replacedDep.LocalFullPath = r.LocalFullPath
+	}
+	replacedDep.Replace = r.String()
+	replacedDep.FullName = replacedDep.GenDepFullName()
+	return &replacedDep, true
+}
+
+// RecordReplace returns a copy of the dependency recording the replacement it is resolved from.
+// The name, version and source of the dependency are kept,
+// and the checksum and the local path are the ones of the replacement.
+func (dep *Dependency) RecordReplace(replacedDep *Dependency) Dependency {
+	recordedDep := *dep
+	recordedDep.Replace = replacedDep.Replace
+	recordedDep.Sum = replacedDep.Sum
+	recordedDep.LocalFullPath = replacedDep.LocalFullPath
+	return recordedDep
+}
+
+// IsReplaced returns true if the dependency is resolved from a replacement.
+func (dep *Dependency) IsReplaced() bool {
+	return len(dep.Replace) != 0
+}
+
+// convertReplacesLocalPathToAbsPath will transform the local path of the replacements to the absolute path from `rootPath`.
+func convertReplacesLocalPathToAbsPath(replaces Replaces, rootPath string) error {
+	for i := range replaces {
+		r := &replaces[i]
+		if r.Local == nil {
+			continue
+		}
+		if filepath.IsAbs(r.Local.Path) {
+			r.LocalFullPath = r.Local.Path
+			continue
+		}
+		localFullPath, err := filepath.Abs(filepath.Join(rootPath, r.Local.Path))
+		if err != nil {
+			return fmt.Errorf("failed to get the absolute path of the replacement %s: %w", r.Key(), err)
+		}
+		r.LocalFullPath = localFullPath
+	}
+	return nil
+}
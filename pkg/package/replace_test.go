@@ -0,0 +1,63 @@
+package pkg
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"kcl-lang.io/kpm/pkg/utils"
+)
+
+func TestUnMarshalTOMLWithReplace(t *testing.T) {
+	testPath := getTestDir("test_replace")
+	modfile, err := LoadModFile(testPath)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(modfile.Replaces), 2)
+
+	helloworld := modfile.Replaces[0]
+	assert.Equal(t, helloworld.Name, "helloworld")
+	assert.Equal(t, helloworld.Version, "")
+	assert.Equal(t, helloworld.Local.Path, "../helloworld_fork")
+	assert.Equal(t, helloworld.LocalFullPath, filepath.Join(filepath.Dir(testPath), "helloworld_fork"))
+
+	k8s := modfile.Replaces[1]
+	assert.Equal(t, k8s.Key(), "k8s@1.28")
+	assert.Equal(t, k8s.Oci.Reg, "ghcr.io")
+	assert.Equal(t, k8s.Oci.Repo, "kcl-lang-mirror/k8s")
+
+	expected := `[replace]
+helloworld = { path = "../helloworld_fork" }
+"k8s@1.28" = { oci = "oci://ghcr.io/kcl-lang-mirror/k8s" }`
+	assert.Equal(t, utils.RmNewline(expected), utils.RmNewline(modfile.Replaces.MarshalTOML()))
+}
+
+func TestReplaceDep(t *testing.T) {
+	modfile, err := LoadModFile(getTestDir("test_replace"))
+	assert.Equal(t, err, nil)
+
+	k8s, ok := modfile.Deps.Get("k8s")
+	assert.Equal(t, ok, true)
+	replacedK8s, ok := modfile.Replaces.ReplaceDep(&k8s)
+	assert.Equal(t, ok, true)
+	assert.Equal(t, replacedK8s.Version, "1.28")
+	assert.Equal(t, replacedK8s.Oci.Repo, "kcl-lang-mirror/k8s")
+	assert.Equal(t, replacedK8s.Oci.Tag, "1.28")
+	assert.Equal(t, replacedK8s.Replace, "oci://ghcr.io/kcl-lang-mirror/k8s")
+
+	// The replacement at the version does not apply to the other versions.
+	otherK8s := k8s.WithVersion("1.29")
+	_, ok = modfile.Replaces.ReplaceDep(&otherK8s)
+	assert.Equal(t, ok, false)
+
+	// The original dependency is recorded with the replacement.
+	helloworld, ok := modfile.Deps.Get("helloworld")
+	assert.Equal(t, ok, true)
+	replacedHelloworld, ok := modfile.Replaces.ReplaceDep(&helloworld)
+	assert.Equal(t, ok, true)
+	assert.Equal(t, replacedHelloworld.IsFromLocal(), true)
+	recorded := helloworld.RecordReplace(replacedHelloworld)
+	assert.Equal(t, recorded.Source, helloworld.Source)
+	assert.Equal(t, recorded.Version, "0.1.2")
+	assert.Equal(t, recorded.Replace, "../helloworld_fork")
+	assert.Equal(t, recorded.LocalFullPath, replacedHelloworld.LocalFullPath)
+}
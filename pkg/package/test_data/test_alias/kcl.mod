@@ -0,0 +1,8 @@
+[package]
+name = "test_alias"
+edition = "v0.10.0"
+version = "0.0.1"
+
+[dependencies]
+k8s = "1.30"
+k8s_old = { package = "k8s", version = "1.27" }
@@ -0,0 +1,10 @@
+[package]
+name = "test_dev_deps"
+edition = "v0.10.0"
+version = "0.0.1"
+
+[dependencies]
+helloworld = "0.1.2"
+
+[dev-dependencies]
+dev_0 = { path = "../dev_0" }
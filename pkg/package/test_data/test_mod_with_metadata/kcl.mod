@@ -0,0 +1,12 @@
+[package]
+name = "test_mod_with_metadata"
+edition = "0.0.1"
+version = "0.0.1"
+description = "This is a test module with the metadata"
+license = "Apache-2.0 OR MIT"
+authors = ["KCL Authors <kcl@kcl-lang.io>", "kpm maintainers"]
+homepage = "https://kcl-lang.io"
+repository = "https://github.com/kcl-lang/kpm"
+documentation = "https://kcl-lang.io/docs"
+keywords = ["kcl", "kubernetes"]
+readme = "README.md"
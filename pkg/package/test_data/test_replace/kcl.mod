@@ -0,0 +1,12 @@
+[package]
+name = "test_replace"
+edition = "v0.10.0"
+version = "0.0.1"
+
+[dependencies]
+helloworld = "0.1.2"
+k8s = "1.28"
+
+[replace]
+helloworld = { path = "../helloworld_fork" }
+"k8s@1.28" = { oci = "oci://ghcr.io/kcl-lang-mirror/k8s" }
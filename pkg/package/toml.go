@@ -43,6 +43,16 @@ func (mod *ModFile) MarshalTOML() string {
 		sb.WriteString(NEWLINE)
 		sb.WriteString(dependencies)
 	}
+	devDependencies := mod.DevDependencies.MarshalDevTOML()
+	if devDependencies != "" {
+		sb.WriteString(NEWLINE)
+		sb.WriteString(devDependencies)
+	}
+	replaces := mod.Replaces.MarshalTOML()
+	if replaces != "" {
+		sb.WriteString(NEWLINE)
+		sb.WriteString(replaces)
+	}
 	profiles := mod.Profiles.MarshalTOML()
 	if profiles != "" {
 		sb.WriteString(NEWLINE)
@@ -66,11 +76,21 @@ func (pkg *Package) MarshalTOML() string {
 }
 
 const DEPS_PATTERN = "[dependencies]"
+const DEV_DEPS_PATTERN = "[dev-dependencies]"
 
 func (dep *Dependencies) MarshalTOML() string {
+	return dep.marshalTOMLWithPattern(DEPS_PATTERN)
+}
+
+// MarshalDevTOML marshals the dependencies into the dev-dependencies section of kcl.mod.
+func (dep *Dependencies) MarshalDevTOML() string {
+	return dep.marshalTOMLWithPattern(DEV_DEPS_PATTERN)
+}
+
+func (dep *Dependencies) marshalTOMLWithPattern(pattern string) string {
 	var sb strings.Builder
 	if dep.Deps != nil && dep.Deps.Len() != 0 {
-		sb.WriteString(DEPS_PATTERN)
+		sb.WriteString(pattern)
 		for _, depKeys := range dep.Deps.Keys() {
 			dep, ok := dep.Deps.Get(depKeys)
 			if !ok {
@@ -89,7 +109,9 @@ const DEP_PATTERN = "%s = %s"
 func (dep *Dependency) MarshalTOML() string {
 	var sb strings.Builder
 
-	if dep.Source.ModSpec != nil && dep.Source.ModSpec.Version != "" && dep.Source.ModSpec.Name != "" {
+	if dep.IsAliased() {
+		sb.WriteString(fmt.Sprintf(DEP_PATTERN, dep.Name, dep.Source.MarshalAliasedTOML()))
+	} else if dep.Source.ModSpec != nil && dep.Source.ModSpec.Version != "" && dep.Source.ModSpec.Name != "" {
 		sb.WriteString(dep.Source.MarshalTOML())
 	} else {
 		sb.WriteString(fmt.Sprintf(DEP_PATTERN, dep.Name, dep.Source.MarshalTOML()))
@@ -98,6 +120,34 @@ func (dep *Dependency) MarshalTOML() string {
 	return sb.String()
 }
 
+const REPLACE_PATTERN = "[replace]"
+
+func (replaces Replaces) MarshalTOML() string {
+	var sb strings.Builder
+	if len(replaces) != 0 {
+		sb.WriteString(REPLACE_PATTERN)
+		for _, r := range replaces {
+			sb.WriteString(NEWLINE)
+			sb.WriteString(r.MarshalTOML())
+		}
+		sb.WriteString(NEWLINE)
+	}
+	return sb.String()
+}
+
+func (r *Replace) MarshalTOML() string {
+	key := r.Key()
+	if len(r.Version) != 0 {
+		key = fmt.Sprintf("%q", key)
+	}
+	source := downloader.Source{
+		Git:   r.Git,
+		Oci:   r.Oci,
+		Local: r.Local,
+	}
+	return fmt.Sprintf(DEP_PATTERN, key, source.MarshalTOML())
+}
+
 const PROFILE_PATTERN = "[profile]"
 
 func (p *Profile) MarshalTOML() string {
@@ -117,6 +167,8 @@ func (p *Profile) MarshalTOML() string {
 const (
 	PACKAGE_FLAG  = "package"
 	DEPS_FLAG     = "dependencies"
+	DEV_DEPS_FLAG = "dev-dependencies"
+	REPLACE_FLAG  = "replace"
 	PROFILES_FLAG = "profile"
 )
 
@@ -144,6 +196,28 @@ func (mod *ModFile) UnmarshalTOML(data interface{}) error {
 			return err
 		}
 	}
+	devDeps := Dependencies{
+		Deps: orderedmap.NewOrderedMap[string, Dependency](),
+	}
+	if v, ok := meta[DEV_DEPS_FLAG]; ok {
+		err := devDeps.UnmarshalModTOML(v)
+		if err != nil {
+			return err
+		}
+		for _, name := range devDeps.Deps.Keys() {
+			if _, ok := deps.Deps.Get(name); ok {
+				return fmt.Errorf("dependency '%s' is in both [%s] and [%s]", name, DEPS_FLAG, DEV_DEPS_FLAG)
+			}
+		}
+	}
+	mod.DevDependencies = devDeps
+
+	if v, ok := meta[REPLACE_FLAG]; ok {
+		err := deps.Replaces.UnmarshalModTOML(v)
+		if err != nil {
+			return err
+		}
+	}
 	mod.Dependencies = deps
 
 	if v, ok := meta[PROFILES_FLAG]; ok {
@@ -162,12 +236,19 @@ func (mod *ModFile) UnmarshalTOML(data interface{}) error {
 }
 
 const (
-	NAME_FLAG        = "name"
-	EDITION_FLAG     = "edition"
-	VERSION_FLAG     = "version"
-	DESCRIPTION_FLAG = "description"
-	INCLUDE_FLAG     = "include"
-	EXCLUDE_FLAG     = "exclude"
+	NAME_FLAG          = "name"
+	EDITION_FLAG       = "edition"
+	VERSION_FLAG       = "version"
+	DESCRIPTION_FLAG   = "description"
+	LICENSE_FLAG       = "license"
+	AUTHORS_FLAG       = "authors"
+	HOMEPAGE_FLAG      = "homepage"
+	REPOSITORY_FLAG    = "repository"
+	DOCUMENTATION_FLAG = "documentation"
+	KEYWORDS_FLAG      = "keywords"
+	README_FLAG        = "readme"
+	INCLUDE_FLAG       = "include"
+	EXCLUDE_FLAG       = "exclude"
 )
 
 func (pkg *Package) UnmarshalTOML(data interface{}) error {
@@ -192,6 +273,26 @@ func (pkg *Package) UnmarshalTOML(data interface{}) error {
 		pkg.Description = v
 	}
 
+	if v, ok := meta[LICENSE_FLAG].(string); ok {
+		pkg.License = v
+	}
+
+	if v, ok := meta[HOMEPAGE_FLAG].(string); ok {
+		pkg.Homepage = v
+	}
+
+	if v, ok := meta[REPOSITORY_FLAG].(string); ok {
+		pkg.Repository = v
+	}
+
+	if v, ok := meta[DOCUMENTATION_FLAG].(string); ok {
+		pkg.Documentation = v
+	}
+
+	if v, ok := meta[README_FLAG].(string); ok {
+		pkg.Readme = v
+	}
+
 	convertToStringArray := func(v interface{}) []string {
 		var arr []string
 		for _, item := range v.([]interface{}) {
@@ -200,6 +301,14 @@ func (pkg *Package) UnmarshalTOML(data interface{}) error {
 		return arr
 	}
 
+	if v, ok := meta[AUTHORS_FLAG].([]interface{}); ok {
+		pkg.Authors = convertToStringArray(v)
+	}
+
+	if v, ok := meta[KEYWORDS_FLAG].([]interface{}); ok {
+		pkg.Keywords = convertToStringArray(v)
+	}
+
 	if v, ok := meta[INCLUDE_FLAG].([]interface{}); ok {
 		pkg.Include = convertToStringArray(v)
 	}
@@ -268,6 +377,40 @@ func (dep *Dependency) UnmarshalModTOML(data interface{}) error {
 	return nil
 }
 
+func (replaces *Replaces) UnmarshalModTOML(data interface{}) error {
+	meta, ok := data.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("expected map[string]interface{}, got %T", data)
+	}
+
+	var keys []string
+	for k := range meta {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		name, version, err := ParseReplaceKey(k)
+		if err != nil {
+			return err
+		}
+		r := Replace{
+			Name:    name,
+			Version: version,
+		}
+		err = r.Source.UnmarshalModTOML(meta[k])
+		if err != nil {
+			return err
+		}
+		if r.Git == nil && r.Oci == nil && r.Local == nil {
+			return fmt.Errorf("invalid replacement '%s', expected a local path, git or oci source", k)
+		}
+		r.ModSpec = nil
+		*replaces = append(*replaces, r)
+	}
+
+	return nil
+}
+
 type DependenciesUI struct {
 	Deps map[string]Dependency `json:"packages" toml:"dependencies,omitempty"`
 }
@@ -279,15 +422,21 @@ func (dep *Dependencies) MarshalLockTOML() (string, error) {
 		if !ok {
 			break
 		}
+		// The local copy of the workspace member is not the package locked from the registry.
+		if dep.FromWorkspace {
+			continue
+		}
+		dep.AddDependents()
 		marshaledDeps[depKey] = dep
 	}
 
-	lockDepdenciesUI := DependenciesUI{
-		Deps: marshaledDeps,
+	lockFile := LockFile{
+		Version: LOCK_FILE_VERSION,
+		Deps:    marshaledDeps,
 	}
 
 	buf := new(bytes.Buffer)
-	if err := toml.NewEncoder(buf).Encode(&lockDepdenciesUI); err != nil {
+	if err := toml.NewEncoder(buf).Encode(&lockFile); err != nil {
 		return "", reporter.NewErrorEvent(reporter.FailedLoadKclModLock, err, "failed to lock dependencies version")
 	}
 	return buf.String(), nil
@@ -298,22 +447,28 @@ func (dep *Dependencies) UnmarshalLockTOML(data string) error {
 		dep.Deps = orderedmap.NewOrderedMap[string, Dependency]()
 	}
 
-	lockDepdenciesUI := DependenciesUI{
+	lockFile := LockFile{
 		Deps: make(map[string]Dependency),
 	}
 
-	if _, err := toml.NewDecoder(strings.NewReader(data)).Decode(&lockDepdenciesUI); err != nil {
+	if _, err := toml.NewDecoder(strings.NewReader(data)).Decode(&lockFile); err != nil {
+		return reporter.NewErrorEvent(reporter.FailedLoadKclModLock, err, "failed to load kcl.mod.lock")
+	}
+
+	// The lock file version 1 shares the fields of the dependencies with the current version,
+	// and it is migrated when the lock file is written again.
+	if err := checkLockFileVersion(lockFile.Version); err != nil {
 		return reporter.NewErrorEvent(reporter.FailedLoadKclModLock, err, "failed to load kcl.mod.lock")
 	}
 
 	var keys []string
-	for k := range lockDepdenciesUI.Deps {
+	for k := range lockFile.Deps {
 		keys = append(keys, k)
 	}
 	sort.Strings(keys)
 
 	for _, k := range keys {
-		dep.Deps.Set(k, lockDepdenciesUI.Deps[k])
+		dep.Deps.Set(k, lockFile.Deps[k])
 	}
 
 	return nil
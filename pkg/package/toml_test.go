@@ -26,7 +26,7 @@ func TestMarshalTOML(t *testing.T) {
 			Exclude: []string{"target/", ".git/", "*.log"},
 		},
 		Dependencies: Dependencies{
-			orderedmap.NewOrderedMap[string, Dependency](),
+			Deps: orderedmap.NewOrderedMap[string, Dependency](),
 		},
 	}
 
@@ -113,21 +113,23 @@ func TestMarshalLockTOML(t *testing.T) {
 	}
 
 	ociDep := Dependency{
-		Name:     "MyOciKcl1",
-		FullName: "MyOciKcl1_0.0.1",
-		Version:  "0.0.1",
-		Sum:      "hjkasdahjksdasdhjk",
+		Name:       "MyOciKcl1",
+		FullName:   "MyOciKcl1_0.0.1",
+		Version:    "0.0.1",
+		Sum:        "hjkasdahjksdasdhjk",
+		Dependents: []string{"my_pkg", "MyKcl1", "my_pkg"},
 		Source: downloader.Source{
 			Oci: &downloader.Oci{
-				Reg:  "test_reg",
-				Repo: "test_repo",
-				Tag:  "0.0.1",
+				Reg:    "test_reg",
+				Repo:   "test_repo",
+				Tag:    "0.0.1",
+				Digest: "sha256:0a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f9",
 			},
 		},
 	}
 
 	deps := Dependencies{
-		orderedmap.NewOrderedMap[string, Dependency](),
+		Deps: orderedmap.NewOrderedMap[string, Dependency](),
 	}
 
 	deps.Deps.Set(dep.Name, dep)
@@ -140,7 +142,7 @@ func TestMarshalLockTOML(t *testing.T) {
 
 func TestUnmarshalLockTOML(t *testing.T) {
 	deps := Dependencies{
-		orderedmap.NewOrderedMap[string, Dependency](),
+		Deps: orderedmap.NewOrderedMap[string, Dependency](),
 	}
 
 	expected_data, _ := os.ReadFile(filepath.Join(getTestDir(testTomlDir), "expected_lock.toml"))
@@ -166,6 +168,28 @@ func TestUnmarshalLockTOML(t *testing.T) {
 	assert.Equal(t, deps.Deps.GetOrDefault("MyOciKcl1", TestPkgDependency).Source.Oci.Reg, "test_reg")
 	assert.Equal(t, deps.Deps.GetOrDefault("MyOciKcl1", TestPkgDependency).Source.Oci.Repo, "test_repo")
 	assert.Equal(t, deps.Deps.GetOrDefault("MyOciKcl1", TestPkgDependency).Source.Oci.Tag, "0.0.1")
+	assert.Equal(t, deps.Deps.GetOrDefault("MyOciKcl1", TestPkgDependency).Source.Oci.Digest, "sha256:0a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f9")
+	assert.Equal(t, deps.Deps.GetOrDefault("MyOciKcl1", TestPkgDependency).Dependents, []string{"MyKcl1", "my_pkg"})
+}
+
+func TestMigrateLockTOMLV1(t *testing.T) {
+	deps := Dependencies{
+		Deps: orderedmap.NewOrderedMap[string, Dependency](),
+	}
+
+	v1Data, err := os.ReadFile(filepath.Join(getTestDir(testTomlDir), "expected_lock_v1.toml"))
+	assert.Equal(t, err, nil)
+	err = deps.UnmarshalLockTOML(string(v1Data))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, deps.Deps.Len(), 2)
+	assert.Equal(t, deps.Deps.GetOrDefault("MyOciKcl1", TestPkgDependency).Source.Oci.Tag, "0.0.1")
+
+	tomlStr, err := deps.MarshalLockTOML()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, strings.HasPrefix(tomlStr, "version = 2"), true)
+
+	err = deps.UnmarshalLockTOML("version = 3")
+	assert.ErrorContains(t, err, "the version 3 of kcl.mod.lock is not supported")
 }
 
 func TestUnMarshalTOMLWithProfile(t *testing.T) {
@@ -233,7 +257,7 @@ func TestMarshalOciUrl(t *testing.T) {
 			Version: "0.0.1",
 		},
 		Dependencies: Dependencies{
-			orderedmap.NewOrderedMap[string, Dependency](),
+			Deps: orderedmap.NewOrderedMap[string, Dependency](),
 		},
 	}
 
@@ -307,7 +331,7 @@ func TestInitEmptyPkg(t *testing.T) {
 			Exclude: []string{"target/", ".git/", "*.log"},
 		},
 		Dependencies: Dependencies{
-			orderedmap.NewOrderedMap[string, Dependency](),
+			Deps: orderedmap.NewOrderedMap[string, Dependency](),
 		},
 	}
 
@@ -361,3 +385,91 @@ func TestInitEmptyPkg(t *testing.T) {
 	fmt.Printf("modfile: '%q'\n", got_data)
 	assert.Equal(t, expected_toml, got_data)
 }
+
+func TestUnMarshalTOMLWithDevDeps(t *testing.T) {
+	modfile, err := LoadModFile(getTestDir("test_dev_deps"))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, modfile.Deps.Len(), 1)
+	assert.Equal(t, modfile.DevDependencies.Deps.Len(), 1)
+	assert.Equal(t, modfile.IsDevDep("dev_0"), true)
+	assert.Equal(t, modfile.IsDevDep("helloworld"), false)
+	assert.Equal(t, modfile.DepsWithDevDeps().Deps.Keys(), []string{"helloworld", "dev_0"})
+
+	expected := `[dev-dependencies]
+dev_0 = { path = "../dev_0" }`
+	assert.Equal(t, utils.RmNewline(expected), utils.RmNewline(modfile.DevDependencies.MarshalDevTOML()))
+
+	_, err = LoadModFile(getTestDir("test_dev_deps_conflict"))
+	assert.ErrorContains(t, err, "dependency 'helloworld' is in both [dependencies] and [dev-dependencies]")
+}
+
+func TestUnMarshalTOMLWithAlias(t *testing.T) {
+	modfile, err := LoadModFile(getTestDir("test_alias"))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, modfile.Deps.Len(), 2)
+
+	k8s, ok := modfile.Deps.Get("k8s")
+	assert.Equal(t, ok, true)
+	assert.Equal(t, k8s.IsAliased(), false)
+	assert.Equal(t, k8s.PkgName(), "k8s")
+	assert.Equal(t, k8s.Version, "1.30")
+
+	k8sOld, ok := modfile.Deps.Get("k8s_old")
+	assert.Equal(t, ok, true)
+	assert.Equal(t, k8sOld.IsAliased(), true)
+	assert.Equal(t, k8sOld.PkgName(), "k8s")
+	assert.Equal(t, k8sOld.Version, "1.27")
+	assert.Equal(t, k8sOld.Oci.Repo, "kcl-lang/k8s")
+	assert.Equal(t, k8sOld.GenPathSuffix(), "k8s_old_1.27")
+
+	rawModfile := ModFile{}
+	err = rawModfile.LoadModFile(filepath.Join(getTestDir("test_alias"), MOD_FILE))
+	assert.Equal(t, err, nil)
+	expected := `[dependencies]
+k8s = "1.30"
+k8s_old = { package = "k8s", version = "1.27" }`
+	assert.Equal(t, utils.RmNewline(expected), utils.RmNewline(rawModfile.Dependencies.MarshalTOML()))
+
+	aliasedDep := k8s.WithAlias("k8s_new")
+	assert.Equal(t, aliasedDep.Name, "k8s_new")
+	assert.Equal(t, aliasedDep.PkgName(), "k8s")
+	assert.Equal(t, k8s.Name, "k8s")
+}
+
+func TestLockTOMLWithOciDigest(t *testing.T) {
+	testDigest := "sha256:3b2e0cbd1c4e2d8e6b2f1f6d4b2e9f5c1a7d8e3f4a5b6c7d8e9f0a1b2c3d4e5f"
+	deps := Dependencies{Deps: orderedmap.NewOrderedMap[string, Dependency]()}
+	deps.Deps.Set("tagged", Dependency{
+		Name:     "tagged",
+		FullName: "tagged_0.1.0",
+		Version:  "0.1.0",
+		Source: downloader.Source{
+			Oci: &downloader.Oci{Reg: "ghcr.io", Repo: "kcl-lang/tagged", Tag: "0.1.0", Digest: testDigest, Pinned: true},
+		},
+	})
+	deps.Deps.Set("digest_only", Dependency{
+		Name:     "digest_only",
+		FullName: "digest_only_0.1.0",
+		Version:  "0.1.0",
+		Source: downloader.Source{
+			Oci: &downloader.Oci{Reg: "ghcr.io", Repo: "kcl-lang/digest_only", Digest: testDigest, Pinned: true},
+		},
+	})
+
+	// Only the digest is recorded in kcl.mod.lock, whether it is pinned comes from kcl.mod.
+	tomlStr, err := deps.MarshalLockTOML()
+	assert.Nil(t, err)
+	assert.Contains(t, tomlStr, "oci_digest = \""+testDigest+"\"")
+	assert.NotContains(t, tomlStr, "pinned")
+
+	lockDeps := Dependencies{}
+	assert.Nil(t, lockDeps.UnmarshalLockTOML(tomlStr))
+	tagged, ok := lockDeps.Deps.Get("tagged")
+	assert.True(t, ok)
+	assert.Equal(t, testDigest, tagged.Source.Oci.Digest)
+	assert.False(t, tagged.Source.Oci.IsPinned())
+	digestOnly, ok := lockDeps.Deps.Get("digest_only")
+	assert.True(t, ok)
+	assert.True(t, digestOnly.Source.Oci.IsPinned())
+	assert.Equal(t, testDigest, digestOnly.Source.Oci.Reference())
+}
@@ -0,0 +1,105 @@
+package pkg
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/BurntSushi/toml"
+	"kcl-lang.io/kpm/pkg/constants"
+	"kcl-lang.io/kpm/pkg/reporter"
+	"kcl-lang.io/kpm/pkg/utils"
+)
+
+// Workspace is a set of kcl packages developed together in one repository.
+// It is described by the file 'kcl.work' in the root directory of the workspace, e.g.
+//
+//	[workspace]
+//	members = ["pkgs/*", "tools/checker"]
+//
+// The members are the directories of the packages relative to the root directory,
+// and the glob patterns are supported.
+type Workspace struct {
+	// HomePath is the root directory of the workspace.
+	HomePath string
+	// Members is the directories or the glob patterns of the member packages.
+	Members []string `toml:"members,omitempty"`
+}
+
+type workFile struct {
+	Workspace Workspace `toml:"workspace"`
+}
+
+// LoadWorkspace will load the workspace from 'kcl.work' in the directory 'homePath'.
+func LoadWorkspace(homePath string) (*Workspace, error) {
+	workPath := filepath.Join(homePath, constants.KCL_WORK)
+	data, err := os.ReadFile(workPath)
+	if err != nil {
+		return nil, reporter.NewErrorEvent(reporter.FailedLoadKclWork, err, fmt.Sprintf("failed to load '%s'", workPath))
+	}
+
+	work := workFile{}
+	err = toml.Unmarshal(data, &work)
+	if err != nil {
+		return nil, reporter.NewErrorEvent(reporter.FailedLoadKclWork, err, fmt.Sprintf("failed to parse '%s'", workPath))
+	}
+	work.Workspace.HomePath = homePath
+
+	return &work.Workspace, nil
+}
+
+// FindWorkspaceRoot will find the root directory of the workspace which contains 'path',
+// by searching 'kcl.work' from 'path' up to the root of the file system.
+// It returns false if 'path' is not in any workspace.
+func FindWorkspaceRoot(path string) (string, bool) {
+	dir, err := filepath.Abs(path)
+	if err != nil {
+		return "", false
+	}
+	for {
+		if info, err := os.Stat(filepath.Join(dir, constants.KCL_WORK)); err == nil && !info.IsDir() {
+			return dir, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// MemberPaths returns the absolute paths of the member packages in the workspace, sorted by path.
+// The glob patterns are expanded to the directories with 'kcl.mod'.
+func (ws *Workspace) MemberPaths() ([]string, error) {
+	seen := make(map[string]struct{})
+	var paths []string
+	for _, member := range ws.Members {
+		pattern := member
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(ws.HomePath, pattern)
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, reporter.NewErrorEvent(reporter.FailedLoadKclWork, err, fmt.Sprintf("invalid workspace member '%s'", member))
+		}
+		if len(matches) == 0 {
+			return nil, reporter.NewErrorEvent(
+				reporter.FailedLoadKclWork,
+				fmt.Errorf("workspace member '%s' not found in '%s'", member, ws.HomePath),
+			)
+		}
+		for _, match := range matches {
+			if !utils.DirExists(filepath.Join(match, constants.KCL_MOD)) {
+				continue
+			}
+			if _, ok := seen[match]; ok {
+				continue
+			}
+			seen[match] = struct{}{}
+			paths = append(paths, match)
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
@@ -0,0 +1,269 @@
+// Copyright 2024 The KCL Authors. All rights reserved.
+//
+// Package provenance generates and verifies the build provenance of the kcl packages published in the oci registry.
+//
+// The provenance is an in-toto statement with the SLSA provenance v1 predicate.
+// The subjects of the statement are the manifest of the package and the package tar,
+// and the predicate records the source git commit of the package, the kpm version, the edition
+// and the dependencies resolved in kcl.mod.lock.
+// The statement is attached to the package as an oci referrer when the package is pushed.
+package provenance
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// The artifact type of the provenance referring to the package.
+	PROVENANCE_ARTIFACT_TYPE = "application/vnd.in-toto+json"
+	// The type of the in-toto statement.
+	STATEMENT_TYPE = "https://in-toto.io/Statement/v1"
+	// The type of the SLSA provenance predicate.
+	PREDICATE_TYPE = "https://slsa.dev/provenance/v1"
+	// The build type of the package built and pushed by kpm.
+	BUILD_TYPE = "https://kcl-lang.io/kpm/push/v1"
+	// The id of the builder.
+	BUILDER_ID = "https://kcl-lang.io/kpm"
+)
+
+// The algorithms of the digests in the statement.
+const (
+	DIGEST_SHA256     = "sha256"
+	DIGEST_GIT_COMMIT = "gitCommit"
+	// The checksum of the dependency in kcl.mod.lock.
+	DIGEST_KCL_SUM = "kclSum"
+)
+
+// Statement is the in-toto statement of the provenance.
+type Statement struct {
+	Type          string    `json:"_type"`
+	Subject       []Subject `json:"subject"`
+	PredicateType string    `json:"predicateType"`
+	Predicate     Predicate `json:"predicate"`
+}
+
+// Subject is the artifact the provenance is about.
+type Subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// Predicate is the SLSA provenance predicate.
+type Predicate struct {
+	BuildDefinition BuildDefinition `json:"buildDefinition"`
+	RunDetails      RunDetails      `json:"runDetails"`
+}
+
+type BuildDefinition struct {
+	BuildType            string               `json:"buildType"`
+	ExternalParameters   ExternalParameters   `json:"externalParameters"`
+	InternalParameters   InternalParameters   `json:"internalParameters"`
+	ResolvedDependencies []ResourceDescriptor `json:"resolvedDependencies,omitempty"`
+}
+
+// ExternalParameters are the package pushed and the oci reference it is pushed to.
+type ExternalParameters struct {
+	Package string `json:"package"`
+	Version string `json:"version"`
+	// Reference is the oci reference of the package, e.g. 'ghcr.io/kcl-lang/helloworld:0.1.2'.
+	Reference string `json:"reference"`
+	// Source is the git repo the package is built from, e.g. 'git+https://github.com/kcl-lang/modules@<commit>'.
+	Source string `json:"source,omitempty"`
+	// SourceDirty is true if the package is built from the source with the changes not committed.
+	SourceDirty bool `json:"sourceDirty,omitempty"`
+}
+
+// InternalParameters are the parameters of kpm building the package.
+type InternalParameters struct {
+	Edition string `json:"edition,omitempty"`
+}
+
+// ResourceDescriptor describes the source or the dependency of the package.
+type ResourceDescriptor struct {
+	Name   string            `json:"name,omitempty"`
+	Uri    string            `json:"uri,omitempty"`
+	Digest map[string]string `json:"digest,omitempty"`
+}
+
+type RunDetails struct {
+	Builder  Builder        `json:"builder"`
+	Metadata *BuildMetadata `json:"metadata,omitempty"`
+}
+
+type Builder struct {
+	Id      string            `json:"id"`
+	Version map[string]string `json:"version,omitempty"`
+}
+
+type BuildMetadata struct {
+	StartedOn  string `json:"startedOn,omitempty"`
+	FinishedOn string `json:"finishedOn,omitempty"`
+}
+
+// BuildInfo is the information of the package pushed to generate the provenance.
+type BuildInfo struct {
+	Name    string
+	Version string
+	Edition string
+	// Reference is the oci reference of the package, e.g. 'ghcr.io/kcl-lang/helloworld:0.1.2'.
+	Reference string
+	// ManifestDigest is the digest of the manifest of the package, e.g. 'sha256:...'.
+	ManifestDigest string
+	// TarName and TarDigest are the file name and the digest of the package tar.
+	TarName   string
+	TarDigest string
+	// SourceUrl and SourceCommit are the git repo and the commit the package is built from.
+	// They are empty if the package is not in a git repo.
+	SourceUrl    string
+	SourceCommit string
+	// SourceDirty is true if the package has the changes not committed to 'SourceCommit'.
+	SourceDirty  bool
+	KpmVersion   string
+	Dependencies []ResourceDescriptor
+	StartedOn    time.Time
+	FinishedOn   time.Time
+}
+
+// NewStatement returns the provenance statement of the package.
+func NewStatement(info *BuildInfo) *Statement {
+	statement := &Statement{
+		Type: STATEMENT_TYPE,
+		Subject: []Subject{
+			{Name: info.Reference, Digest: DigestSet(info.ManifestDigest)},
+			{Name: info.TarName, Digest: DigestSet(info.TarDigest)},
+		},
+		PredicateType: PREDICATE_TYPE,
+		Predicate: Predicate{
+			BuildDefinition: BuildDefinition{
+				BuildType: BUILD_TYPE,
+				ExternalParameters: ExternalParameters{
+					Package:   info.Name,
+					Version:   info.Version,
+					Reference: info.Reference,
+				},
+				InternalParameters: InternalParameters{
+					Edition: info.Edition,
+				},
+			},
+			RunDetails: RunDetails{
+				Builder: Builder{
+					Id:      BUILDER_ID,
+					Version: map[string]string{"kpm": info.KpmVersion},
+				},
+			},
+		},
+	}
+
+	if len(info.SourceCommit) != 0 {
+		source := ResourceDescriptor{
+			Name:   "source",
+			Uri:    "git+" + info.SourceUrl,
+			Digest: map[string]string{DIGEST_GIT_COMMIT: info.SourceCommit},
+		}
+		statement.Predicate.BuildDefinition.ExternalParameters.Source = fmt.Sprintf("%s@%s", source.Uri, info.SourceCommit)
+		statement.Predicate.BuildDefinition.ExternalParameters.SourceDirty = info.SourceDirty
+		statement.Predicate.BuildDefinition.ResolvedDependencies = append(statement.Predicate.BuildDefinition.ResolvedDependencies, source)
+	}
+	statement.Predicate.BuildDefinition.ResolvedDependencies = append(statement.Predicate.BuildDefinition.ResolvedDependencies, info.Dependencies...)
+
+	if !info.StartedOn.IsZero() || !info.FinishedOn.IsZero() {
+		statement.Predicate.RunDetails.Metadata = &BuildMetadata{
+			StartedOn:  formatTime(info.StartedOn),
+			FinishedOn: formatTime(info.FinishedOn),
+		}
+	}
+	return statement
+}
+
+// ParseStatement parses the provenance statement in json.
+func ParseStatement(content []byte) (*Statement, error) {
+	var statement Statement
+	if err := json.Unmarshal(content, &statement); err != nil {
+		return nil, fmt.Errorf("invalid provenance statement: %w", err)
+	}
+	return &statement, nil
+}
+
+// Verify checks whether the statement is the provenance of the manifest 'manifestDigest' and the package tar 'tarDigest'.
+func (s *Statement) Verify(manifestDigest, tarDigest string) error {
+	if s.Type != STATEMENT_TYPE {
+		return fmt.Errorf("unsupported statement type '%s'", s.Type)
+	}
+	if s.PredicateType != PREDICATE_TYPE {
+		return fmt.Errorf("unsupported predicate type '%s'", s.PredicateType)
+	}
+	if s.Predicate.BuildDefinition.BuildType != BUILD_TYPE {
+		return fmt.Errorf("unsupported build type '%s'", s.Predicate.BuildDefinition.BuildType)
+	}
+	if !s.hasSubject(manifestDigest) {
+		return fmt.Errorf("the manifest '%s' is not the subject of the provenance", manifestDigest)
+	}
+	if !s.hasSubject(tarDigest) {
+		return fmt.Errorf("the package tar '%s' is not the subject of the provenance", tarDigest)
+	}
+	return nil
+}
+
+// Source returns the git repo and the commit the package is built from.
+func (s *Statement) Source() (string, string) {
+	for _, dep := range s.Predicate.BuildDefinition.ResolvedDependencies {
+		if commit, ok := dep.Digest[DIGEST_GIT_COMMIT]; ok && dep.Name == "source" {
+			return strings.TrimPrefix(dep.Uri, "git+"), commit
+		}
+	}
+	return "", ""
+}
+
+// SourceDirty returns true if the package is built from the source with the changes not committed.
+func (s *Statement) SourceDirty() bool {
+	return s.Predicate.BuildDefinition.ExternalParameters.SourceDirty
+}
+
+// Dependencies returns the dependencies of the package resolved, without the source.
+func (s *Statement) Dependencies() []ResourceDescriptor {
+	var deps []ResourceDescriptor
+	for _, dep := range s.Predicate.BuildDefinition.ResolvedDependencies {
+		if _, ok := dep.Digest[DIGEST_GIT_COMMIT]; ok && dep.Name == "source" {
+			continue
+		}
+		deps = append(deps, dep)
+	}
+	return deps
+}
+
+// KpmVersion returns the version of kpm pushing the package.
+func (s *Statement) KpmVersion() string {
+	return s.Predicate.RunDetails.Builder.Version["kpm"]
+}
+
+func (s *Statement) hasSubject(digest string) bool {
+	algorithm, encoded, ok := strings.Cut(digest, ":")
+	if !ok {
+		return false
+	}
+	for _, subject := range s.Subject {
+		if subject.Digest[algorithm] == encoded {
+			return true
+		}
+	}
+	return false
+}
+
+// DigestSet returns the digest 'sha256:...' in the format of the in-toto digest set.
+func DigestSet(digest string) map[string]string {
+	algorithm, encoded, ok := strings.Cut(digest, ":")
+	if !ok {
+		return map[string]string{}
+	}
+	return map[string]string{algorithm: encoded}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,96 @@
+package provenance
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testManifestDigest = "sha256:3cdd0e30457d33c546167d42b40fd3ddb72066630998ea3e46406b2ca21661e4"
+	testTarDigest      = "sha256:9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+)
+
+func testBuildInfo() *BuildInfo {
+	return &BuildInfo{
+		Name:           "helloworld",
+		Version:        "0.1.2",
+		Edition:        "v0.10.0",
+		Reference:      "ghcr.io/kcl-lang/helloworld:0.1.2",
+		ManifestDigest: testManifestDigest,
+		TarName:        "helloworld_0.1.2.tar",
+		TarDigest:      testTarDigest,
+		SourceUrl:      "https://github.com/kcl-lang/modules",
+		SourceCommit:   "ade147b0d8d5d2a8e8e4f6c1b1f0a3c9e2b7d4f5",
+		KpmVersion:     "0.10.0",
+		Dependencies: []ResourceDescriptor{
+			{
+				Name:   "k8s",
+				Uri:    "oci://ghcr.io/kcl-lang/k8s:1.28",
+				Digest: map[string]string{DIGEST_KCL_SUM: "h1:PN0OMEV9M8VGFn1CtA/T3bcgZmMJmOo+RkBrLKIWYeQ="},
+			},
+		},
+		StartedOn:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		FinishedOn: time.Date(2024, 1, 1, 0, 0, 1, 0, time.UTC),
+	}
+}
+
+func TestNewStatement(t *testing.T) {
+	statement := NewStatement(testBuildInfo())
+
+	assert.Equal(t, STATEMENT_TYPE, statement.Type)
+	assert.Equal(t, PREDICATE_TYPE, statement.PredicateType)
+	assert.Equal(t, []Subject{
+		{Name: "ghcr.io/kcl-lang/helloworld:0.1.2", Digest: map[string]string{"sha256": "3cdd0e30457d33c546167d42b40fd3ddb72066630998ea3e46406b2ca21661e4"}},
+		{Name: "helloworld_0.1.2.tar", Digest: map[string]string{"sha256": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"}},
+	}, statement.Subject)
+	assert.Equal(t, "git+https://github.com/kcl-lang/modules@ade147b0d8d5d2a8e8e4f6c1b1f0a3c9e2b7d4f5", statement.Predicate.BuildDefinition.ExternalParameters.Source)
+	assert.Equal(t, "v0.10.0", statement.Predicate.BuildDefinition.InternalParameters.Edition)
+	assert.Equal(t, "2024-01-01T00:00:01Z", statement.Predicate.RunDetails.Metadata.FinishedOn)
+
+	url, commit := statement.Source()
+	assert.Equal(t, "https://github.com/kcl-lang/modules", url)
+	assert.Equal(t, "ade147b0d8d5d2a8e8e4f6c1b1f0a3c9e2b7d4f5", commit)
+	assert.Equal(t, testBuildInfo().Dependencies, statement.Dependencies())
+	assert.Equal(t, "0.10.0", statement.KpmVersion())
+	assert.Equal(t, false, statement.SourceDirty())
+
+	// The package built from the source with the changes not committed.
+	info := testBuildInfo()
+	info.SourceDirty = true
+	statement = NewStatement(info)
+	assert.Equal(t, true, statement.SourceDirty())
+	content, err := json.Marshal(statement)
+	assert.Nil(t, err)
+	assert.Contains(t, string(content), `"sourceDirty":true`)
+
+	// The package not in a git repo has no source.
+	info = testBuildInfo()
+	info.SourceCommit = ""
+	statement = NewStatement(info)
+	url, commit = statement.Source()
+	assert.Equal(t, "", url)
+	assert.Equal(t, "", commit)
+	assert.Equal(t, "", statement.Predicate.BuildDefinition.ExternalParameters.Source)
+	assert.Equal(t, 1, len(statement.Predicate.BuildDefinition.ResolvedDependencies))
+}
+
+func TestVerifyStatement(t *testing.T) {
+	content, err := json.Marshal(NewStatement(testBuildInfo()))
+	assert.Nil(t, err)
+	statement, err := ParseStatement(content)
+	assert.Nil(t, err)
+
+	assert.Nil(t, statement.Verify(testManifestDigest, testTarDigest))
+	assert.ErrorContains(t, statement.Verify("sha256:0000", testTarDigest), "the manifest 'sha256:0000' is not the subject of the provenance")
+	assert.ErrorContains(t, statement.Verify(testManifestDigest, "sha256:0000"), "the package tar 'sha256:0000' is not the subject of the provenance")
+	assert.ErrorContains(t, statement.Verify(testManifestDigest, ""), "is not the subject of the provenance")
+
+	statement.PredicateType = "https://slsa.dev/provenance/v0.2"
+	assert.ErrorContains(t, statement.Verify(testManifestDigest, testTarDigest), "unsupported predicate type")
+
+	_, err = ParseStatement([]byte("not json"))
+	assert.ErrorContains(t, err, "invalid provenance statement")
+}
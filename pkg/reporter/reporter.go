@@ -87,6 +87,16 @@ const (
 	FailedCloneFromGit
 	FailedHashPkg
 	FailedUpdatingBuildList
+	FailedParseKclFile
+	FailedVerifyDeps
+	DepsOutdated
+	LockFileChanged
+	FailedLoadKclWork
+	FailedMarkPkgVersion
+	PkgVersionYanked
+	FailedSign
+	FailedVerifySignature
+	FailedVerifyProvenance
 	Bug
 
 	// normal event type means the event is a normal event.
@@ -114,10 +124,12 @@ const (
 	CircularDependencyExist
 	RemoveDep
 	AddDep
+	UpdateDep
 	KclModNotFound
 	CompileFailed
 	FailedParseVersion
 	FailedFetchOciManifest
+	PkgVersionDeprecated
 )
 
 // KpmEvent is the event used to show kpm logs to users.
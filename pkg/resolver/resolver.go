@@ -8,6 +8,7 @@ import (
 	"kcl-lang.io/kpm/pkg/constants"
 	"kcl-lang.io/kpm/pkg/downloader"
 	pkg "kcl-lang.io/kpm/pkg/package"
+	"kcl-lang.io/kpm/pkg/semver"
 	"kcl-lang.io/kpm/pkg/settings"
 	"kcl-lang.io/kpm/pkg/utils"
 	"kcl-lang.io/kpm/pkg/visitor"
@@ -20,6 +21,14 @@ type ResolveOption func(*ResolveOptions) error
 // currentPkg is the current package to be resolved and parentPkg is the parent package of the current package.
 type resolveFunc func(dep *pkg.Dependency, parentPkg *pkg.KclPkg) error
 
+// checkFunc is the function for checking each dependency before it is visited.
+type checkFunc func(dep *pkg.Dependency) error
+
+// fetchModFunc is the function for fetching kcl.mod of the remote package without downloading the package,
+// e.g. from the config blob of the package published in the oci registry.
+// It returns nil if kcl.mod can not be fetched alone, and the package is downloaded to read kcl.mod instead.
+type fetchModFunc func(source *downloader.Source) (*pkg.ModFile, error)
+
 type ResolveOptions struct {
 	// Source is the source of the package to be pulled.
 	// Including git, oci, local.
@@ -74,6 +83,18 @@ type DepsResolver struct {
 	Settings              *settings.Settings
 	LogWriter             io.Writer
 	ResolveFuncs          []resolveFunc
+	// CheckFuncs check each dependency with the source to be visited before visiting it,
+	// e.g. to refuse the version yanked by the publisher.
+	CheckFuncs []checkFunc
+	// FetchModFunc fetches kcl.mod of the remote package to traverse its dependencies without downloading it.
+	// The package is downloaded if it is nil or the package has no kcl.mod fetched alone.
+	FetchModFunc fetchModFunc
+	// Replaces is the [replace] section in kcl.mod of the package being resolved.
+	// It redirects the direct and indirect dependencies to the replacements.
+	Replaces pkg.Replaces
+	// LockDeps is the dependencies in kcl.mod.lock of the package being resolved.
+	// The version locked is selected for the dependency required by a version range if it is still in the range.
+	LockDeps *pkg.Dependencies
 }
 
 // Resolve resolves the dependencies of the package.
@@ -132,22 +153,23 @@ func (dr *DepsResolver) Resolve(options ...ResolveOption) error {
 	visitFunc := func(kclPkg *pkg.KclPkg) error {
 		// Traverse the all dependencies of the package.
 		for _, depKey := range kclPkg.ModFile.Deps.Keys() {
-			dep, ok := kclPkg.ModFile.Deps.Get(depKey)
+			modDep, ok := kclPkg.ModFile.Deps.Get(depKey)
 			if !ok {
 				break
 			}
 
+			// If the dependency is required by a version range, select the exact version.
+			exactDep, err := dr.ResolveVersionRange(&modDep)
+			if err != nil {
+				return err
+			}
+
 			// Get the dependency source.
-			var depSource downloader.Source
-			// If the dependency source is a local path and the path is not absolute, transform the path to absolute path.
-			if dep.Source.IsLocalPath() && !filepath.IsAbs(dep.Source.Path) {
-				depSource = downloader.Source{
-					Local: &downloader.Local{
-						Path: filepath.Join(kclPkg.HomePath, dep.Source.Path),
-					},
-				}
-			} else {
-				depSource = dep.Source
+			dep, depSource := dr.ResolveSource(exactDep, kclPkg.HomePath)
+
+			err = dr.Check(dep, depSource)
+			if err != nil {
+				return err
 			}
 
 			// Get the visitor for the dependency source.
@@ -160,7 +182,7 @@ func (dr *DepsResolver) Resolve(options ...ResolveOption) error {
 			err = visitor.Visit(&depSource,
 				func(childPkg *pkg.KclPkg) error {
 					for _, resolveFunc := range dr.ResolveFuncs {
-						err := resolveFunc(&dep, kclPkg)
+						err := resolveFunc(dep, kclPkg)
 						if err != nil {
 							return err
 						}
@@ -187,10 +209,127 @@ func (dr *DepsResolver) Resolve(options ...ResolveOption) error {
 		return nil
 	}
 
-	visitor, err := visitorSelectorFunc(opts.Source)
+	source := opts.Source
+	if source.IsVersionRange() {
+		var err error
+		source, err = downloader.ResolveVersionRange(*source, dr.downloadOptions()...)
+		if err != nil {
+			return err
+		}
+	}
+
+	if kclPkg := dr.fetchPkg(source); kclPkg != nil {
+		return visitFunc(kclPkg)
+	}
+
+	visitor, err := visitorSelectorFunc(source)
 	if err != nil {
 		return err
 	}
 
-	return visitor.Visit(opts.Source, visitFunc)
+	return visitor.Visit(source, visitFunc)
+}
+
+// fetchPkg will return the remote package in the source with kcl.mod fetched by the FetchModFunc,
+// and nil if the package should be downloaded to read kcl.mod.
+// If kcl.mod fails to be fetched, the package is also downloaded, e.g. from the cache in offline.
+// The package fetched has no home path, so the package with the relative local dependencies is downloaded.
+func (dr *DepsResolver) fetchPkg(source *downloader.Source) *pkg.KclPkg {
+	if dr.FetchModFunc == nil || !source.IsRemote() {
+		return nil
+	}
+	modFile, err := dr.FetchModFunc(source)
+	if err != nil || modFile == nil {
+		return nil
+	}
+	// The kcl.mod fetched is the one of the root package,
+	// the package in the subdirectory of the source is downloaded to find and check it.
+	if !source.ModSpec.IsNil() && (modFile.Pkg.Name != source.ModSpec.Name || modFile.Pkg.Version != source.ModSpec.Version) {
+		return nil
+	}
+	// The local dependencies relative to the package are found in the package downloaded,
+	// the package fetched has no home path to resolve them.
+	if modFile.Dependencies.HasRelativeLocalDeps() {
+		return nil
+	}
+	return &pkg.KclPkg{ModFile: *modFile}
+}
+
+// ResolveVersionRange will select the exact version for the dependency required by a version range,
+// and return a copy of the dependency with the exact version.
+// The version in LockDeps is preferred if it is still in the range,
+// otherwise the highest version in the range is selected from the source.
+// If the dependency is not required by a version range, the dependency is returned as it is.
+func (dr *DepsResolver) ResolveVersionRange(dep *pkg.Dependency) (*pkg.Dependency, error) {
+	if !dep.Source.IsVersionRange() {
+		return dep, nil
+	}
+
+	if dr.LockDeps != nil && dr.LockDeps.Deps != nil {
+		if lockedDep, ok := dr.LockDeps.Deps.Get(dep.Name); ok && !lockedDep.Source.IsVersionRange() {
+			inRange, err := semver.VersionInRange(lockedDep.Version, dep.Source.VersionRange())
+			if err == nil && inRange {
+				exactDep := dep.WithExactVersion(lockedDep.Version)
+				exactDep.Sum = lockedDep.Sum
+				return &exactDep, nil
+			}
+		}
+	}
+
+	source, err := downloader.ResolveVersionRange(dep.Source, dr.downloadOptions()...)
+	if err != nil {
+		return nil, err
+	}
+
+	exactDep := dep.WithExactVersion(source.ExactVersion())
+	return &exactDep, nil
+}
+
+// Check will check the dependency with the source to be visited by the CheckFuncs,
+// the source is the replacement if the dependency is redirected by the [replace] section in kcl.mod.
+func (dr *DepsResolver) Check(dep *pkg.Dependency, source downloader.Source) error {
+	checkedDep := *dep
+	checkedDep.Source = source
+	for _, check := range dr.CheckFuncs {
+		if err := check(&checkedDep); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ResolveSource returns the dependency to be recorded and the source to be visited for the dependency
+// required by the package in 'homePath'.
+// If the dependency is redirected by the [replace] section in kcl.mod, the replacement is visited,
+// and the returned dependency records the replacement.
+// If the dependency source is a local path and the path is not absolute, the path is joined to 'homePath'.
+func (dr *DepsResolver) ResolveSource(dep *pkg.Dependency, homePath string) (*pkg.Dependency, downloader.Source) {
+	if replacedDep, ok := dr.Replaces.ReplaceDep(dep); ok {
+		recordedDep := dep.RecordReplace(replacedDep)
+		return &recordedDep, replacedDep.Source
+	}
+
+	if dep.Source.IsLocalPath() && !filepath.IsAbs(dep.Source.Path) {
+		return dep, downloader.Source{
+			Local: &downloader.Local{
+				Path: filepath.Join(homePath, dep.Source.Path),
+			},
+		}
+	}
+	return dep, dep.Source
+}
+
+// downloadOptions returns the options used to access the remote sources.
+func (dr *DepsResolver) downloadOptions() []downloader.Option {
+	options := []downloader.Option{
+		downloader.WithLogWriter(dr.LogWriter),
+		downloader.WithInsecureSkipTLSverify(dr.InsecureSkipTLSverify),
+	}
+	if dr.Settings != nil {
+		options = append(options, downloader.WithSettings(*dr.Settings))
+		if credCli, err := downloader.LoadCredentialFile(dr.Settings.CredentialsFile); err == nil {
+			options = append(options, downloader.WithCredsClient(credCli))
+		}
+	}
+	return options
 }
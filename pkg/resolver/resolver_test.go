@@ -66,3 +66,149 @@ func TestResolver(t *testing.T) {
 	assert.Equal(t, len(res), 3)
 	assert.Equal(t, res, expected)
 }
+
+func TestResolverCheck(t *testing.T) {
+	var checked []string
+	resolver := DepsResolver{
+		CheckFuncs: []checkFunc{func(dep *pkg.Dependency) error {
+			if dep.Source.Oci == nil {
+				return fmt.Errorf("'%s' is not from the oci registry", dep.Name)
+			}
+			checked = append(checked, fmt.Sprintf("%s:%s", dep.Source.Oci.Repo, dep.Source.Oci.Tag))
+			return nil
+		}},
+	}
+
+	dep := pkg.Dependency{
+		Name:    "helloworld",
+		Version: "0.1.2",
+		Source: downloader.Source{
+			Oci: &downloader.Oci{Reg: "ghcr.io", Repo: "kcl-lang/helloworld", Tag: "0.1.2"},
+		},
+	}
+	// The source to be visited is checked, e.g. the replacement of the dependency.
+	replacement := downloader.Source{
+		Oci: &downloader.Oci{Reg: "ghcr.io", Repo: "kcl-lang/helloworld-fork", Tag: "0.1.3"},
+	}
+	assert.Nil(t, resolver.Check(&dep, replacement))
+	assert.Equal(t, []string{"kcl-lang/helloworld-fork:0.1.3"}, checked)
+	assert.Equal(t, "kcl-lang/helloworld", dep.Source.Oci.Repo)
+
+	err := resolver.Check(&dep, downloader.Source{Local: &downloader.Local{Path: "helloworld"}})
+	assert.ErrorContains(t, err, "'helloworld' is not from the oci registry")
+}
+
+// failedDownloader fails to download any package,
+// to check the package is resolved without downloading it.
+type failedDownloader struct{}
+
+func (d *failedDownloader) Download(opts downloader.DownloadOptions) error {
+	return fmt.Errorf("'%s' should not be downloaded", opts.Source.Oci.Repo)
+}
+
+func TestResolverFetchMod(t *testing.T) {
+	depPath := filepath.Join(t.TempDir(), "dep1")
+	assert.Nil(t, os.MkdirAll(depPath, 0755))
+	assert.Nil(t, os.WriteFile(filepath.Join(depPath, "kcl.mod"), []byte("[package]\nname = \"dep1\"\nversion = \"0.0.1\"\n"), 0644))
+
+	rootMod := fmt.Sprintf("[package]\nname = \"root\"\nversion = \"0.0.1\"\n\n[dependencies]\ndep1 = { path = %q }\n", depPath)
+	modFile, _, err := pkg.ParseModFile(rootMod, "")
+	assert.Nil(t, err)
+
+	var res []string
+	var fetched []string
+	withConfig := true
+	resolver := DepsResolver{
+		Downloader: &failedDownloader{},
+		Settings:   settings.GetSettings(),
+		LogWriter:  &bytes.Buffer{},
+		ResolveFuncs: []resolveFunc{func(dep *pkg.Dependency, parentPkg *pkg.KclPkg) error {
+			res = append(res, fmt.Sprintf("%s -> %s", parentPkg.GetPkgName(), dep.Name))
+			return nil
+		}},
+		FetchModFunc: func(source *downloader.Source) (*pkg.ModFile, error) {
+			fetched = append(fetched, source.Oci.Repo)
+			if !withConfig {
+				// The package published in the layout without the config blob.
+				return nil, nil
+			}
+			return modFile, nil
+		},
+	}
+
+	// The dependencies of the package with the config blob are resolved without downloading it.
+	err = resolver.Resolve(WithSourceUrl("oci://ghcr.io/kcl-lang/root?tag=0.0.1"))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"root -> dep1"}, res)
+	assert.Equal(t, []string{"kcl-lang/root"}, fetched)
+
+	// The package without the config blob is downloaded.
+	withConfig = false
+	err = resolver.Resolve(WithSourceUrl("oci://ghcr.io/kcl-lang/root?tag=0.0.1"))
+	assert.ErrorContains(t, err, "'kcl-lang/root' should not be downloaded")
+
+	// The package in the subdirectory of the source is downloaded to find it.
+	withConfig = true
+	source := downloader.Source{
+		ModSpec: &downloader.ModSpec{Name: "sub", Version: "0.0.1"},
+		Oci:     &downloader.Oci{Reg: "ghcr.io", Repo: "kcl-lang/root", Tag: "0.0.1"},
+	}
+	err = resolver.Resolve(WithSource(&source))
+	assert.ErrorContains(t, err, "'kcl-lang/root' should not be downloaded")
+
+	// The package with the local dependencies relative to it is downloaded to find them.
+	modFile, _, err = pkg.ParseModFile("[package]\nname = \"root\"\nversion = \"0.0.1\"\n\n[dependencies]\ndep1 = { path = \"../dep1\" }\n", "")
+	assert.Nil(t, err)
+	err = resolver.Resolve(WithSourceUrl("oci://ghcr.io/kcl-lang/root?tag=0.0.1"))
+	assert.ErrorContains(t, err, "'kcl-lang/root' should not be downloaded")
+}
+
+func TestResolveVersionRangeWithLockDeps(t *testing.T) {
+	modFile, lockDeps, err := pkg.ParseModFile(`[package]
+name = "root"
+version = "0.0.1"
+
+[dependencies]
+k8s = "^1.28"
+helloworld = "~0.1.0"
+`, `[dependencies]
+  [dependencies.k8s]
+    name = "k8s"
+    full_name = "k8s_1.29"
+    version = "1.29"
+    sum = "sum_1.29"
+    reg = "ghcr.io"
+    repo = "kcl-lang/k8s"
+    oci_tag = "1.29"
+  [dependencies.helloworld]
+    name = "helloworld"
+    full_name = "helloworld_0.1.2"
+    version = "0.1.2"
+    sum = "sum_0.1.2"
+    reg = "ghcr.io"
+    repo = "kcl-lang/helloworld"
+    oci_tag = "0.1.2"
+`)
+	assert.Nil(t, err)
+
+	resolver := DepsResolver{
+		Downloader: &failedDownloader{},
+		Settings:   settings.GetSettings(),
+		LogWriter:  &bytes.Buffer{},
+		LockDeps:   lockDeps,
+	}
+
+	// The versions locked in kcl.mod.lock are selected in the caret and the tilde ranges,
+	// without selecting the highest versions in the ranges from the registry.
+	for name, expected := range map[string]string{"k8s": "1.29", "helloworld": "0.1.2"} {
+		modDep, ok := modFile.Deps.Get(name)
+		assert.True(t, ok)
+		assert.True(t, modDep.Source.IsVersionRange())
+
+		exactDep, err := resolver.ResolveVersionRange(&modDep)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, exactDep.Version)
+		assert.Equal(t, expected, exactDep.Source.Oci.Tag)
+		assert.Equal(t, "sum_"+expected, exactDep.Sum)
+	}
+}
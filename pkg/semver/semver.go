@@ -2,6 +2,7 @@ package semver
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/hashicorp/go-version"
 	"kcl-lang.io/kpm/pkg/constants"
@@ -84,3 +85,156 @@ func LeastOldCompatibleVersion(versions []string, baseVersion string) (string, e
 	}
 	return OldestVersion(compatibleVersions)
 }
+
+// FilterValidVersions returns the versions that can be parsed as semantic versions.
+func FilterValidVersions(versions []string) []string {
+	var validVersions []string
+	for _, v := range versions {
+		if _, err := version.NewVersion(v); err == nil {
+			validVersions = append(validVersions, v)
+		}
+	}
+	return validVersions
+}
+
+// IsVersionRange returns true if the version is a range such as '^1.28', '~1.2' or '>=0.1.0, <0.3.0',
+// rather than an exact version.
+func IsVersionRange(v string) bool {
+	v = strings.TrimSpace(v)
+	if len(v) == 0 {
+		return false
+	}
+	return strings.ContainsAny(v[:1], "^~<>=!") || strings.Contains(v, ",")
+}
+
+// VersionInRange returns true if the version matches the version range.
+func VersionInRange(v, versionRange string) (bool, error) {
+	constraints, err := parseVersionRange(versionRange)
+	if err != nil {
+		return false, err
+	}
+	ver, err := version.NewVersion(v)
+	if err != nil {
+		return false, reporter.NewErrorEvent(reporter.FailedParseVersion, err, fmt.Sprintf("failed to parse version %s", v))
+	}
+	return constraints.Check(ver), nil
+}
+
+// LatestVersionInRange returns the highest version matching the version range.
+// The versions that can not be parsed are skipped.
+func LatestVersionInRange(versions []string, versionRange string) (string, error) {
+	constraints, err := parseVersionRange(versionRange)
+	if err != nil {
+		return "", err
+	}
+
+	var matched []string
+	for _, v := range versions {
+		ver, err := version.NewVersion(v)
+		if err != nil {
+			continue
+		}
+		if constraints.Check(ver) {
+			matched = append(matched, v)
+		}
+	}
+
+	if len(matched) == 0 {
+		return "", reporter.NewErrorEvent(
+			reporter.FailedSelectLatestVersion,
+			fmt.Errorf("no version matches '%s'", versionRange),
+		)
+	}
+
+	return LatestVersion(matched)
+}
+
+// parseVersionRange parses the version range into the constraints.
+// The range consists of the comma-separated clauses, and all of them must be satisfied.
+// Each clause is one of:
+//
+//	^1.2.3  := >=1.2.3, <2.0.0 (^0.2.3 := >=0.2.3, <0.3.0)
+//	~1.2.3  := >=1.2.3, <1.3.0 (~1 := >=1, <2.0.0)
+//	>=, >, <=, <, =, != followed by a version
+func parseVersionRange(versionRange string) (version.Constraints, error) {
+	var clauses []string
+	for _, clause := range strings.Split(versionRange, ",") {
+		clause = strings.TrimSpace(clause)
+		if len(clause) == 0 {
+			continue
+		}
+
+		var err error
+		switch {
+		case strings.HasPrefix(clause, "^"):
+			clause, err = caretRange(strings.TrimSpace(clause[1:]))
+		case strings.HasPrefix(clause, "~") && !strings.HasPrefix(clause, "~>"):
+			clause, err = tildeRange(strings.TrimSpace(clause[1:]))
+		}
+		if err != nil {
+			return nil, err
+		}
+		clauses = append(clauses, clause)
+	}
+
+	constraints, err := version.NewConstraint(strings.Join(clauses, ", "))
+	if err != nil {
+		return nil, reporter.NewErrorEvent(
+			reporter.FailedParseVersion,
+			err,
+			fmt.Sprintf("failed to parse version range %s", versionRange),
+		)
+	}
+	return constraints, nil
+}
+
+// caretRange converts '^v' into the comparison clauses.
+// It allows the changes that do not modify the left-most non-zero segment of the version.
+func caretRange(v string) (string, error) {
+	ver, parts, err := parseRangeBase(v)
+	if err != nil {
+		return "", err
+	}
+	segments := ver.Segments()
+	var upper string
+	switch {
+	case segments[0] > 0 || parts == 1:
+		upper = fmt.Sprintf("%d.0.0", segments[0]+1)
+	case segments[1] > 0 || parts == 2:
+		upper = fmt.Sprintf("0.%d.0", segments[1]+1)
+	default:
+		upper = fmt.Sprintf("0.0.%d", segments[2]+1)
+	}
+	return fmt.Sprintf(">= %s, < %s", v, upper), nil
+}
+
+// tildeRange converts '~v' into the comparison clauses.
+// It allows the patch level changes if the minor version is specified, otherwise the minor level changes.
+func tildeRange(v string) (string, error) {
+	ver, parts, err := parseRangeBase(v)
+	if err != nil {
+		return "", err
+	}
+	segments := ver.Segments()
+	var upper string
+	if parts == 1 {
+		upper = fmt.Sprintf("%d.0.0", segments[0]+1)
+	} else {
+		upper = fmt.Sprintf("%d.%d.0", segments[0], segments[1]+1)
+	}
+	return fmt.Sprintf(">= %s, < %s", v, upper), nil
+}
+
+// parseRangeBase parses the version in the range clause,
+// and returns the number of the segments specified in it.
+func parseRangeBase(v string) (*version.Version, int, error) {
+	ver, err := version.NewVersion(v)
+	if err != nil {
+		return nil, 0, reporter.NewErrorEvent(reporter.FailedParseVersion, err, fmt.Sprintf("failed to parse version %s", v))
+	}
+	core := strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(core, "-+"); i >= 0 {
+		core = core[:i]
+	}
+	return ver, len(strings.Split(core, ".")), nil
+}
@@ -68,3 +68,53 @@ func TestFilterCompatibleVersions(t *testing.T) {
 		assert.Equal(t, v, expCompatible[i])
 	}
 }
+
+func TestFilterValidVersions(t *testing.T) {
+	valid := FilterValidVersions([]string{"1.2.3", "latest", "v1.4.0", "main", "2.0.0-rc.1"})
+	assert.Equal(t, len(valid), 3)
+	assert.Equal(t, valid[0], "1.2.3")
+	assert.Equal(t, valid[1], "v1.4.0")
+	assert.Equal(t, valid[2], "2.0.0-rc.1")
+}
+
+func TestIsVersionRange(t *testing.T) {
+	assert.Equal(t, IsVersionRange("^1.28"), true)
+	assert.Equal(t, IsVersionRange("~1.2"), true)
+	assert.Equal(t, IsVersionRange(">=0.1.0, <0.3.0"), true)
+	assert.Equal(t, IsVersionRange("1.28.0"), false)
+	assert.Equal(t, IsVersionRange(""), false)
+}
+
+func TestLatestVersionInRange(t *testing.T) {
+	versions := []string{"0.1.0", "0.2.5", "0.3.0", "1.2.0", "1.2.3", "1.3.0", "1.28.0", "1.29.1", "2.0.0", "latest"}
+	cases := []struct {
+		versionRange string
+		expected     string
+	}{
+		{"^1.28", "1.29.1"},
+		{"^1.2.3", "1.29.1"},
+		{"^0.2", "0.2.5"},
+		{"~1.2", "1.2.3"},
+		{"~1", "1.29.1"},
+		{">=0.1.0, <0.3.0", "0.2.5"},
+		{">= 1.0.0, != 2.0.0", "1.29.1"},
+	}
+	for _, c := range cases {
+		latest, err := LatestVersionInRange(versions, c.versionRange)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, latest, c.expected, c.versionRange)
+	}
+
+	_, err := LatestVersionInRange(versions, "^3.0")
+	assert.ErrorContains(t, err, "no version matches '^3.0'")
+}
+
+func TestVersionInRange(t *testing.T) {
+	inRange, err := VersionInRange("1.28.1", "^1.28")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, inRange, true)
+
+	inRange, err = VersionInRange("2.0.0", "^1.28")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, inRange, false)
+}
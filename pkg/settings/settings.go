@@ -31,6 +31,21 @@ type KpmConf struct {
 	DefaultOciRegistry  string
 	DefaultOciRepo      string
 	DefaultOciPlainHttp *bool `json:",omitempty"`
+	// SignaturePolicies are the registries or namespaces requiring the packages signed by the trusted keys.
+	SignaturePolicies []SignaturePolicy `json:",omitempty"`
+}
+
+// SignaturePolicy requires the packages pulled from the scope to be signed by one of the trusted public keys.
+//
+//	"SignaturePolicies": [
+//	  {"Scope": "ghcr.io/kcl-lang", "PublicKeys": ["keys/kcl-lang.pub"]}
+//	]
+type SignaturePolicy struct {
+	// Scope is the registry or the namespace in the registry, e.g. 'ghcr.io' or 'ghcr.io/kcl-lang'.
+	Scope string
+	// PublicKeys are the paths of the trusted public keys in PEM,
+	// the relative paths are relative to the directory of 'kpm.json'.
+	PublicKeys []string
 }
 
 const ON = "on"
@@ -153,6 +168,35 @@ func (settings *Settings) ForceOciPlainHttp() (bool, bool) {
 	return *settings.Conf.DefaultOciPlainHttp, true
 }
 
+// SignaturePolicyFor returns the signature policy of the oci repo 'repo' in the registry 'registry'.
+// The policy with the most specific scope is returned, and nil is returned if no policy applies.
+// The relative paths of the public keys in the policy returned are resolved.
+func (settings *Settings) SignaturePolicyFor(registry, repo string) *SignaturePolicy {
+	ref := utils.JoinPath(registry, repo)
+	var matched *SignaturePolicy
+	for i, policy := range settings.Conf.SignaturePolicies {
+		scope := strings.TrimSuffix(policy.Scope, "/")
+		if len(scope) == 0 || (ref != scope && !strings.HasPrefix(ref, scope+"/")) {
+			continue
+		}
+		if matched == nil || len(scope) > len(strings.TrimSuffix(matched.Scope, "/")) {
+			matched = &settings.Conf.SignaturePolicies[i]
+		}
+	}
+	if matched == nil {
+		return nil
+	}
+
+	policy := SignaturePolicy{Scope: matched.Scope}
+	for _, keyPath := range matched.PublicKeys {
+		if !filepath.IsAbs(keyPath) && len(settings.KpmConfFile) != 0 {
+			keyPath = filepath.Join(filepath.Dir(settings.KpmConfFile), keyPath)
+		}
+		policy.PublicKeys = append(policy.PublicKeys, keyPath)
+	}
+	return &policy
+}
+
 // DefaultOciRef return the default OCI ref 'ghcr.io/kcl-lang'.
 func (settings *Settings) DefaultOciRef() string {
 	return utils.JoinPath(settings.Conf.DefaultOciRegistry, settings.Conf.DefaultOciRepo)
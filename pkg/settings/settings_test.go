@@ -187,3 +187,25 @@ func TestSettingEnv(t *testing.T) {
 	settings = GetSettings()
 	assert.Equal(t, settings.DefaultOciPlainHttp(), false)
 }
+
+func TestSignaturePolicyFor(t *testing.T) {
+	settings := Settings{
+		KpmConfFile: filepath.Join("/home", ".kpm", "config", "kpm.json"),
+		Conf: KpmConf{
+			SignaturePolicies: []SignaturePolicy{
+				{Scope: "ghcr.io", PublicKeys: []string{"/keys/ghcr.pub"}},
+				{Scope: "ghcr.io/kcl-lang/", PublicKeys: []string{"keys/kcl-lang.pub"}},
+			},
+		},
+	}
+
+	policy := settings.SignaturePolicyFor("ghcr.io", "kcl-lang/helloworld")
+	assert.Equal(t, policy.Scope, "ghcr.io/kcl-lang/")
+	assert.Equal(t, policy.PublicKeys, []string{filepath.Join("/home", ".kpm", "config", "keys", "kcl-lang.pub")})
+
+	policy = settings.SignaturePolicyFor("ghcr.io", "kcl-lang-fork/helloworld")
+	assert.Equal(t, policy.Scope, "ghcr.io")
+	assert.Equal(t, policy.PublicKeys, []string{"/keys/ghcr.pub"})
+
+	assert.Nil(t, settings.SignaturePolicyFor("docker.io", "kcl-lang/helloworld"))
+}
@@ -0,0 +1,96 @@
+package signature
+
+import (
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// The media type of the DSSE envelope, e.g. the envelope of the signed in-toto statement.
+const DSSE_MEDIA_TYPE = "application/vnd.dsse.envelope.v1+json"
+
+// Envelope is the DSSE envelope of the payload signed.
+// See https://github.com/secure-systems-lab/dsse/blob/master/envelope.md.
+type Envelope struct {
+	PayloadType string `json:"payloadType"`
+	// Payload is the payload signed in base64.
+	Payload    string              `json:"payload"`
+	Signatures []EnvelopeSignature `json:"signatures"`
+}
+
+// EnvelopeSignature is the signature of the envelope in base64.
+type EnvelopeSignature struct {
+	KeyId string `json:"keyid"`
+	Sig   string `json:"sig"`
+}
+
+// PAE returns the pre-authentication encoding of the payload, which is the content signed in the envelope.
+func PAE(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
+
+// SignEnvelope signs the payload of 'payloadType' by the private key and returns the envelope.
+func SignEnvelope(signer crypto.Signer, payloadType string, payload []byte) (*Envelope, error) {
+	sig, err := Sign(signer, PAE(payloadType, payload))
+	if err != nil {
+		return nil, err
+	}
+	return &Envelope{
+		PayloadType: payloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures:  []EnvelopeSignature{{Sig: base64.StdEncoding.EncodeToString(sig)}},
+	}, nil
+}
+
+// ParseEnvelope parses the DSSE envelope in json.
+func ParseEnvelope(content []byte) (*Envelope, error) {
+	var envelope Envelope
+	if err := json.Unmarshal(content, &envelope); err != nil {
+		return nil, fmt.Errorf("invalid dsse envelope: %w", err)
+	}
+	if len(envelope.PayloadType) == 0 || len(envelope.Payload) == 0 {
+		return nil, errors.New("invalid dsse envelope: the payload is empty")
+	}
+	return &envelope, nil
+}
+
+// DecodePayload returns the payload of the envelope without checking the signatures.
+func (e *Envelope) DecodePayload() ([]byte, error) {
+	payload, err := base64.StdEncoding.DecodeString(e.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dsse payload: %w", err)
+	}
+	return payload, nil
+}
+
+// VerifyEnvelope checks whether the envelope is signed by one of the public keys,
+// and returns the payload verified.
+func VerifyEnvelope(envelope *Envelope, publicKeys []crypto.PublicKey) ([]byte, error) {
+	payload, err := envelope.DecodePayload()
+	if err != nil {
+		return nil, err
+	}
+	if len(envelope.Signatures) == 0 {
+		return nil, errors.New("the dsse envelope is not signed")
+	}
+
+	pae := PAE(envelope.PayloadType, payload)
+	lastErr := errors.New("no public key is provided")
+	for _, signature := range envelope.Signatures {
+		sig, err := base64.StdEncoding.DecodeString(signature.Sig)
+		if err != nil {
+			lastErr = fmt.Errorf("invalid dsse signature: %w", err)
+			continue
+		}
+		for _, publicKey := range publicKeys {
+			if err := Verify(publicKey, pae, sig); err != nil {
+				lastErr = err
+				continue
+			}
+			return payload, nil
+		}
+	}
+	return nil, lastErr
+}
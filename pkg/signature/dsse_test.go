@@ -0,0 +1,71 @@
+package signature
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignAndVerifyEnvelope(t *testing.T) {
+	assert.Equal(t, string(PAE("application/vnd.in-toto+json", []byte("hello"))), "DSSEv1 28 application/vnd.in-toto+json 5 hello")
+
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	assert.Nil(t, err)
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	payload := []byte(`{"_type":"https://in-toto.io/Statement/v1"}`)
+
+	for _, key := range []crypto.Signer{ed25519Key, ecdsaKey} {
+		envelope, err := SignEnvelope(key, "application/vnd.in-toto+json", payload)
+		assert.Nil(t, err)
+		content, err := json.Marshal(envelope)
+		assert.Nil(t, err)
+
+		parsed, err := ParseEnvelope(content)
+		assert.Nil(t, err)
+		verified, err := VerifyEnvelope(parsed, []crypto.PublicKey{key.Public()})
+		assert.Nil(t, err)
+		assert.Equal(t, verified, payload)
+
+		// The envelope is verified by any of the public keys.
+		verified, err = VerifyEnvelope(parsed, []crypto.PublicKey{ed25519Key.Public(), ecdsaKey.Public()})
+		assert.Nil(t, err)
+		assert.Equal(t, verified, payload)
+
+		_, err = VerifyEnvelope(parsed, nil)
+		assert.ErrorContains(t, err, "no public key is provided")
+
+		// The payload type is also signed.
+		tampered := *parsed
+		tampered.PayloadType = "application/json"
+		_, err = VerifyEnvelope(&tampered, []crypto.PublicKey{key.Public()})
+		assert.ErrorContains(t, err, "invalid signature")
+
+		tampered = *parsed
+		tampered.Payload = base64.StdEncoding.EncodeToString([]byte("tampered"))
+		_, err = VerifyEnvelope(&tampered, []crypto.PublicKey{key.Public()})
+		assert.ErrorContains(t, err, "invalid signature")
+	}
+
+	// The envelope is not verified by another key.
+	envelope, err := SignEnvelope(ed25519Key, "application/vnd.in-toto+json", payload)
+	assert.Nil(t, err)
+	_, err = VerifyEnvelope(envelope, []crypto.PublicKey{ecdsaKey.Public()})
+	assert.ErrorContains(t, err, "invalid signature")
+
+	unsigned := &Envelope{PayloadType: "application/vnd.in-toto+json", Payload: envelope.Payload}
+	_, err = VerifyEnvelope(unsigned, []crypto.PublicKey{ed25519Key.Public()})
+	assert.ErrorContains(t, err, "the dsse envelope is not signed")
+
+	_, err = ParseEnvelope(payload)
+	assert.ErrorContains(t, err, "the payload is empty")
+	_, err = ParseEnvelope([]byte("invalid"))
+	assert.ErrorContains(t, err, "invalid dsse envelope")
+}
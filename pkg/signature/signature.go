@@ -0,0 +1,207 @@
+// Copyright 2024 The KCL Authors. All rights reserved.
+//
+// Package signature signs and verifies the kcl packages published in the oci registry.
+//
+// The signature is compatible with cosign: the payload is the cosign simple signing payload
+// pointing to the digest of the manifest of the package, and it is signed by an ed25519 or ECDSA key in PEM.
+// The signature is attached to the package as an oci referrer in the format of 'cosign sign --registry-referrers-mode=oci-1-1',
+// so that it can also be verified by 'cosign verify --key <public key>'.
+package signature
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+)
+
+const (
+	// The artifact type of the signature referring to the package.
+	SIGNATURE_ARTIFACT_TYPE = "application/vnd.dev.cosign.artifact.sig.v1+json"
+	// The media type of the layer of the simple signing payload.
+	SIMPLE_SIGNING_MEDIA_TYPE = "application/vnd.dev.cosign.simplesigning.v1+json"
+	// The annotation of the layer holding the signature of the payload in base64.
+	SIGNATURE_ANNOTATION = "dev.cosignproject.cosign/signature"
+	// The type of the simple signing payload.
+	SIMPLE_SIGNING_TYPE = "cosign container image signature"
+)
+
+// The pem types of the keys.
+const (
+	PEM_TYPE_PRIVATE_KEY            = "PRIVATE KEY"
+	PEM_TYPE_EC_PRIVATE_KEY         = "EC PRIVATE KEY"
+	PEM_TYPE_PUBLIC_KEY             = "PUBLIC KEY"
+	PEM_TYPE_ENCRYPTED_COSIGN_KEY   = "ENCRYPTED COSIGN PRIVATE KEY"
+	PEM_TYPE_ENCRYPTED_SIGSTORE_KEY = "ENCRYPTED SIGSTORE PRIVATE KEY"
+)
+
+// SimpleSigning is the cosign simple signing payload.
+type SimpleSigning struct {
+	Critical Critical          `json:"critical"`
+	Optional map[string]string `json:"optional"`
+}
+
+// Critical is the critical section of the simple signing payload.
+type Critical struct {
+	Identity Identity `json:"identity"`
+	Image    Image    `json:"image"`
+	Type     string   `json:"type"`
+}
+
+// Identity is the oci repo the package is published to, e.g. 'ghcr.io/kcl-lang/helloworld'.
+type Identity struct {
+	DockerReference string `json:"docker-reference"`
+}
+
+// Image is the digest of the manifest of the package signed.
+type Image struct {
+	DockerManifestDigest string `json:"docker-manifest-digest"`
+}
+
+// NewPayload returns the simple signing payload of the manifest 'digest' in the oci repo 'repoRef'.
+func NewPayload(repoRef, digest string) ([]byte, error) {
+	return json.Marshal(SimpleSigning{
+		Critical: Critical{
+			Identity: Identity{DockerReference: repoRef},
+			Image:    Image{DockerManifestDigest: digest},
+			Type:     SIMPLE_SIGNING_TYPE,
+		},
+	})
+}
+
+// VerifyPayload checks whether the simple signing payload is signed for the manifest 'digest' in the oci repo 'repoRef',
+// so that the signature of a package can not be copied to another repo.
+func VerifyPayload(payload []byte, repoRef, digest string) error {
+	var simpleSigning SimpleSigning
+	if err := json.Unmarshal(payload, &simpleSigning); err != nil {
+		return fmt.Errorf("invalid signature payload: %w", err)
+	}
+	if simpleSigning.Critical.Type != SIMPLE_SIGNING_TYPE {
+		return fmt.Errorf("invalid signature payload type '%s'", simpleSigning.Critical.Type)
+	}
+	if simpleSigning.Critical.Identity.DockerReference != repoRef {
+		return fmt.Errorf(
+			"the signature is signed for the repo '%s', not for '%s'",
+			simpleSigning.Critical.Identity.DockerReference, repoRef,
+		)
+	}
+	if simpleSigning.Critical.Image.DockerManifestDigest != digest {
+		return fmt.Errorf(
+			"the signature is signed for '%s', not for '%s'",
+			simpleSigning.Critical.Image.DockerManifestDigest, digest,
+		)
+	}
+	return nil
+}
+
+// LoadPrivateKey loads the ed25519 or ECDSA private key in PEM from 'path'.
+// The unencrypted PKCS #8 and SEC 1 keys are supported, e.g. the key generated by openssl,
+// which can also be imported into cosign by 'cosign import-key-pair'.
+func LoadPrivateKey(path string) (crypto.Signer, error) {
+	block, err := loadPem(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch block.Type {
+	case PEM_TYPE_PRIVATE_KEY:
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the private key '%s': %w", path, err)
+		}
+		switch key := key.(type) {
+		case ed25519.PrivateKey:
+			return key, nil
+		case *ecdsa.PrivateKey:
+			return key, nil
+		default:
+			return nil, fmt.Errorf("unsupported private key '%s', only ed25519 and ECDSA keys are supported", path)
+		}
+	case PEM_TYPE_EC_PRIVATE_KEY:
+		key, err := x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the private key '%s': %w", path, err)
+		}
+		return key, nil
+	case PEM_TYPE_ENCRYPTED_COSIGN_KEY, PEM_TYPE_ENCRYPTED_SIGSTORE_KEY:
+		return nil, fmt.Errorf("the encrypted cosign key '%s' is not supported, please use an unencrypted PKCS #8 key", path)
+	default:
+		return nil, fmt.Errorf("unsupported pem type '%s' of the private key '%s'", block.Type, path)
+	}
+}
+
+// LoadPublicKey loads the ed25519 or ECDSA public key in PEM from 'path', e.g. the 'cosign.pub' generated by cosign.
+func LoadPublicKey(path string) (crypto.PublicKey, error) {
+	block, err := loadPem(path)
+	if err != nil {
+		return nil, err
+	}
+	if block.Type != PEM_TYPE_PUBLIC_KEY {
+		return nil, fmt.Errorf("unsupported pem type '%s' of the public key '%s'", block.Type, path)
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the public key '%s': %w", path, err)
+	}
+	switch key := key.(type) {
+	case ed25519.PublicKey:
+		return key, nil
+	case *ecdsa.PublicKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key '%s', only ed25519 and ECDSA keys are supported", path)
+	}
+}
+
+// Sign signs the payload by the private key.
+// The ECDSA key signs the sha256 digest of the payload and the ed25519 key signs the payload itself, as cosign does.
+func Sign(signer crypto.Signer, payload []byte) ([]byte, error) {
+	switch key := signer.(type) {
+	case ed25519.PrivateKey:
+		return ed25519.Sign(key, payload), nil
+	case *ecdsa.PrivateKey:
+		digest := sha256.Sum256(payload)
+		return ecdsa.SignASN1(rand.Reader, key, digest[:])
+	default:
+		return nil, errors.New("unsupported private key, only ed25519 and ECDSA keys are supported")
+	}
+}
+
+// Verify verifies the signature of the payload by the public key.
+func Verify(publicKey crypto.PublicKey, payload, sig []byte) error {
+	switch key := publicKey.(type) {
+	case ed25519.PublicKey:
+		if ed25519.Verify(key, payload, sig) {
+			return nil
+		}
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(payload)
+		if ecdsa.VerifyASN1(key, digest[:], sig) {
+			return nil
+		}
+	default:
+		return errors.New("unsupported public key, only ed25519 and ECDSA keys are supported")
+	}
+	return errors.New("invalid signature")
+}
+
+// loadPem loads the first pem block from 'path'.
+func loadPem(path string) (*pem.Block, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, fmt.Errorf("no pem block is found in '%s'", path)
+	}
+	return block, nil
+}
@@ -0,0 +1,79 @@
+package signature
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeKeyPair(t *testing.T, dir string, key crypto.Signer) (string, string) {
+	privBytes, err := x509.MarshalPKCS8PrivateKey(key)
+	assert.Nil(t, err)
+	pubBytes, err := x509.MarshalPKIXPublicKey(key.Public())
+	assert.Nil(t, err)
+
+	privPath := filepath.Join(dir, "key.pem")
+	pubPath := filepath.Join(dir, "key.pub")
+	assert.Nil(t, os.WriteFile(privPath, pem.EncodeToMemory(&pem.Block{Type: PEM_TYPE_PRIVATE_KEY, Bytes: privBytes}), 0600))
+	assert.Nil(t, os.WriteFile(pubPath, pem.EncodeToMemory(&pem.Block{Type: PEM_TYPE_PUBLIC_KEY, Bytes: pubBytes}), 0644))
+	return privPath, pubPath
+}
+
+func TestSignAndVerify(t *testing.T) {
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	assert.Nil(t, err)
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	digest := "sha256:d2a8bd1b2ab6da3f9f7c1b9a4e8a09a4e58b6e1b2c0e4d6c1b6bd1ab0f3c5d22"
+	payload, err := NewPayload("localhost:5001/test/helloworld", digest)
+	assert.Nil(t, err)
+	assert.Equal(t, string(payload), `{"critical":{"identity":{"docker-reference":"localhost:5001/test/helloworld"},"image":{"docker-manifest-digest":"`+digest+`"},"type":"cosign container image signature"},"optional":null}`)
+	assert.Nil(t, VerifyPayload(payload, "localhost:5001/test/helloworld", digest))
+	assert.ErrorContains(t, VerifyPayload(payload, "localhost:5001/test/helloworld", "sha256:0000"), "the signature is signed for")
+	// The signature copied to another repo is not valid.
+	assert.ErrorContains(t, VerifyPayload(payload, "localhost:5001/other/helloworld", digest), "the signature is signed for the repo 'localhost:5001/test/helloworld'")
+
+	for _, key := range []crypto.Signer{ed25519Key, ecdsaKey} {
+		privPath, pubPath := writeKeyPair(t, t.TempDir(), key)
+
+		signer, err := LoadPrivateKey(privPath)
+		assert.Nil(t, err)
+		publicKey, err := LoadPublicKey(pubPath)
+		assert.Nil(t, err)
+
+		sig, err := Sign(signer, payload)
+		assert.Nil(t, err)
+		assert.Nil(t, Verify(publicKey, payload, sig))
+		assert.ErrorContains(t, Verify(publicKey, []byte("tampered"), sig), "invalid signature")
+	}
+
+	// The signature is not verified by another key.
+	sig, err := Sign(ed25519Key, payload)
+	assert.Nil(t, err)
+	assert.ErrorContains(t, Verify(ecdsaKey.Public(), payload, sig), "invalid signature")
+}
+
+func TestLoadKeyInvalid(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cosign.key")
+	assert.Nil(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: PEM_TYPE_ENCRYPTED_SIGSTORE_KEY, Bytes: []byte("encrypted")}), 0600))
+	_, err := LoadPrivateKey(path)
+	assert.ErrorContains(t, err, "the encrypted cosign key")
+
+	_, err = LoadPublicKey(path)
+	assert.ErrorContains(t, err, "unsupported pem type")
+
+	assert.Nil(t, os.WriteFile(path, []byte("not a pem"), 0600))
+	_, err = LoadPrivateKey(path)
+	assert.ErrorContains(t, err, "no pem block is found")
+}
@@ -10,12 +10,14 @@ import (
 	goerrors "errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"log"
 	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"sort"
 	"strings"
 	"syscall"
 
@@ -74,6 +76,129 @@ func HashDir(dir string) (string, error) {
 	return base64.StdEncoding.EncodeToString(hasher.Sum(nil)), nil
 }
 
+// H1_SUM_PREFIX is the prefix of the checksum computed by HashDirH1.
+// The checksum without the prefix is computed by HashDir.
+const H1_SUM_PREFIX = "h1:"
+
+// HashDirH1 computes the checksum of a directory with the prefix 'h1:'.
+// The checksum is the sha256 of the sorted list of the paths of the files in the directory
+// and the sha256 of their contents, so renaming a file changes the checksum.
+// The '.git' directories and the package tars under the directory are not included.
+func HashDirH1(dir string) (string, error) {
+	fileSums := make(map[string]string)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == constants.GitPathSuffix {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		// The package tar is generated into the root of the package by 'kpm pkg'.
+		if !strings.Contains(relPath, string(filepath.Separator)) && IsTar(relPath) {
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		fileSums[filepath.ToSlash(relPath)], err = hashContent(f)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return sumH1(fileSums), nil
+}
+
+// HashTarH1 computes the checksum of the files in a package tar with the prefix 'h1:'.
+// It is the same as the checksum computed by HashDirH1 from the directory the tar is extracted into.
+func HashTarH1(tarPath string) (string, error) {
+	file, err := os.Open(tarPath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	fileSums := make(map[string]string)
+	tarReader := tar.NewReader(file)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		fileSums[filepath.ToSlash(filepath.Clean(header.Name))], err = hashContent(tarReader)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return sumH1(fileSums), nil
+}
+
+// HashDirAs computes the checksum of a directory by the algorithm the checksum 'sum' is computed by,
+// so that the checksums computed by the algorithm before 'h1:' can still be verified.
+func HashDirAs(dir, sum string) (string, error) {
+	if IsH1Sum(sum) {
+		return HashDirH1(dir)
+	}
+	return HashDir(dir)
+}
+
+// IsH1Sum returns true if the checksum is computed by HashDirH1.
+func IsH1Sum(sum string) bool {
+	return strings.HasPrefix(sum, H1_SUM_PREFIX)
+}
+
+// SameSumAlgorithm returns true if the two checksums are computed by the same algorithm,
+// only the checksums computed by the same algorithm can be compared.
+func SameSumAlgorithm(sum, otherSum string) bool {
+	return IsH1Sum(sum) == IsH1Sum(otherSum)
+}
+
+// hashContent returns the hex encoded sha256 of the content.
+func hashContent(r io.Reader) (string, error) {
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// sumH1 computes the checksum with the prefix 'h1:' from the sha256 of the files,
+// in the same format as the 'h1:' hash of the go modules.
+func sumH1(fileSums map[string]string) string {
+	paths := make([]string, 0, len(fileSums))
+	for path := range fileSums {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	hasher := sha256.New()
+	for _, path := range paths {
+		fmt.Fprintf(hasher, "%s  %s\n", fileSums[path], path)
+	}
+	return H1_SUM_PREFIX + base64.StdEncoding.EncodeToString(hasher.Sum(nil))
+}
+
 // StoreToFile will store 'data' into toml file under 'filePath'.
 func StoreToFile(filePath string, dataStr string) error {
 	err := os.WriteFile(filePath, []byte(dataStr), 0644)
@@ -123,6 +248,13 @@ func Exists(path string) (bool, error) {
 var ignores = []string{".git", ".tar"}
 
 func TarDir(srcDir string, tarPath string, include []string, exclude []string) error {
+	return TarDirWithFiles(srcDir, tarPath, include, exclude, nil)
+}
+
+// TarDirWithFiles is the same as TarDir, except that the files in 'files',
+// indexed by the slash-separated paths relative to 'srcDir', are written with the content in 'files'
+// instead of the content in 'srcDir'.
+func TarDirWithFiles(srcDir string, tarPath string, include []string, exclude []string, files map[string][]byte) error {
 	fw, err := os.Create(tarPath)
 	if err != nil {
 		log.Fatal(err)
@@ -172,6 +304,11 @@ func TarDir(srcDir string, tarPath string, include []string, exclude []string) e
 		}
 		hdr.Name = relPath
 
+		content, replaced := files[relPath]
+		if replaced && !info.IsDir() {
+			hdr.Size = int64(len(content))
+		}
+
 		if err := tw.WriteHeader(hdr); err != nil {
 			return err
 		}
@@ -180,6 +317,11 @@ func TarDir(srcDir string, tarPath string, include []string, exclude []string) e
 			return nil
 		}
 
+		if replaced {
+			_, err := tw.Write(content)
+			return err
+		}
+
 		fr, err := os.Open(path)
 		if err != nil {
 			return err
@@ -196,6 +338,31 @@ func TarDir(srcDir string, tarPath string, include []string, exclude []string) e
 	return err
 }
 
+// ReadFileFromTar returns the content of the file 'name' in the tar,
+// 'name' is the slash-separated path in the tar. An error wrapping os.ErrNotExist is returned if the file is not found.
+func ReadFileFromTar(tarPath string, name string) ([]byte, error) {
+	file, err := os.Open(tarPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	tarReader := tar.NewReader(file)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag == tar.TypeReg && filepath.ToSlash(filepath.Clean(header.Name)) == name {
+			return io.ReadAll(tarReader)
+		}
+	}
+	return nil, fmt.Errorf("'%s' not found in '%s': %w", name, tarPath, os.ErrNotExist)
+}
+
 // UnTarDir will extract tar from 'tarPath' to 'destDir'.
 func UnTarDir(tarPath string, destDir string) error {
 	file, err := os.Open(tarPath)
@@ -567,7 +734,7 @@ func CheckPackageSum(checkedSum, localPath string) bool {
 		return false
 	}
 
-	sum, err := HashDir(localPath)
+	sum, err := HashDirAs(localPath, checkedSum)
 
 	if err != nil {
 		return false
@@ -70,6 +70,61 @@ func TestHashDir(t *testing.T) {
 	assert.Equal(t, res, "n4bQgYhMfWWaL+qgxVrQFaO/TxsrC4Is0V1sFbDwCgg=")
 }
 
+func TestHashDirH1(t *testing.T) {
+	test_path := filepath.Join(getTestDir("test_hash"), "test_hash.txt")
+	tp := TestPath{
+		FilePath: test_path,
+	}
+
+	_ = CreateFileIfNotExist(tp.FilePath, tp.TestStore)
+	res, err := HashDirH1(filepath.Dir(tp.FilePath))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, res, "h1:CDFTB+ydIlso9AGUKXRzz7PxOGYND+Rem11uxGVQ7YY=")
+
+	// The sums computed by both algorithms are verified.
+	assert.Equal(t, CheckPackageSum(res, filepath.Dir(tp.FilePath)), true)
+	assert.Equal(t, CheckPackageSum("n4bQgYhMfWWaL+qgxVrQFaO/TxsrC4Is0V1sFbDwCgg=", filepath.Dir(tp.FilePath)), true)
+	assert.Equal(t, CheckPackageSum("h1:n4bQgYhMfWWaL+qgxVrQFaO/TxsrC4Is0V1sFbDwCgg=", filepath.Dir(tp.FilePath)), false)
+}
+
+func TestHashDirH1WithPaths(t *testing.T) {
+	pkgDir := filepath.Join(t.TempDir(), "pkg")
+	assert.Equal(t, os.MkdirAll(filepath.Join(pkgDir, "sub"), 0755), nil)
+	assert.Equal(t, os.WriteFile(filepath.Join(pkgDir, "main.k"), []byte("a = 1"), 0644), nil)
+	assert.Equal(t, os.WriteFile(filepath.Join(pkgDir, "sub", "sub.k"), []byte("b = 1"), 0644), nil)
+	sum, err := HashDirH1(pkgDir)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, IsH1Sum(sum), true)
+
+	// The '.git' directory and the package tar are not included.
+	assert.Equal(t, os.MkdirAll(filepath.Join(pkgDir, ".git"), 0755), nil)
+	assert.Equal(t, os.WriteFile(filepath.Join(pkgDir, ".git", "HEAD"), []byte("ref: refs/heads/main"), 0644), nil)
+	assert.Equal(t, os.WriteFile(filepath.Join(pkgDir, "pkg_0.0.1.tar"), []byte("tar"), 0644), nil)
+	gitSum, err := HashDirH1(pkgDir)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, gitSum, sum)
+
+	// The files ignored by the algorithm before 'h1:' are included.
+	assert.Equal(t, os.WriteFile(filepath.Join(pkgDir, ".gitignore"), []byte("*.tar"), 0644), nil)
+	ignoreSum, err := HashDirH1(pkgDir)
+	assert.Equal(t, err, nil)
+	assert.NotEqual(t, ignoreSum, sum)
+	assert.Equal(t, os.Remove(filepath.Join(pkgDir, ".gitignore")), nil)
+
+	// Renaming a file changes the sum.
+	assert.Equal(t, os.Rename(filepath.Join(pkgDir, "sub", "sub.k"), filepath.Join(pkgDir, "sub", "renamed.k")), nil)
+	renamedSum, err := HashDirH1(pkgDir)
+	assert.Equal(t, err, nil)
+	assert.NotEqual(t, renamedSum, sum)
+
+	// The sum of the package tar is the same as the sum of the package.
+	tarPath := filepath.Join(t.TempDir(), "pkg.tar")
+	assert.Equal(t, TarDir(pkgDir, tarPath, []string{}, []string{}), nil)
+	tarSum, err := HashTarH1(tarPath)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, tarSum, renamedSum)
+}
+
 func TestTarDir(t *testing.T) {
 	testDir := getTestDir("test_tar")
 	tarPath := filepath.Join(testDir, "test.tar")
@@ -145,6 +200,31 @@ func TestTarDir(t *testing.T) {
 	os.Remove(tarPath)
 }
 
+func TestTarDirWithFiles(t *testing.T) {
+	testDir := getTestDir("test_tar")
+	tarPath := filepath.Join(testDir, "test_with_files.tar")
+	defer os.Remove(tarPath)
+
+	testSrcDir := filepath.Join(testDir, "test_src")
+	err := TarDirWithFiles(testSrcDir, tarPath, []string{}, []string{}, map[string][]byte{
+		"test.mod": []byte("replaced"),
+	})
+	assert.Equal(t, err, nil)
+
+	content, err := ReadFileFromTar(tarPath, "test.mod")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, string(content), "replaced")
+
+	expected, err := os.ReadFile(filepath.Join(testSrcDir, "test_tar_dir", "test_1.txt"))
+	assert.Equal(t, err, nil)
+	content, err = ReadFileFromTar(tarPath, "test_tar_dir/test_1.txt")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, string(content), string(expected))
+
+	_, err = ReadFileFromTar(tarPath, "not_exist.txt")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
 func TestUnTarDir(t *testing.T) {
 	testDir := getTestDir("test_un_tar")
 	tarPath := filepath.Join(testDir, "test.tar")
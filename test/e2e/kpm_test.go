@@ -283,15 +283,16 @@ var _ = ginkgo.Describe("Kpm CLI Testing", func() {
 				var manifest_expect v1.Manifest
 				err = json.Unmarshal(bytes, &manifest_expect)
 				gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
-				gomega.Expect(len(manifest_expect.Annotations)).To(gomega.Equal(len(manifest_got.Annotations)))
-				gomega.Expect(manifest_expect.Annotations[constants.DEFAULT_KCL_OCI_MANIFEST_NAME]).
-					To(gomega.Equal(manifest_got.Annotations[constants.DEFAULT_KCL_OCI_MANIFEST_NAME]))
-				gomega.Expect(manifest_expect.Annotations[constants.DEFAULT_KCL_OCI_MANIFEST_VERSION]).
-					To(gomega.Equal(manifest_got.Annotations[constants.DEFAULT_KCL_OCI_MANIFEST_VERSION]))
-				gomega.Expect(manifest_expect.Annotations[constants.DEFAULT_KCL_OCI_MANIFEST_DESCRIPTION]).
-					To(gomega.Equal(manifest_got.Annotations[constants.DEFAULT_KCL_OCI_MANIFEST_DESCRIPTION]))
-				gomega.Expect(manifest_expect.Annotations[constants.DEFAULT_KCL_OCI_MANIFEST_SUM]).
-					To(gomega.Equal(manifest_got.Annotations[constants.DEFAULT_KCL_OCI_MANIFEST_SUM]))
+				// kcl.mod and kcl.mod.lock are pushed as the config blob of the package.
+				gomega.Expect(manifest_got.Config.MediaType).To(gomega.Equal(constants.DEFAULT_KCL_OCI_CONFIG_MEDIA_TYPE))
+				// The annotations expected are checked by the keys, the time the package is created is different.
+				for key, value := range manifest_expect.Annotations {
+					if key == constants.DEFAULT_CREATE_OCI_MANIFEST_TIME {
+						gomega.Expect(manifest_got.Annotations).To(gomega.HaveKey(key))
+						continue
+					}
+					gomega.Expect(manifest_got.Annotations).To(gomega.HaveKeyWithValue(key, value))
+				}
 			})
 
 			ginkgo.It("testing 'fetch api '", func() {
@@ -309,11 +310,14 @@ var _ = ginkgo.Describe("Kpm CLI Testing", func() {
 				var manifest_expect v1.Manifest
 				err = json.Unmarshal([]byte(jsonstr), &manifest_expect)
 				gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
-				gomega.Expect(len(manifest_expect.Annotations)).To(gomega.Equal(5))
 				gomega.Expect(manifest_expect.Annotations[constants.DEFAULT_KCL_OCI_MANIFEST_NAME]).To(gomega.Equal("kcl2"))
 				gomega.Expect(manifest_expect.Annotations[constants.DEFAULT_KCL_OCI_MANIFEST_VERSION]).To(gomega.Equal("0.0.1"))
 				gomega.Expect(manifest_expect.Annotations[constants.DEFAULT_KCL_OCI_MANIFEST_DESCRIPTION]).To(gomega.Equal("This is the kcl package named kcl2"))
+				gomega.Expect(manifest_expect.Annotations[constants.OCI_MANIFEST_TITLE]).To(gomega.Equal("kcl2"))
+				gomega.Expect(manifest_expect.Annotations[constants.OCI_MANIFEST_VERSION]).To(gomega.Equal("0.0.1"))
+				gomega.Expect(manifest_expect.Annotations[constants.OCI_MANIFEST_DESCRIPTION]).To(gomega.Equal("This is the kcl package named kcl2"))
 				gomega.Expect(manifest_expect.Annotations[constants.DEFAULT_KCL_OCI_MANIFEST_SUM]).To(gomega.Equal("Y/QXruiaxcJcmOnKWl4UEFuUqKTtbi4jTTeuEjeGV8s="))
+				gomega.Expect(manifest_expect.Annotations[constants.DEFAULT_KCL_OCI_MANIFEST_SUM_H1]).To(gomega.Equal("h1:awowEWQ3cJdNuVITNFe7hrUDlEDhvHHJuBHOexLIAB0="))
 			})
 		}
 	})